@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/service"
+	"github.com/Shishlyannikovvv/project-avito/internal/snapshot"
+)
+
+const usage = "usage: app [org create <name> | snapshot export [--anonymize] [file] | snapshot import [file]]"
+
+// runCommand выполняет команду администрирования вместо запуска сервера
+func runCommand(ctx context.Context, manager *service.Manager, args []string) error {
+	switch {
+	case len(args) >= 3 && args[0] == "org" && args[1] == "create":
+		org, apiKey, err := manager.CreateOrganization(ctx, strings.Join(args[2:], " "))
+		if err != nil {
+			return fmt.Errorf("failed to create organization: %w", err)
+		}
+		// Ключ хранится только в виде хеша - показать его повторно нельзя
+		fmt.Printf("organization_id: %d\napi_key: %s\n", org.ID, apiKey)
+		return nil
+	case len(args) >= 2 && args[0] == "snapshot" && args[1] == "export":
+		rest := args[2:]
+		anonymize := len(rest) > 0 && rest[0] == "--anonymize"
+		if anonymize {
+			rest = rest[1:]
+		}
+		if len(rest) > 1 {
+			return errors.New(usage)
+		}
+		return exportSnapshot(ctx, manager, fileArg(rest), anonymize)
+	case len(args) >= 2 && len(args) <= 3 && args[0] == "snapshot" && args[1] == "import":
+		return importSnapshot(ctx, manager, fileArg(args[2:]))
+	default:
+		return errors.New(usage)
+	}
+}
+
+// fileArg - путь к файлу снимка; "-" или его отсутствие - stdin/stdout
+func fileArg(args []string) string {
+	if len(args) == 0 {
+		return "-"
+	}
+	return args[0]
+}
+
+func exportSnapshot(ctx context.Context, manager *service.Manager, path string, anonymize bool) error {
+	snap, err := manager.ExportSnapshot(ctx, anonymize)
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	out := os.Stdout
+	if path != "-" {
+		if out, err = os.Create(path); err != nil {
+			return err
+		}
+	}
+	err = snapshot.Write(out, snap, time.Now())
+	if path != "-" {
+		// Ошибка закрытия файла означает, что снимок мог не записаться целиком
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	// Сводка - в stderr, чтобы не смешиваться со снимком в stdout
+	fmt.Fprintf(os.Stderr, "exported %d teams, %d users, %d pull requests\n",
+		len(snap.Teams), len(snap.Users), len(snap.PullRequests))
+	return nil
+}
+
+func importSnapshot(ctx context.Context, manager *service.Manager, path string) error {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	snap, err := snapshot.Read(r)
+	if err != nil {
+		return err
+	}
+	if err := manager.ImportSnapshot(ctx, snap); err != nil {
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+	fmt.Printf("restored %d teams, %d users, %d pull requests\n", len(snap.Teams), len(snap.Users), len(snap.PullRequests))
+	return nil
+}
@@ -3,13 +3,33 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/Shishlyannikovvv/project-avito/internal/api"
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/Shishlyannikovvv/project-avito/internal/gql"
+	"github.com/Shishlyannikovvv/project-avito/internal/grpcapi"
+	"github.com/Shishlyannikovvv/project-avito/internal/notify"
+	"github.com/Shishlyannikovvv/project-avito/internal/ratelimit"
+	"github.com/Shishlyannikovvv/project-avito/internal/scheduler"
 	"github.com/Shishlyannikovvv/project-avito/internal/service"
 	"github.com/Shishlyannikovvv/project-avito/internal/storage"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
 )
 
+// Ключ advisory-блокировки Postgres для выбора реплики, выполняющей периодические задачи
+const schedulerLockKey = 7_311_001
+
+// Сколько хранить журнал событий (столько клиент потока событий может быть отключен без потерь)
+const eventRetention = 7 * 24 * time.Hour
+
+// Сколько хранить ответы на запросы с Idempotency-Key по умолчанию
+const defaultIdempotencyTTL = 24 * time.Hour
+
 func main() {
 	// --- Конфигурация из переменных окружения (для Docker) ---
 	dbHost := os.Getenv("DB_HOST")
@@ -18,13 +38,36 @@ func main() {
 	dbName := os.Getenv("DB_NAME")
 	dbPort := os.Getenv("DB_PORT")
 	serverPort := "8080" // Требование ТЗ
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "9090"
+	}
+	// AUTH_REQUIRED=true - запросы без API-ключа организации отклоняются (401).
+	// По умолчанию (false) каждый запрос без ключа работает с организацией domain.DefaultOrgID:
+	// это режим установки с одним арендатором, при нескольких организациях его нужно включить.
+	authRequired := os.Getenv("AUTH_REQUIRED") == "true"
+	if !authRequired {
+		log.Printf("AUTH_REQUIRED is not set: requests without API key use organization %d", domain.DefaultOrgID)
+	}
+	// Токен администратора установки (организации, резервные копии); не задан - админские маршруты отключены
+	adminToken := os.Getenv("ADMIN_TOKEN")
+	idempotencyTTL, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_TTL"))
+	if err != nil {
+		idempotencyTTL = defaultIdempotencyTTL
+	}
+	// Лимиты запросов: RATE_LIMIT_DEFAULT="100/s", RATE_LIMIT_ROUTES="POST /api/v1/prs=10/s; ..."
+	rateLimits, err := ratelimit.ParseConfig(os.Getenv("RATE_LIMIT_DEFAULT"), os.Getenv("RATE_LIMIT_ROUTES"))
+	if err != nil {
+		log.Fatalf("Invalid rate limit config: %v", err)
+	}
 
 	if dbHost == "" {
 		// Заглушка для локального запуска без Docker-Compose, если нужно
 		log.Fatal("DB_HOST environment variable not set. Please run via docker-compose.")
 	}
 
-	ctx := context.Background()
+	// Фоновые задачи обходят все организации
+	ctx := domain.WithAllOrgs(context.Background())
 
 	// 1. Storage Layer (Подключение к БД)
 	db, err := storage.NewPostgresDB(dbHost, dbUser, dbPassword, dbName, dbPort)
@@ -37,9 +80,117 @@ func main() {
 	// 2. Service Layer (Бизнес-логика)
 	manager := service.NewManager(repo)
 
-	// 3. API Layer (HTTP)
+	// Обезличенные снимки (snapshot export --anonymize, ?anonymize=true): секрет псевдонимов.
+	// Тот же ключ дает те же псевдонимы, поэтому стенд можно обновлять, не теряя соответствия.
+	manager.SetAnonymizationKey([]byte(os.Getenv("SNAPSHOT_ANONYMIZATION_KEY")))
+
+	// Команды администрирования: app org create <name>, app snapshot export|import [file]
+	if len(os.Args) > 1 {
+		if err := runCommand(ctx, manager, os.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Уведомления рассылаются в фоне и не замедляют API.
+	// В чат пишем командам с настроенным вебхуком, по почте - если задан SMTP_HOST.
+	chat := notify.NewChatNotifier(repo, nil)
+	notifiers := []notify.Notifier{chat}
+	if smtpHost := os.Getenv("SMTP_HOST"); smtpHost != "" {
+		smtpPort, err := strconv.Atoi(os.Getenv("SMTP_PORT"))
+		if err != nil {
+			smtpPort = 587
+		}
+		mailer := notify.NewSMTPMailer(notify.SMTPConfig{
+			Host:     smtpHost,
+			Port:     smtpPort,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+			StartTLS: os.Getenv("SMTP_STARTTLS") == "true",
+		})
+		notifiers = append(notifiers, notify.NewEmailNotifier(repo, mailer))
+	}
+	dispatcher := notify.NewDispatcher(repo, 1000, notifiers...)
+	manager.SetEventPublisher(dispatcher)
+	go dispatcher.Run(ctx, 4)
+
+	// Назначение ревьюеров PR, ожидающим в очереди
+	go manager.RunReviewerQueue(ctx, 30*time.Second)
+
+	// Периодические задачи выполняет только одна реплика - держатель advisory-блокировки
+	sched := scheduler.New(storage.NewAdvisoryLeader(db, schedulerLockKey))
+	// Переназначение ревью тех, у кого начался отпуск
+	if err := sched.Add("unavailability-reassign", "* * * * *", manager.ReassignStartedUnavailability); err != nil {
+		log.Fatalf("Failed to schedule job: %v", err)
+	}
+	// Напоминания и эскалация зависших ревью
+	if err := sched.Add("stale-reviews", "*/10 * * * *", manager.ProcessStaleReviews); err != nil {
+		log.Fatalf("Failed to schedule job: %v", err)
+	}
+	// Ежедневный дайджест открытых PR в чаты команд
+	if err := sched.Add("chat-digest", "0 9 * * *", chat.SendDigest); err != nil {
+		log.Fatalf("Failed to schedule job: %v", err)
+	}
+	// Очистка старых событий журнала
+	purgeEvents := func(ctx context.Context) error { return manager.PurgeEvents(ctx, eventRetention) }
+	if err := sched.Add("events-retention", "30 3 * * *", purgeEvents); err != nil {
+		log.Fatalf("Failed to schedule job: %v", err)
+	}
+	// Очистка истекших ключей идемпотентности
+	purgeIdempotencyKeys := func(ctx context.Context) error {
+		_, err := repo.DeleteExpiredIdempotencyRecords(ctx, time.Now())
+		return err
+	}
+	if err := sched.Add("idempotency-retention", "15 * * * *", purgeIdempotencyKeys); err != nil {
+		log.Fatalf("Failed to schedule job: %v", err)
+	}
+	// Корзины лимитов: в памяти реплики или общие в Postgres (RATE_LIMIT_STORE=postgres)
+	var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if os.Getenv("RATE_LIMIT_STORE") == "postgres" {
+		sharedStore := storage.NewRateLimitStore(db)
+		rateLimitStore = sharedStore
+		purgeBuckets := func(ctx context.Context) error {
+			_, err := sharedStore.DeleteIdle(ctx, time.Now().Add(-time.Hour))
+			return err
+		}
+		if err := sched.Add("rate-limit-cleanup", "*/10 * * * *", purgeBuckets); err != nil {
+			log.Fatalf("Failed to schedule job: %v", err)
+		}
+	}
+	go sched.Run(ctx)
+
+	// 3. API Layer: gRPC на отдельном порту, поверх того же Manager
+	grpcListener, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		log.Fatalf("Failed to listen on gRPC port: %v", err)
+	}
+	grpcServer := grpcapi.NewGRPCServer(manager, grpc.ChainUnaryInterceptor(grpcapi.AuthInterceptor(manager, authRequired)))
+	go func() {
+		log.Printf("Starting gRPC server on :%s", grpcPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Fatalf("gRPC server failed: %v", err)
+		}
+	}()
+
+	// HTTP
 	handler := api.NewHandler(manager)
-	router := api.SetupRouter(handler)
+	// API-ключ определяет организацию запроса; лимиты считаются по ней, поэтому проверяются после ключа.
+	// Повтор POST-запроса с тем же Idempotency-Key возвращает сохраненный ответ.
+	middleware := []gin.HandlerFunc{
+		api.Authenticate(manager, authRequired),
+		api.RateLimit(ratelimit.NewLimiter(rateLimitStore, rateLimits)),
+		api.Idempotency(repo, idempotencyTTL),
+	}
+	router := api.SetupRouter(handler, middleware...)
+
+	// GraphQL: чтение через пакетные загрузчики поверх репозитория, мутации через Manager
+	graphqlHandler, err := gql.NewHandler(manager, repo, gql.DefaultConfig())
+	if err != nil {
+		log.Fatalf("Failed to build GraphQL schema: %v", err)
+	}
+	api.SetupGraphQLRoute(router, graphqlHandler.Serve, middleware...)
+	api.SetupAdminRoutes(router, handler, adminToken)
 
 	// Запуск сервера
 	log.Printf("Starting server on :%s", serverPort)
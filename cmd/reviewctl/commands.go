@@ -0,0 +1,711 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+var commands = map[string]command{
+	"teams create":  {"teams create <name> [-parent id]", teamsCreate},
+	"teams get":     {"teams get <id>", teamsGet},
+	"teams rename":  {"teams rename <id> <name>", teamsRename},
+	"teams archive": {"teams archive <id> [-strategy reassign|close]", teamsArchive},
+	"teams members": {"teams members <id>", teamsMembers},
+
+	"users create":     {"users create <name> -team id", usersCreate},
+	"users update":     {"users update <id> [-name name] [-team id]", usersUpdate},
+	"users activate":   {"users activate <id>", usersActivate},
+	"users deactivate": {"users deactivate <id> [-hard]", usersDeactivate},
+	"users teams":      {"users teams <id>", usersTeams},
+
+	"prs create":  {"prs create <title> -author id [-file path]... [-label name]...", prsCreate},
+	"prs get":     {"prs get <id>", prsGet},
+	"prs merge":   {"prs merge <id>", prsMerge},
+	"prs reroll":  {"prs reroll <id> <reviewer-id> [-to user-id]", prsReroll},
+	"prs list":    {"prs list -reviewer id", prsList},
+	"prs history": {"prs history <id>", prsHistory},
+
+	"stats": {"stats [-team id]", stats},
+
+	"tokens create": {"tokens create <organization-name>  (admin)", tokensCreate},
+
+	"import": {"import <file.csv|file.json> [-dry-run]", importDirectory},
+	"export": {"export [-format csv|json] [-file path]", exportDirectory},
+
+	"snapshot export": {"snapshot export [-anonymize] [-file path]  (admin)", snapshotExport},
+	"snapshot import": {"snapshot import <file>  (admin)", snapshotImport},
+
+	"profiles list": {"profiles list", profilesList},
+	"profiles set":  {"profiles set <name> [-url url] [-api-key key] [-admin-token token] [-o format]", profilesSet},
+	"profiles use":  {"profiles use <name>", profilesUse},
+}
+
+// --- Teams ---
+
+func teamsCreate(e *env, args []string) error {
+	fs := e.flagSet("teams create")
+	parent := fs.Int("parent", 0, "parent team ID")
+	args, err := e.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	var parentID *int
+	if *parent != 0 {
+		parentID = parent
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	team, err := c.CreateTeam(e.ctx, args[0], parentID)
+	if err != nil {
+		return err
+	}
+	return e.render(team, func() *table { return teamsTable(*team) })
+}
+
+func teamsGet(e *env, args []string) error {
+	id, err := e.parseID("teams get", args)
+	if err != nil {
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	team, err := c.GetTeam(e.ctx, id)
+	if err != nil {
+		return err
+	}
+	return e.render(team, func() *table { return teamsTable(*team) })
+}
+
+func teamsRename(e *env, args []string) error {
+	args, err := e.parse(e.flagSet("teams rename"), args, 2)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	team, err := c.RenameTeam(e.ctx, id, args[1])
+	if err != nil {
+		return err
+	}
+	return e.render(team, func() *table { return teamsTable(*team) })
+}
+
+func teamsArchive(e *env, args []string) error {
+	fs := e.flagSet("teams archive")
+	strategy := fs.String("strategy", domain.ArchiveReassign, "open PRs: reassign or close")
+	args, err := e.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	team, err := c.ArchiveTeam(e.ctx, id, *strategy)
+	if err != nil {
+		return err
+	}
+	return e.render(team, func() *table { return teamsTable(*team) })
+}
+
+func teamsMembers(e *env, args []string) error {
+	id, err := e.parseID("teams members", args)
+	if err != nil {
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	members, err := c.GetTeamMembers(e.ctx, id)
+	if err != nil {
+		return err
+	}
+	return e.render(members, func() *table { return membershipsTable(members) })
+}
+
+// --- Users ---
+
+func usersCreate(e *env, args []string) error {
+	fs := e.flagSet("users create")
+	teamID := fs.Int("team", 0, "primary team ID (required)")
+	args, err := e.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if *teamID == 0 {
+		return usageErrorf("-team is required")
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	user, err := c.CreateUser(e.ctx, args[0], *teamID)
+	if err != nil {
+		return err
+	}
+	return e.render(user, func() *table { return usersTable(*user) })
+}
+
+func usersUpdate(e *env, args []string) error {
+	fs := e.flagSet("users update")
+	name := fs.String("name", "", "new name")
+	teamID := fs.Int("team", 0, "transfer to this primary team")
+	args, err := e.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	var update domain.UserUpdate
+	if *name != "" {
+		update.Name = name
+	}
+	if *teamID != 0 {
+		update.TeamID = teamID
+	}
+	if update.Name == nil && update.TeamID == nil {
+		return usageErrorf("nothing to update: pass -name or -team")
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	user, err := c.UpdateUser(e.ctx, id, update)
+	if err != nil {
+		return err
+	}
+	return e.render(user, func() *table { return usersTable(*user) })
+}
+
+func usersActivate(e *env, args []string) error {
+	id, err := e.parseID("users activate", args)
+	if err != nil {
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	user, err := c.ActivateUser(e.ctx, id)
+	if err != nil {
+		return err
+	}
+	return e.render(user, func() *table { return usersTable(*user) })
+}
+
+func usersDeactivate(e *env, args []string) error {
+	fs := e.flagSet("users deactivate")
+	hard := fs.Bool("hard", false, "delete and anonymize instead of deactivating")
+	args, err := e.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	if *hard {
+		err = c.HardDeleteUser(e.ctx, id)
+	} else {
+		err = c.DeactivateUser(e.ctx, id)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stderr, "user %d deactivated\n", id)
+	return nil
+}
+
+func usersTeams(e *env, args []string) error {
+	id, err := e.parseID("users teams", args)
+	if err != nil {
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	memberships, err := c.GetUserTeams(e.ctx, id)
+	if err != nil {
+		return err
+	}
+	return e.render(memberships, func() *table { return membershipsTable(memberships) })
+}
+
+// --- Pull Requests ---
+
+func prsCreate(e *env, args []string) error {
+	fs := e.flagSet("prs create")
+	authorID := fs.Int("author", 0, "author user ID (required)")
+	var files, labels stringList
+	fs.Var(&files, "file", "changed file (repeatable)")
+	fs.Var(&labels, "label", "label (repeatable)")
+	args, err := e.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if *authorID == 0 {
+		return usageErrorf("-author is required")
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	pr, err := c.CreatePR(e.ctx, args[0], *authorID, files, labels)
+	if err != nil {
+		return err
+	}
+	return e.render(pr, func() *table { return prsTable(*pr) })
+}
+
+func prsGet(e *env, args []string) error {
+	id, err := e.parseID("prs get", args)
+	if err != nil {
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	pr, err := c.GetPR(e.ctx, id)
+	if err != nil {
+		return err
+	}
+	return e.render(pr, func() *table { return prsTable(*pr) })
+}
+
+func prsMerge(e *env, args []string) error {
+	id, err := e.parseID("prs merge", args)
+	if err != nil {
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	pr, err := c.MergePR(e.ctx, id)
+	if err != nil {
+		return err
+	}
+	return e.render(pr, func() *table { return prsTable(*pr) })
+}
+
+func prsReroll(e *env, args []string) error {
+	fs := e.flagSet("prs reroll")
+	to := fs.Int("to", 0, "specific replacement reviewer (default: pick automatically)")
+	args, err := e.parse(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	oldID, err := parseID(args[1])
+	if err != nil {
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	var pr *domain.PullRequest
+	if *to != 0 {
+		pr, err = c.ReplaceReviewer(e.ctx, id, oldID, *to)
+	} else {
+		pr, err = c.RerollReviewer(e.ctx, id, oldID)
+	}
+	if err != nil {
+		return err
+	}
+	return e.render(pr, func() *table { return prsTable(*pr) })
+}
+
+func prsList(e *env, args []string) error {
+	fs := e.flagSet("prs list")
+	reviewer := fs.Int("reviewer", 0, "reviewer user ID (required)")
+	if _, err := e.parse(fs, args, 0); err != nil {
+		return err
+	}
+	if *reviewer == 0 {
+		return usageErrorf("-reviewer is required")
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	prs, err := c.GetReviewerPRs(e.ctx, *reviewer)
+	if err != nil {
+		return err
+	}
+	return e.render(prs, func() *table { return prsTable(prs...) })
+}
+
+func prsHistory(e *env, args []string) error {
+	id, err := e.parseID("prs history", args)
+	if err != nil {
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	history, err := c.GetPRHistory(e.ctx, id)
+	if err != nil {
+		return err
+	}
+	return e.render(history, func() *table {
+		t := newTable("TIME", "EVENT", "USER", "DETAILS")
+		for _, h := range history {
+			t.add(h.CreatedAt.Format("2006-01-02 15:04:05"), h.Event, h.UserID, h.Details)
+		}
+		return t
+	})
+}
+
+// --- Stats ---
+
+func stats(e *env, args []string) error {
+	fs := e.flagSet("stats")
+	teamID := fs.Int("team", 0, "only reviewers from this team's subtree")
+	if _, err := e.parse(fs, args, 0); err != nil {
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	var counts map[int]int
+	if *teamID != 0 {
+		counts, err = c.GetTeamReviewerStats(e.ctx, *teamID)
+	} else {
+		counts, err = c.GetReviewerStats(e.ctx)
+	}
+	if err != nil {
+		return err
+	}
+	return e.render(counts, func() *table {
+		ids := make([]int, 0, len(counts))
+		for id := range counts {
+			ids = append(ids, id)
+		}
+		sort.Ints(ids)
+		t := newTable("USER", "ASSIGNED")
+		for _, id := range ids {
+			t.add(id, counts[id])
+		}
+		return t
+	})
+}
+
+// --- Tokens ---
+
+func tokensCreate(e *env, args []string) error {
+	args, err := e.parse(e.flagSet("tokens create"), args, -1)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return usageErrorf("organization name is required")
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	org, apiKey, err := c.CreateOrganization(e.ctx, strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	// Ключ показывается один раз - повторно его не получить
+	resp := struct {
+		Organization *domain.Organization `json:"organization"`
+		APIKey       string               `json:"api_key"`
+	}{org, apiKey}
+	return e.render(resp, func() *table {
+		t := newTable("ORG ID", "NAME", "API KEY")
+		t.add(org.ID, org.Name, apiKey)
+		return t
+	})
+}
+
+// --- Import/Export ---
+
+func importDirectory(e *env, args []string) error {
+	fs := e.flagSet("import")
+	dryRun := fs.Bool("dry-run", false, "only report what would change")
+	args, err := e.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	var report *domain.ImportReport
+	if strings.EqualFold(filepath.Ext(args[0]), ".csv") {
+		report, err = c.ImportDirectoryCSV(e.ctx, f, *dryRun)
+	} else {
+		var records []domain.DirectoryRecord
+		if decodeErr := json.NewDecoder(f).Decode(&records); decodeErr != nil {
+			return fmt.Errorf("invalid %s: %w", args[0], decodeErr)
+		}
+		report, err = c.ImportDirectory(e.ctx, records, *dryRun)
+	}
+	if report == nil {
+		return err
+	}
+	if renderErr := e.render(report, func() *table { return importTable(*report) }); renderErr != nil {
+		return renderErr
+	}
+	// С ошибками в строках приходит и отчет, и ошибка 422
+	if err != nil {
+		return fmt.Errorf("%w: %d row(s) failed, nothing applied", err, report.Failed)
+	}
+	fmt.Fprintf(e.stderr, "created %d, updated %d, unchanged %d (applied: %t)\n",
+		report.Created, report.Updated, report.Unchanged, report.Applied)
+	return nil
+}
+
+func exportDirectory(e *env, args []string) error {
+	fs := e.flagSet("export")
+	format := fs.String("format", "csv", "csv or json")
+	file := fs.String("file", "", "write to file instead of stdout")
+	if _, err := e.parse(fs, args, 0); err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "json" {
+		return usageErrorf("unknown export format %q (csv, json)", *format)
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	return e.writeTo(*file, func(w io.Writer) error {
+		if *format == "csv" {
+			return c.ExportDirectoryCSV(e.ctx, w)
+		}
+		records, err := c.ExportDirectory(e.ctx)
+		if err != nil {
+			return err
+		}
+		return render(w, outputJSON, records, nil)
+	})
+}
+
+func snapshotExport(e *env, args []string) error {
+	fs := e.flagSet("snapshot export")
+	anonymize := fs.Bool("anonymize", false, "replace personal data with pseudonyms")
+	file := fs.String("file", "", "write to file instead of stdout")
+	if _, err := e.parse(fs, args, 0); err != nil {
+		return err
+	}
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+	return e.writeTo(*file, func(w io.Writer) error {
+		return c.ExportSnapshot(e.ctx, w, *anonymize)
+	})
+}
+
+func snapshotImport(e *env, args []string) error {
+	args, err := e.parse(e.flagSet("snapshot import"), args, 1)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	c, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	counts, err := c.ImportSnapshot(e.ctx, f)
+	if err != nil {
+		return err
+	}
+	return e.render(counts, func() *table {
+		t := newTable("RESTORED", "COUNT")
+		for _, kind := range []string{"organizations", "teams", "users", "pull_requests"} {
+			t.add(kind, counts[kind])
+		}
+		return t
+	})
+}
+
+// --- Profiles ---
+
+func profilesList(e *env, args []string) error {
+	if _, err := e.parse(e.flagSet("profiles list"), args, 0); err != nil {
+		return err
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	// Ключи не выводим
+	type view struct {
+		Name    string `json:"name"`
+		URL     string `json:"url"`
+		Current bool   `json:"current"`
+	}
+	views := make([]view, 0, len(names))
+	for _, name := range names {
+		views = append(views, view{Name: name, URL: cfg.Profiles[name].URL, Current: name == cfg.CurrentProfile})
+	}
+	return e.render(views, func() *table {
+		t := newTable("CURRENT", "NAME", "URL")
+		for _, v := range views {
+			current := ""
+			if v.Current {
+				current = "*"
+			}
+			t.add(current, v.Name, v.URL)
+		}
+		return t
+	})
+}
+
+func profilesSet(e *env, args []string) error {
+	fs := e.flagSet("profiles set")
+	args, err := e.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if e.opts.output != "" && e.opts.output != outputTable && e.opts.output != outputJSON && e.opts.output != outputYAML {
+		return usageErrorf("unknown output format %q", e.opts.output)
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	p := cfg.Profiles[args[0]]
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	set(&p.URL, e.opts.url)
+	set(&p.APIKey, e.opts.apiKey)
+	set(&p.AdminToken, e.opts.adminToken)
+	set(&p.Output, e.opts.output)
+	cfg.Profiles[args[0]] = p
+	// Первый профиль сразу становится текущим
+	if cfg.CurrentProfile == "" {
+		cfg.CurrentProfile = args[0]
+	}
+	if err := cfg.save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stderr, "profile %q saved\n", args[0])
+	return nil
+}
+
+func profilesUse(e *env, args []string) error {
+	args, err := e.parse(e.flagSet("profiles use"), args, 1)
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if _, ok := cfg.Profiles[args[0]]; !ok {
+		return usageErrorf("unknown profile %q", args[0])
+	}
+	cfg.CurrentProfile = args[0]
+	if err := cfg.save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stderr, "using profile %q\n", args[0])
+	return nil
+}
+
+// --- Helpers ---
+
+// writeTo пишет вывод write в файл (пусто - в stdout); недописанный файл удаляется
+func (e *env) writeTo(file string, write func(w io.Writer) error) error {
+	if file == "" {
+		return write(e.stdout)
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		os.Remove(file)
+		return err
+	}
+	return f.Close()
+}
+
+// parseID разбирает единственный аргумент - числовой ID
+func (e *env) parseID(name string, args []string) (int, error) {
+	args, err := e.parse(e.flagSet(name), args, 1)
+	if err != nil {
+		return 0, err
+	}
+	return parseID(args[0])
+}
+
+func parseID(s string) (int, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, usageErrorf("invalid ID %q", s)
+	}
+	return id, nil
+}
+
+// stringList - повторяемый строковый флаг
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// profile - параметры подключения к одной установке сервиса
+type profile struct {
+	URL        string `yaml:"url"`
+	APIKey     string `yaml:"api_key,omitempty"`
+	AdminToken string `yaml:"admin_token,omitempty"`
+	// Формат вывода по умолчанию: table | json | yaml
+	Output string `yaml:"output,omitempty"`
+}
+
+// config - файл профилей (по умолчанию ~/.config/reviewctl/config.yaml, путь меняет REVIEWCTL_CONFIG)
+type config struct {
+	CurrentProfile string             `yaml:"current_profile"`
+	Profiles       map[string]profile `yaml:"profiles"`
+}
+
+const defaultURL = "http://localhost:8080"
+
+func configPath() (string, error) {
+	if path := os.Getenv("REVIEWCTL_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "reviewctl", "config.yaml"), nil
+}
+
+// loadConfig читает файл профилей; отсутствующий файл - пустая конфигурация
+func loadConfig() (*config, error) {
+	cfg := &config{Profiles: make(map[string]profile)}
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string]profile)
+	}
+	return cfg, nil
+}
+
+func (c *config) save() error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	// В файле ключи доступа - читать его может только владелец
+	return os.WriteFile(path, data, 0o600)
+}
+
+// resolve собирает параметры подключения: профиль (явный, иначе текущий), поверх него
+// переменные окружения REVIEWCTL_URL / REVIEWCTL_API_KEY / REVIEWCTL_ADMIN_TOKEN, поверх них - флаги
+func (c *config) resolve(opts *options) (profile, error) {
+	name := opts.profile
+	if name == "" {
+		name = os.Getenv("REVIEWCTL_PROFILE")
+	}
+	if name == "" {
+		name = c.CurrentProfile
+	}
+
+	var p profile
+	if name != "" {
+		var ok bool
+		if p, ok = c.Profiles[name]; !ok {
+			return p, usageErrorf("unknown profile %q", name)
+		}
+	}
+	override := func(dst *string, env, flag string) {
+		if v := os.Getenv(env); v != "" {
+			*dst = v
+		}
+		if flag != "" {
+			*dst = flag
+		}
+	}
+	override(&p.URL, "REVIEWCTL_URL", opts.url)
+	override(&p.APIKey, "REVIEWCTL_API_KEY", opts.apiKey)
+	override(&p.AdminToken, "REVIEWCTL_ADMIN_TOKEN", opts.adminToken)
+	override(&p.Output, "REVIEWCTL_OUTPUT", opts.output)
+	if p.URL == "" {
+		p.URL = defaultURL
+	}
+	if p.Output == "" {
+		p.Output = outputTable
+	}
+	return p, nil
+}
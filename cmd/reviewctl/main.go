@@ -0,0 +1,230 @@
+// reviewctl - CLI администрирования сервиса назначения ревьюеров поверх REST API.
+//
+//	reviewctl [-profile name] [-o table|json|yaml] <command> [args] [flags]
+//
+// Параметры подключения берутся из профиля (reviewctl profiles set/use), переменных окружения
+// REVIEWCTL_URL / REVIEWCTL_API_KEY / REVIEWCTL_ADMIN_TOKEN или флагов -url / -api-key / -admin-token.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Shishlyannikovvv/project-avito/pkg/client"
+)
+
+// Коды завершения
+const (
+	exitOK    = 0
+	exitError = 1 // сеть, ошибка сервера и прочее
+	exitUsage = 2 // неверные аргументы
+	// Ошибки API по классам ответа
+	exitNotFound    = 3 // 404
+	exitConflict    = 4 // 409
+	exitInvalid     = 5 // 400, 422
+	exitAuth        = 6 // 401, 403
+	exitRateLimited = 7 // 429
+)
+
+// options - общие флаги всех команд
+type options struct {
+	profile    string
+	url        string
+	apiKey     string
+	adminToken string
+	output     string
+}
+
+// env - окружение выполнения команды
+type env struct {
+	ctx            context.Context
+	opts           options
+	stdin          io.Reader
+	stdout, stderr io.Writer
+	// Подменяется в тестах
+	httpClient *http.Client
+	// Формат вывода (определяется вместе с профилем)
+	format string
+}
+
+type command struct {
+	usage string
+	run   func(e *env, args []string) error
+}
+
+func main() {
+	e := &env{ctx: context.Background(), stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+	os.Exit(run(e, os.Args[1:]))
+}
+
+// run выполняет команду и возвращает код завершения
+func run(e *env, args []string) int {
+	root := e.flagSet("reviewctl")
+	root.Usage = func() { printUsage(e.stderr) }
+	if err := root.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	args = root.Args()
+	if len(args) == 0 || args[0] == "help" {
+		printUsage(e.stderr)
+		return exitUsage
+	}
+
+	name, cmd, ok := lookup(args)
+	if !ok {
+		fmt.Fprintf(e.stderr, "reviewctl: unknown command %q\n\n", strings.Join(args, " "))
+		printUsage(e.stderr)
+		return exitUsage
+	}
+
+	err := cmd.run(e, args[len(strings.Fields(name)):])
+	if err == nil {
+		return exitOK
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	fmt.Fprintf(e.stderr, "reviewctl: %v\n", err)
+	var uerr *usageError
+	if errors.As(err, &uerr) {
+		fmt.Fprintf(e.stderr, "usage: reviewctl %s\n", cmd.usage)
+	}
+	return exitCode(err)
+}
+
+// lookup находит команду из двух слов ("teams create"), затем из одного ("stats")
+func lookup(args []string) (string, command, bool) {
+	if len(args) >= 2 {
+		name := args[0] + " " + args[1]
+		if cmd, ok := commands[name]; ok {
+			return name, cmd, true
+		}
+	}
+	cmd, ok := commands[args[0]]
+	return args[0], cmd, ok
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: reviewctl [-profile name] [-o table|json|yaml] <command> [args]")
+	fmt.Fprintln(w, "\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\n", commands[name].usage)
+	}
+}
+
+// exitCode сопоставляет ошибку коду завершения
+func exitCode(err error) int {
+	var uerr *usageError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.As(err, &uerr):
+		return exitUsage
+	case client.StatusCode(err) != 0:
+		switch client.StatusCode(err) {
+		case http.StatusNotFound:
+			return exitNotFound
+		case http.StatusConflict:
+			return exitConflict
+		case http.StatusBadRequest, http.StatusUnprocessableEntity:
+			return exitInvalid
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return exitAuth
+		case http.StatusTooManyRequests:
+			return exitRateLimited
+		}
+	}
+	return exitError
+}
+
+// usageError - неверный вызов команды
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string { return e.msg }
+
+func usageErrorf(format string, args ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// flagSet создает набор флагов команды вместе с общими флагами
+func (e *env) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.StringVar(&e.opts.profile, "profile", e.opts.profile, "profile from the config file")
+	fs.StringVar(&e.opts.url, "url", e.opts.url, "service URL, e.g. http://localhost:8080")
+	fs.StringVar(&e.opts.apiKey, "api-key", e.opts.apiKey, "organization API key")
+	fs.StringVar(&e.opts.adminToken, "admin-token", e.opts.adminToken, "installation admin token")
+	fs.StringVar(&e.opts.output, "o", e.opts.output, "output format: table, json or yaml")
+	return fs
+}
+
+// parse разбирает флаги вперемешку с позиционными аргументами и проверяет их число (n < 0 - любое)
+func (e *env) parse(fs *flag.FlagSet, args []string, n int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, usageErrorf("%v", err)
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if n >= 0 && len(positional) != n {
+		return nil, usageErrorf("expected %d argument(s), got %d", n, len(positional))
+	}
+	return positional, nil
+}
+
+// client возвращает клиент API для выбранного профиля
+func (e *env) client() (*client.Client, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	p, err := cfg.resolve(&e.opts)
+	if err != nil {
+		return nil, err
+	}
+	e.format = p.Output
+	return client.New(client.Config{
+		BaseURL:    p.URL,
+		APIKey:     p.APIKey,
+		AdminToken: p.AdminToken,
+		HTTPClient: e.httpClient,
+	}), nil
+}
+
+// render выводит ответ в формате профиля (команды без обращения к API - в формате из -o)
+func (e *env) render(v interface{}, toTable func() *table) error {
+	format := e.format
+	if format == "" {
+		format = e.opts.output
+	}
+	if format == "" {
+		format = outputTable
+	}
+	return render(e.stdout, format, v, toTable)
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/api"
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/Shishlyannikovvv/project-avito/pkg/client"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeService реализует только нужные тестам методы
+type fakeService struct {
+	domain.Service
+	teams map[int]*domain.Team
+}
+
+func (s *fakeService) GetTeam(ctx context.Context, teamID int) (*domain.Team, error) {
+	team, ok := s.teams[teamID]
+	if !ok {
+		return nil, domain.ErrTeamNotFound
+	}
+	return team, nil
+}
+
+func (s *fakeService) MergePR(ctx context.Context, prID int) (*domain.PullRequest, error) {
+	return &domain.PullRequest{ID: prID, Title: "Fix", Status: domain.PRStatusMerged, AuthorID: 1,
+		Reviewers: []domain.User{{ID: 2}, {ID: 3}}}, nil
+}
+
+func (s *fakeService) RerollReviewer(ctx context.Context, prID int, oldReviewerID int) (*domain.PullRequest, error) {
+	return nil, domain.ErrPRAlreadyMerged
+}
+
+func (s *fakeService) GetReviewerStats(ctx context.Context) (map[int]int, error) {
+	return map[int]int{10: 1, 2: 5}, nil
+}
+
+func (s *fakeService) CreateOrganization(ctx context.Context, name string) (*domain.Organization, string, error) {
+	return &domain.Organization{ID: 7, Name: name}, "secret-key", nil
+}
+
+func (s *fakeService) ImportDirectory(ctx context.Context, records []domain.DirectoryRecord, dryRun bool) (*domain.ImportReport, error) {
+	report := &domain.ImportReport{DryRun: dryRun}
+	for i, r := range records {
+		row := domain.ImportRowResult{Row: i + 1, Action: domain.ImportCreated}
+		if r.User == "" {
+			row = domain.ImportRowResult{Row: i + 1, Action: domain.ImportFailed, Error: "user is required"}
+			report.Failed++
+		} else {
+			report.Created++
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	report.Applied = !dryRun && report.Failed == 0
+	return report, nil
+}
+
+// setup поднимает API на фейковом сервисе и пустой файл профилей
+func setup(t *testing.T) string {
+	gin.SetMode(gin.TestMode)
+	handler := api.NewHandler(&fakeService{teams: map[int]*domain.Team{1: {ID: 1, Name: "Backend"}}})
+	router := api.SetupRouter(handler)
+	api.SetupAdminRoutes(router, handler, "admin-token")
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+
+	t.Setenv("REVIEWCTL_CONFIG", filepath.Join(t.TempDir(), "config.yaml"))
+	for _, name := range []string{"REVIEWCTL_PROFILE", "REVIEWCTL_URL", "REVIEWCTL_API_KEY", "REVIEWCTL_ADMIN_TOKEN", "REVIEWCTL_OUTPUT"} {
+		t.Setenv(name, "")
+	}
+	return srv.URL
+}
+
+func exec(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	e := &env{ctx: context.Background(), stdin: strings.NewReader(""), stdout: &stdout, stderr: &stderr}
+	code := run(e, args)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestOutputFormats(t *testing.T) {
+	url := setup(t)
+
+	code, out, _ := exec("-url", url, "teams", "get", "1")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, out, "NAME")
+	assert.Contains(t, out, "Backend")
+
+	code, out, _ = exec("teams", "get", "1", "-url", url, "-o", "json")
+	assert.Equal(t, exitOK, code)
+	var team domain.Team
+	assert.NoError(t, json.Unmarshal([]byte(out), &team))
+	assert.Equal(t, "Backend", team.Name)
+
+	code, out, _ = exec("-url", url, "-o", "yaml", "prs", "merge", "5")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, out, "status: MERGED")
+	assert.Contains(t, out, "reviewers:\n  - id: 2")
+
+	// Статистика - по возрастанию ID пользователя
+	code, out, _ = exec("-url", url, "stats")
+	assert.Equal(t, exitOK, code)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[1], "2 "))
+	assert.True(t, strings.HasPrefix(lines[2], "10 "))
+}
+
+func TestExitCodes(t *testing.T) {
+	url := setup(t)
+
+	code, _, stderr := exec("-url", url, "teams", "get", "99")
+	assert.Equal(t, exitNotFound, code)
+	assert.Contains(t, stderr, "HTTP 404")
+
+	code, _, _ = exec("-url", url, "prs", "reroll", "1", "2")
+	assert.Equal(t, exitConflict, code)
+
+	code, _, stderr = exec("-url", url, "teams", "get", "abc")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "usage: reviewctl teams get <id>")
+
+	code, _, _ = exec("-url", url, "teams", "frobnicate")
+	assert.Equal(t, exitUsage, code)
+
+	// Без токена администратора - 401
+	code, _, _ = exec("-url", url, "tokens", "create", "Payments")
+	assert.Equal(t, exitAuth, code)
+
+	code, out, _ := exec("-url", url, "-admin-token", "admin-token", "tokens", "create", "Payments")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, out, "secret-key")
+
+	assert.Equal(t, exitInvalid, exitCode(&client.APIError{StatusCode: 422}))
+	assert.Equal(t, exitRateLimited, exitCode(&client.APIError{StatusCode: 429}))
+	assert.Equal(t, exitError, exitCode(&client.APIError{StatusCode: 500}))
+}
+
+func TestImport(t *testing.T) {
+	url := setup(t)
+	dir := t.TempDir()
+
+	good := filepath.Join(dir, "good.csv")
+	assert.NoError(t, os.WriteFile(good, []byte("team,user\nBackend,Alice\nBackend,Bob\n"), 0o600))
+	code, out, stderr := exec("-url", url, "import", good, "-dry-run")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, out, "created")
+	assert.Contains(t, stderr, "created 2")
+	assert.Contains(t, stderr, "applied: false")
+
+	// Ошибка в строке - отчет выводится, код завершения 5
+	bad := filepath.Join(dir, "bad.json")
+	assert.NoError(t, os.WriteFile(bad, []byte(`[{"team":"Backend","user":"Alice"},{"team":"Backend"}]`), 0o600))
+	code, out, stderr = exec("-url", url, "-o", "json", "import", bad)
+	assert.Equal(t, exitInvalid, code)
+	var report domain.ImportReport
+	assert.NoError(t, json.Unmarshal([]byte(out), &report))
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, "user is required", report.Rows[1].Error)
+	assert.Contains(t, stderr, "1 row(s) failed")
+}
+
+func TestProfiles(t *testing.T) {
+	url := setup(t)
+
+	code, _, _ := exec("profiles", "use", "prod")
+	assert.Equal(t, exitUsage, code)
+
+	code, _, _ = exec("profiles", "set", "local", "-url", url, "-o", "json")
+	assert.Equal(t, exitOK, code)
+	code, _, _ = exec("profiles", "set", "prod", "-url", "http://127.0.0.1:1")
+	assert.Equal(t, exitOK, code)
+
+	// Первый профиль стал текущим: URL и формат берутся из него
+	code, out, _ := exec("teams", "get", "1")
+	assert.Equal(t, exitOK, code)
+	assert.True(t, strings.HasPrefix(out, "{"))
+
+	code, out, _ = exec("profiles", "list")
+	assert.Equal(t, exitOK, code)
+	assert.Regexp(t, `\*\s+local`, out)
+
+	// Недоступный сервер - общая ошибка
+	code, _, _ = exec("profiles", "use", "prod")
+	assert.Equal(t, exitOK, code)
+	code, _, _ = exec("teams", "get", "1")
+	assert.Equal(t, exitError, code)
+
+	// Флаг профиля важнее текущего
+	code, _, _ = exec("-profile", "local", "teams", "get", "1")
+	assert.Equal(t, exitOK, code)
+
+	info, err := os.Stat(os.Getenv("REVIEWCTL_CONFIG"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// Форматы вывода
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// table - табличное представление ответа
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func newTable(header ...string) *table {
+	return &table{header: header}
+}
+
+func (t *table) add(cells ...interface{}) {
+	row := make([]string, len(cells))
+	for i, c := range cells {
+		row[i] = cell(c)
+	}
+	t.rows = append(t.rows, row)
+}
+
+// cell форматирует значение ячейки: nil-указатели и пустые значения - "-"
+func cell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "-"
+	case *int:
+		if v == nil {
+			return "-"
+		}
+		return fmt.Sprint(*v)
+	case string:
+		if v == "" {
+			return "-"
+		}
+		return v
+	case []string:
+		if len(v) == 0 {
+			return "-"
+		}
+		return strings.Join(v, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (t *table) write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// render выводит v в формате format; для таблицы используется toTable
+func render(w io.Writer, format string, v interface{}, toTable func() *table) error {
+	switch format {
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case outputYAML:
+		return writeYAML(w, v)
+	case outputTable:
+		return toTable().write(w)
+	default:
+		return usageErrorf("unknown output format %q (table, json, yaml)", format)
+	}
+}
+
+// writeYAML выводит v в YAML с теми же именами полей, что и в JSON API
+// (JSON - подмножество YAML, поэтому разбираем его в узлы и снимаем inline-стиль)
+func writeYAML(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// blockStyle переводит узлы в блочный стиль; строки, похожие на числа или bool, кодировщик сам возьмет в кавычки
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, child := range n.Content {
+		blockStyle(child)
+	}
+}
+
+// --- Таблицы ответов ---
+
+func teamsTable(teams ...domain.Team) *table {
+	t := newTable("ID", "NAME", "PARENT", "ARCHIVED")
+	for _, team := range teams {
+		t.add(team.ID, team.Name, team.ParentID, team.ArchivedAt != nil)
+	}
+	return t
+}
+
+func usersTable(users ...domain.User) *table {
+	t := newTable("ID", "NAME", "TEAM", "ACTIVE", "LOGIN", "EMAIL")
+	for _, u := range users {
+		t.add(u.ID, u.Name, u.TeamID, u.IsActive, u.Login, u.Email)
+	}
+	return t
+}
+
+func membershipsTable(memberships []domain.TeamMembership) *table {
+	t := newTable("TEAM", "USER", "NAME", "CAN AUTHOR", "CAN REVIEW")
+	for _, m := range memberships {
+		name := ""
+		if m.User != nil {
+			name = m.User.Name
+		}
+		t.add(m.TeamID, m.UserID, name, m.CanAuthor, m.CanReview)
+	}
+	return t
+}
+
+func prsTable(prs ...domain.PullRequest) *table {
+	t := newTable("ID", "TITLE", "STATUS", "AUTHOR", "REVIEWERS")
+	for _, pr := range prs {
+		reviewers := make([]string, 0, len(pr.Reviewers))
+		for _, r := range pr.Reviewers {
+			reviewers = append(reviewers, strconv.Itoa(r.ID))
+		}
+		t.add(pr.ID, pr.Title, pr.Status, pr.AuthorID, reviewers)
+	}
+	return t
+}
+
+func importTable(report domain.ImportReport) *table {
+	t := newTable("ROW", "ACTION", "TEAM", "USER", "ERROR")
+	for _, r := range report.Rows {
+		t.add(r.Row, r.Action, r.TeamID, r.UserID, r.Error)
+	}
+	return t
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"math"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+// Гистограмма задержек в духе HDR Histogram: значения в микросекундах раскладываются по
+// логарифмическим диапазонам (степени двойки), каждый из которых делится на равные поддиапазоны.
+// Погрешность значения не больше 1/subBucketHalf (~1.6%) при фиксированной памяти на любом масштабе.
+const (
+	subBucketBits  = 7
+	subBucketCount = 1 << subBucketBits // значения меньше этого хранятся точно
+	subBucketHalf  = subBucketCount / 2
+)
+
+type histogram struct {
+	mu     sync.Mutex
+	counts []uint64
+	total  uint64
+	sum    time.Duration
+	min    time.Duration
+	max    time.Duration
+}
+
+func bucketIndex(us int64) int {
+	if us < subBucketCount {
+		return int(us)
+	}
+	// Старшие subBucketBits бит значения: номер диапазона и поддиапазон в нем
+	shift := bits.Len64(uint64(us)) - subBucketBits
+	sub := int(us >> shift) // [subBucketHalf, subBucketCount)
+	return subBucketCount + (shift-1)*subBucketHalf + sub - subBucketHalf
+}
+
+// bucketUpper - наибольшее значение, попадающее в поддиапазон index
+func bucketUpper(index int) int64 {
+	if index < subBucketCount {
+		return int64(index)
+	}
+	shift := (index-subBucketCount)/subBucketHalf + 1
+	sub := int64((index-subBucketCount)%subBucketHalf + subBucketHalf)
+	return (sub+1)<<shift - 1
+}
+
+func (h *histogram) record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	index := bucketIndex(d.Microseconds())
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if index >= len(h.counts) {
+		grown := make([]uint64, index+1)
+		copy(grown, h.counts)
+		h.counts = grown
+	}
+	h.counts[index]++
+	if h.total == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.total++
+	h.sum += d
+}
+
+// percentile возвращает значение, не меньше которого p процентов записей (верхняя граница поддиапазона, не больше max)
+func (h *histogram) percentile(p float64) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.total == 0 {
+		return 0
+	}
+	target := uint64(math.Ceil(p / 100 * float64(h.total)))
+	if target == 0 {
+		target = 1
+	}
+	var seen uint64
+	for index, count := range h.counts {
+		seen += count
+		if seen >= target {
+			return min(time.Duration(bucketUpper(index))*time.Microsecond, h.max)
+		}
+	}
+	return h.max
+}
+
+func (h *histogram) count() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.total
+}
+
+func (h *histogram) mean() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.total == 0 {
+		return 0
+	}
+	return h.sum / time.Duration(h.total)
+}
+
+func (h *histogram) maxValue() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.max
+}
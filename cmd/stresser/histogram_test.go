@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucketBounds(t *testing.T) {
+	for _, us := range []int64{0, 1, 127, 128, 129, 255, 256, 1000, 12345, 999999, 3_600_000_000} {
+		index := bucketIndex(us)
+		upper := bucketUpper(index)
+		assert.GreaterOrEqual(t, upper, us)
+		// Погрешность в пределах одного поддиапазона
+		assert.LessOrEqual(t, float64(upper-us), float64(us)/subBucketHalf+1, "value %d", us)
+		if index > 0 {
+			assert.Less(t, bucketUpper(index-1), us, "value %d", us)
+		}
+	}
+}
+
+func TestPercentiles(t *testing.T) {
+	var h histogram
+	assert.Equal(t, time.Duration(0), h.percentile(99))
+
+	// 1..1000 мс
+	for i := 1; i <= 1000; i++ {
+		h.record(time.Duration(i) * time.Millisecond)
+	}
+	assert.Equal(t, uint64(1000), h.count())
+	assert.InEpsilon(t, float64(500*time.Millisecond), float64(h.percentile(50)), 0.02)
+	assert.InEpsilon(t, float64(950*time.Millisecond), float64(h.percentile(95)), 0.02)
+	assert.InEpsilon(t, float64(990*time.Millisecond), float64(h.percentile(99)), 0.02)
+	assert.Equal(t, time.Second, h.percentile(100))
+	assert.Equal(t, time.Second, h.maxValue())
+	assert.Equal(t, 500500*time.Microsecond, h.mean())
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// Инварианты, проверяемые после прогона
+const (
+	invAuthorReviewer    = "author_is_reviewer"
+	invDuplicateReviewer = "duplicate_reviewer"
+	invInactiveReviewer  = "inactive_reviewer_on_open_pr"
+	invRerollAfterMerge  = "reroll_after_merge"
+	invTooManyReviewers  = "too_many_reviewers"
+	// Подтвержденное изменение потеряно: мердж откатился или назначенный переназначением ревьюер пропал
+	invLostUpdate = "lost_update"
+)
+
+// checkWorkers - сколько PR проверяется одновременно
+const checkWorkers = 8
+
+// Violation - нарушение инварианта на PR
+type Violation struct {
+	Invariant string `json:"invariant"`
+	PRID      int    `json:"pr_id"`
+	Details   string `json:"details"`
+}
+
+// expectation - что о PR известно из успешных ответов сервиса
+type expectation struct {
+	// Мердж подтвержден
+	merged bool
+	// Ревьюеры из ответа на подтвержденный мердж (по возрастанию ID) - состояние, зафиксированное вместе со статусом
+	mergedReviewers []int
+	// Ревьюеры, назначенные подтвержденными переназначениями, которых с тех пор никто не снимал
+	kept []int
+}
+
+// checkPR проверяет инварианты PR по его текущему состоянию
+func checkPR(pr *domain.PullRequest, maxReviewers int, exp expectation) []Violation {
+	var violations []Violation
+	add := func(invariant, format string, args ...interface{}) {
+		violations = append(violations, Violation{Invariant: invariant, PRID: pr.ID, Details: fmt.Sprintf(format, args...)})
+	}
+
+	seen := make(map[int]bool, len(pr.Reviewers))
+	for _, u := range pr.Reviewers {
+		if u.ID == pr.AuthorID {
+			add(invAuthorReviewer, "author %d is a reviewer", u.ID)
+		}
+		if seen[u.ID] {
+			add(invDuplicateReviewer, "reviewer %d assigned more than once", u.ID)
+		}
+		seen[u.ID] = true
+		if pr.Status == domain.PRStatusOpen && !u.IsActive {
+			add(invInactiveReviewer, "reviewer %d is inactive", u.ID)
+		}
+	}
+	if maxReviewers > 0 && len(pr.Reviewers) > maxReviewers {
+		add(invTooManyReviewers, "%d reviewers, policy allows %d", len(pr.Reviewers), maxReviewers)
+	}
+
+	if exp.merged && pr.Status != domain.PRStatusMerged {
+		add(invLostUpdate, "merge was acknowledged, but status is %s", pr.Status)
+	}
+	// Мердж фиксирует ревьюеров вместе со статусом: любое отличие - изменение после мерджа
+	// (история для этого не годится - она пишется после коммита и может лечь не по порядку)
+	if exp.merged && pr.Status == domain.PRStatusMerged {
+		if got := reviewerIDs(pr); !slices.Equal(exp.mergedReviewers, got) {
+			add(invRerollAfterMerge, "reviewers %v at merge, %v now", exp.mergedReviewers, got)
+		}
+	}
+	for _, id := range exp.kept {
+		if !seen[id] {
+			add(invLostUpdate, "reviewer %d assigned by an acknowledged reroll is missing", id)
+		}
+	}
+	return violations
+}
+
+// Checks - итоги проверки корректности
+type Checks struct {
+	CheckedPRs int `json:"checked_prs"`
+	RaceRounds int `json:"race_rounds"`
+	// PR и раунды гонок, которые не удалось проверить из-за ошибки запроса
+	Failed     int         `json:"failed"`
+	Violations []Violation `json:"violations"`
+}
+
+// check выполняет раунды гонок, затем проверяет инварианты всех PR, созданных нагрузкой
+func (r *runner) check(ctx context.Context) *Checks {
+	maxReviewers := 0
+	if policy, err := r.api.GetTeamPolicy(ctx, r.teamID); err != nil {
+		log.Printf("Failed to load team policy, reviewer limit is not checked: %v", err)
+	} else {
+		maxReviewers = policy.MaxReviewers
+	}
+
+	checks := &Checks{Violations: []Violation{}}
+	r.races(ctx, maxReviewers, checks)
+	r.verify(ctx, maxReviewers, checks)
+	sort.SliceStable(checks.Violations, func(i, j int) bool { return checks.Violations[i].PRID < checks.Violations[j].PRID })
+	return checks
+}
+
+// verify проверяет PR, созданные нагрузкой
+func (r *runner) verify(ctx context.Context, maxReviewers int, checks *Checks) {
+	r.poolMu.Lock()
+	ids := append([]int(nil), r.created...)
+	merged := make(map[int][]int, len(r.merged))
+	for id, reviewers := range r.merged {
+		merged[id] = reviewers
+	}
+	r.poolMu.Unlock()
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	queue := make(chan int)
+	for i := 0; i < min(checkWorkers, r.sc.MaxInFlight); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range queue {
+				reviewers, ok := merged[id]
+				found, err := r.checkOne(ctx, id, maxReviewers, expectation{merged: ok, mergedReviewers: reviewers})
+				mu.Lock()
+				if err != nil {
+					log.Printf("Failed to check PR %d: %v", id, err)
+					checks.Failed++
+				} else {
+					checks.CheckedPRs++
+					checks.Violations = append(checks.Violations, found...)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, id := range ids {
+		queue <- id
+	}
+	close(queue)
+	wg.Wait()
+}
+
+func (r *runner) checkOne(ctx context.Context, prID, maxReviewers int, exp expectation) ([]Violation, error) {
+	pr, err := r.api.GetPR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	return checkPR(pr, maxReviewers, exp), nil
+}
+
+// raceRound создает PR и одновременно запускает переназначение каждого его ревьюера, деактивацию
+// случайного участника команды (не автора) и, если withMerge, мердж. Без сериализации этих изменений
+// в сервисе одно из них перезаписывает другое: пропадает подтвержденный ревьюер, мердж откатывается,
+// ревьюер дублируется или остается деактивированным. Инварианты проверяются до возврата участника.
+func (r *runner) raceRound(ctx context.Context, withMerge bool, maxReviewers int) ([]Violation, error) {
+	author := r.users[r.randomIndex(len(r.users))]
+	pr, err := r.api.CreatePR(ctx, fmt.Sprintf("Race PR by %d", author.ID), author.ID, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create PR: %w", err)
+	}
+	target := r.users[r.randomIndex(len(r.users))]
+	for target.ID == author.ID {
+		target = r.users[r.randomIndex(len(r.users))]
+	}
+
+	var (
+		mu sync.Mutex
+		// Ревьюеры, назначенные подтвержденными переназначениями
+		assigned    []int
+		mergeAcked  bool
+		atMerge     []int
+		deactivated bool
+		wg          sync.WaitGroup
+	)
+	// Все запросы раунда стартуют одновременно
+	start := make(chan struct{})
+	race := func(f func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			f()
+		}()
+	}
+	for _, old := range reviewerIDs(pr) {
+		race(func() {
+			updated, err := r.api.RerollReviewer(ctx, pr.ID, old)
+			if err != nil {
+				return
+			}
+			for _, id := range reviewerIDs(updated) {
+				if !hasID(pr, id) {
+					mu.Lock()
+					assigned = append(assigned, id)
+					mu.Unlock()
+				}
+			}
+		})
+	}
+	if withMerge {
+		race(func() {
+			if merged, err := r.api.MergePR(ctx, pr.ID); err == nil {
+				mu.Lock()
+				mergeAcked = true
+				atMerge = reviewerIDs(merged)
+				mu.Unlock()
+			}
+		})
+	}
+	race(func() {
+		if err := r.api.MassDeactivateTeamUsers(ctx, r.teamID, target.ID); err == nil {
+			mu.Lock()
+			deactivated = true
+			mu.Unlock()
+		}
+	})
+	close(start)
+	wg.Wait()
+
+	if deactivated {
+		defer func() {
+			if _, err := r.api.ActivateUser(ctx, target.ID); err != nil {
+				log.Printf("Failed to reactivate user %d: %v", target.ID, err)
+			}
+		}()
+	}
+
+	// Деактивация законно снимает участника со всех PR, поэтому его назначение не ожидается
+	exp := expectation{merged: mergeAcked, mergedReviewers: atMerge}
+	for _, id := range assigned {
+		if id != target.ID {
+			exp.kept = append(exp.kept, id)
+		}
+	}
+	return r.checkOne(ctx, pr.ID, maxReviewers, exp)
+}
+
+// races выполняет раунды гонок; раунды с мерджем чередуются с раундами без него,
+// чтобы переназначения чаще сталкивались друг с другом, а не с закрытым PR
+func (r *runner) races(ctx context.Context, maxReviewers int, checks *Checks) {
+	for i := 0; i < r.sc.Races && ctx.Err() == nil; i++ {
+		found, err := r.raceRound(ctx, i%2 == 1, maxReviewers)
+		if err != nil {
+			log.Printf("Race round %d failed: %v", i, err)
+			checks.Failed++
+			continue
+		}
+		checks.RaceRounds++
+		checks.Violations = append(checks.Violations, found...)
+	}
+}
+
+func hasID(pr *domain.PullRequest, userID int) bool {
+	for _, u := range pr.Reviewers {
+		if u.ID == userID {
+			return true
+		}
+	}
+	return false
+}
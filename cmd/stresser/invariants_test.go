@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPR(t *testing.T) {
+	reviewer := func(id int, active bool) domain.User { return domain.User{ID: id, IsActive: active} }
+	invariants := func(violations []Violation) []string {
+		var names []string
+		for _, v := range violations {
+			assert.Equal(t, 7, v.PRID)
+			names = append(names, v.Invariant)
+		}
+		return names
+	}
+
+	pr := &domain.PullRequest{ID: 7, AuthorID: 1, Status: domain.PRStatusOpen,
+		Reviewers: []domain.User{reviewer(3, true), reviewer(2, true)}}
+	assert.Empty(t, checkPR(pr, 2, expectation{kept: []int{3}}))
+
+	pr.Reviewers = []domain.User{reviewer(1, true), reviewer(3, false), reviewer(3, false)}
+	assert.Equal(t, []string{invAuthorReviewer, invInactiveReviewer, invDuplicateReviewer, invInactiveReviewer, invTooManyReviewers},
+		invariants(checkPR(pr, 2, expectation{})))
+
+	// После мерджа неактивный ревьюер допустим, а смена ревьюеров относительно ответа на мердж - нет
+	pr.Status = domain.PRStatusMerged
+	pr.Reviewers = []domain.User{reviewer(3, false), reviewer(2, false)}
+	assert.Empty(t, checkPR(pr, 2, expectation{merged: true, mergedReviewers: []int{2, 3}, kept: []int{3}}))
+	pr.Reviewers = []domain.User{reviewer(2, false)}
+	assert.Equal(t, []string{invRerollAfterMerge, invLostUpdate},
+		invariants(checkPR(pr, 2, expectation{merged: true, mergedReviewers: []int{2, 3}, kept: []int{3}})))
+
+	// Подтвержденный мердж потерян
+	pr.Status = domain.PRStatusOpen
+	pr.Reviewers = nil
+	assert.Equal(t, []string{invLostUpdate}, invariants(checkPR(pr, 2, expectation{merged: true})))
+}
@@ -1,170 +1,199 @@
+// stresser - нагрузочный прогон сервиса по сценарию: смесь операций с весами, открытый поток запросов,
+// прогрев, перцентили задержек по операциям и проверка SLO. После нагрузки стрессер запускает гонки
+// переназначения, мерджа и деактивации и проверяет бизнес-инварианты всех созданных PR.
+//
+//	stresser -scenario scenarios/mixed.yaml -json report.json
+//	stresser -rate 20 -duration 1m -mix create=5,merge=3,reroll=1,list=2 -slo-p99 300ms
+//
+// Флаги переопределяют значения из файла сценария. Код завершения: 0 - цели выполнены,
+// 1 - SLO не выполнены, 2 - ошибка сценария или подготовки данных, 3 - нарушены инварианты.
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"log"
-	"net/http"
-	"sync"
+	"os"
+	"os/signal"
 	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/pkg/client"
 )
 
 const (
-	baseURL   = "http://localhost:8080/api/v1"
-	rpsGoal   = 5                // RPS - 5
-	duration  = 10 * time.Second // Тестируем 10 секунд
-	userCount = 10               // Количество пользователей для теста
+	exitOK        = 0
+	exitSLOFailed = 1
+	exitSetup     = 2
+	exitViolation = 3
 )
 
-type user struct {
-	ID     int `json:"id"`
-	TeamID int `json:"team_id"`
-}
-type pr struct {
-	ID int `json:"id"`
-}
-
 func main() {
-	log.Println("Starting stress test setup...")
-
-	// 1. Подготовка данных: Создание команды и пользователей
-	teamID, users := setupTestData()
+	os.Exit(run(os.Args[1:], os.Stdout))
+}
 
-	// Проверка, что есть хотя бы 3 активных пользователя для теста
-	if len(users) < 3 {
-		log.Fatal("Not enough users for stress test. Need at least 3 active users in the team.")
+func run(args []string, stdout io.Writer) int {
+	sc, reports, err := parseArgs(args)
+	if err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		log.Printf("Invalid scenario: %v", err)
+		return exitSetup
 	}
 
-	log.Printf("Setup complete. Team ID: %d, Users: %d. Starting test for %s at %d RPS.", teamID, len(users), duration, rpsGoal)
-
-	var wg sync.WaitGroup
-	ticker := time.NewTicker(time.Second / time.Duration(rpsGoal))
-	defer ticker.Stop()
+	// Ctrl+C прекращает подачу запросов; отчет строится по уже выполненным
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-	// Таймер для ограничения продолжительности теста
-	ctx, cancel := context.WithTimeout(context.Background(), duration)
-	defer cancel()
+	// Повторы клиента исказили бы задержки и долю ошибок - каждый запрос выполняется один раз
+	api := client.New(client.Config{BaseURL: sc.URL, APIKey: sc.APIKey, MaxRetries: -1, HTTPClient: newHTTPClient(sc)})
+	r := newRunner(sc, api)
 
-	var requestCounter int64
-	var successCounter int64
-
-	start := time.Now()
-
-	// 2. Запуск горутин для RPS
-	for i := 0; i < int(duration.Seconds()*float64(rpsGoal)); i++ {
-		select {
-		case <-ctx.Done():
-			goto endLoop
-		case <-ticker.C:
-			wg.Add(1)
-			requestCounter++
-
-			// Выбираем случайного автора
-			author := users[i%len(users)]
-
-			go func(author user) {
-				defer wg.Done()
-
-				// 3. Создание PR
-				prID, err := createPR(author.ID)
-				if err != nil {
-					log.Printf("Error creating PR for user %d: %v", author.ID, err)
-					return
-				}
-
-				// 4. Мердж PR (для полной нагрузки)
-				if err := mergePR(prID); err != nil {
-					log.Printf("Error merging PR %d: %v", prID, err)
-					return
-				}
-
-				successCounter++
-			}(author)
-		}
+	log.Println("Starting stress test setup...")
+	if err := r.setup(ctx); err != nil {
+		log.Printf("Failed to set up test data (is the server running?): %v", err)
+		return exitSetup
+	}
+	log.Printf("Setup complete. Team ID: %d, Users: %d. Warm-up %s, then %s at %g req/s, mix %s.",
+		r.teamID, len(r.users), sc.WarmUp, sc.Duration, sc.Rate, sc.mix())
+
+	startedAt := time.Now()
+	measured := r.run(ctx)
+	rep := buildReport(r, startedAt, measured)
+	if !sc.SkipChecks {
+		log.Printf("Load complete. Running %d race rounds and checking invariants...", sc.Races)
+		rep.Checks = r.check(ctx)
 	}
 
-endLoop:
-	wg.Wait()
-
-	elapsed := time.Since(start)
-
-	// 5. Вывод результатов
-	log.Println("--- Stress Test Results ---")
-	log.Printf("Duration: %s", elapsed.Round(time.Millisecond))
-	log.Printf("Total Requests Sent (Create PR + Merge): %d", requestCounter*2)
-	log.Printf("Successful PR Cycles (Create + Merge): %d", successCounter)
-	log.Printf("Measured RPS: %.2f (Goal: %d)", float64(requestCounter)/elapsed.Seconds(), rpsGoal)
-	log.Printf("Success SLI: %.2f%% (Goal: 99.9%%)", float64(successCounter*2)/float64(requestCounter*2)*100)
+	if err := reports.write(rep, stdout); err != nil {
+		log.Printf("Failed to write report: %v", err)
+		return exitSetup
+	}
+	if rep.Checks != nil && len(rep.Checks.Violations) > 0 {
+		log.Printf("Invariant check failed: %d violations", len(rep.Checks.Violations))
+		return exitViolation
+	}
+	if !rep.Passed {
+		log.Println("SLO check failed")
+		return exitSLOFailed
+	}
+	return exitOK
 }
 
-// --- Helper Functions ---
+// reportFiles - куда писать отчеты; Markdown без файла выводится в stdout
+type reportFiles struct {
+	json     string
+	markdown string
+}
 
-func setupTestData() (int, []user) {
-	// Создание команды
-	var teamID int
-	resp, _ := http.Post(baseURL+"/teams", "application/json", bytes.NewBufferString(`{"name": "StresserTeam"}`))
-	if resp != nil && resp.StatusCode == http.StatusCreated {
-		var t struct {
-			ID int `json:"id"`
-		}
-		json.NewDecoder(resp.Body).Decode(&t)
-		teamID = t.ID
-		resp.Body.Close()
-	} else {
-		log.Fatal("Failed to create test team. Is the server running?")
-	}
-
-	// Создание пользователей
-	users := make([]user, userCount)
-	for i := 0; i < userCount; i++ {
-		userData := fmt.Sprintf(`{"name": "StressUser_%d", "team_id": %d}`, i, teamID)
-		resp, _ := http.Post(baseURL+"/users", "application/json", bytes.NewBufferString(userData))
-		if resp != nil && resp.StatusCode == http.StatusCreated {
-			var u user
-			json.NewDecoder(resp.Body).Decode(&u)
-			users[i] = u
-			resp.Body.Close()
+func (f reportFiles) write(rep *Report, stdout io.Writer) error {
+	if f.json != "" {
+		if err := writeFile(f.json, rep.writeJSON); err != nil {
+			return err
 		}
 	}
-	return teamID, users
+	if f.markdown != "" {
+		return writeFile(f.markdown, rep.writeMarkdown)
+	}
+	return rep.writeMarkdown(stdout)
 }
 
-func createPR(authorID int) (int, error) {
-	prData := fmt.Sprintf(`{"title": "Test PR by %d", "author_id": %d}`, authorID, authorID)
-	resp, err := http.Post(baseURL+"/prs", "application/json", bytes.NewBufferString(prData))
+func writeFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
 	if err != nil {
-		return 0, err
+		return err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return 0, fmt.Errorf("failed to create PR, status: %d, body: %s", resp.StatusCode, body)
+	if err := write(f); err != nil {
+		f.Close()
+		return err
 	}
+	return f.Close()
+}
 
-	var p pr
-	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
-		return 0, err
+// parseArgs собирает сценарий: значения по умолчанию, поверх них файл, поверх него явно заданные флаги
+func parseArgs(args []string) (*Scenario, reportFiles, error) {
+	var reports reportFiles
+	fs := flag.NewFlagSet("stresser", flag.ContinueOnError)
+	file := fs.String("scenario", "", "scenario file (YAML or JSON)")
+	name := fs.String("name", "", "scenario name for the report")
+	url := fs.String("url", "", "service URL")
+	apiKey := fs.String("api-key", "", "organization API key (default $STRESSER_API_KEY)")
+	rate := fs.Float64("rate", 0, "arrival rate, requests per second (open loop)")
+	duration := fs.Duration("duration", 0, "measured duration")
+	warmUp := fs.Duration("warmup", 0, "warm-up before measuring")
+	users := fs.Int("users", 0, "users to create in the test team")
+	maxInFlight := fs.Int("max-in-flight", 0, "concurrent request limit; arrivals above it are dropped")
+	timeout := fs.Duration("timeout", 0, "per-request timeout")
+	seed := fs.Int64("seed", 0, "random seed (0 - time based)")
+	mix := fs.String("mix", "", "operation weights, e.g. create=5,merge=3,reroll=1,list=2,deactivate=0.2")
+	sloP99 := fs.Duration("slo-p99", 0, "p99 latency objective for every operation")
+	sloSuccess := fs.Float64("slo-success", 0, "success rate objective, percent")
+	races := fs.Int("races", 0, "reroll/merge/deactivate race rounds after the load")
+	skipChecks := fs.Bool("skip-checks", false, "skip race rounds and invariant checks")
+	fs.StringVar(&reports.json, "json", "", "write JSON report to file")
+	fs.StringVar(&reports.markdown, "markdown", "", "write Markdown report to file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return nil, reports, err
+	}
+	if fs.NArg() > 0 {
+		return nil, reports, fmt.Errorf("unexpected arguments: %v", fs.Args())
 	}
-	return p.ID, nil
-}
 
-func mergePR(prID int) error {
-	url := fmt.Sprintf("%s/prs/%d/merge", baseURL, prID)
-	req, _ := http.NewRequest(http.MethodPost, url, nil)
+	sc := defaultScenario()
+	if *file != "" {
+		var err error
+		if sc, err = loadScenario(*file); err != nil {
+			return nil, reports, err
+		}
+	}
+	if sc.APIKey == "" {
+		sc.APIKey = os.Getenv("STRESSER_API_KEY")
+	}
 
-	client := http.Client{}
-	resp, err := client.Do(req)
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "name":
+			sc.Name = *name
+		case "url":
+			sc.URL = *url
+		case "api-key":
+			sc.APIKey = *apiKey
+		case "rate":
+			sc.Rate = *rate
+		case "duration":
+			sc.Duration = *duration
+		case "warmup":
+			sc.WarmUp = *warmUp
+		case "users":
+			sc.Users = *users
+		case "max-in-flight":
+			sc.MaxInFlight = *maxInFlight
+		case "timeout":
+			sc.Timeout = *timeout
+		case "seed":
+			sc.Seed = *seed
+		case "mix":
+			if sc.Operations, err = parseMix(*mix); err != nil {
+				return
+			}
+		case "slo-p99":
+			sc.SLO.P99 = *sloP99
+		case "slo-success":
+			sc.SLO.SuccessRate = *sloSuccess
+		case "races":
+			sc.Races = *races
+		case "skip-checks":
+			sc.SkipChecks = *skipChecks
+		}
+	})
 	if err != nil {
-		return err
+		return nil, reports, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to merge PR %d, status: %d", prID, resp.StatusCode)
+	if err := sc.validate(); err != nil {
+		return nil, reports, err
 	}
-	return nil
+	return sc, reports, nil
 }
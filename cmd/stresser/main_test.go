@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/api"
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseArgs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scenario.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`
+name: nightly
+rate: 50
+duration: 2m
+warmup: 15s
+operations:
+  - name: create
+    weight: 3
+  - name: reroll
+    weight: 1
+    p99: 800ms
+slo:
+  p99: 250ms
+  success_rate: 99.5
+`), 0o600))
+
+	// Флаги поверх файла
+	sc, reports, err := parseArgs([]string{"-scenario", path, "-rate", "10", "-json", "out.json"})
+	assert.NoError(t, err)
+	assert.Equal(t, "nightly", sc.Name)
+	assert.Equal(t, 10.0, sc.Rate)
+	assert.Equal(t, 2*time.Minute, sc.Duration)
+	assert.Equal(t, 15*time.Second, sc.WarmUp)
+	assert.Equal(t, 10, sc.Users) // из значений по умолчанию
+	assert.Equal(t, 800*time.Millisecond, sc.p99Target(opReroll))
+	assert.Equal(t, 250*time.Millisecond, sc.p99Target(opCreate))
+	assert.Equal(t, "out.json", reports.json)
+
+	sc, _, err = parseArgs([]string{"-mix", "list=2, merge=1", "-slo-p99", "1s"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Operation{{Name: opList, Weight: 2}, {Name: opMerge, Weight: 1}}, sc.Operations)
+	assert.Equal(t, "list=2,merge=1", sc.mix())
+	assert.Equal(t, time.Second, sc.SLO.P99)
+
+	_, _, err = parseArgs([]string{"-mix", "create=1,explode=2"})
+	assert.ErrorContains(t, err, `unknown operation "explode"`)
+	_, _, err = parseArgs([]string{"-rate", "0", "-users", "2"})
+	assert.ErrorContains(t, err, "rate must be positive")
+	assert.ErrorContains(t, err, "at least 3 users")
+	_, _, err = parseArgs([]string{"-mix", "create"})
+	assert.Error(t, err)
+}
+
+// fakeService - сервис в памяти для прогона без базы
+type fakeService struct {
+	domain.Service
+	mu    sync.Mutex
+	users map[int]*domain.User
+	prs   map[int]*domain.PullRequest
+	// Мердж подтверждается, но не сохраняется (потерянное обновление)
+	loseMerges bool
+}
+
+func newFakeService() *fakeService {
+	return &fakeService{users: make(map[int]*domain.User), prs: make(map[int]*domain.PullRequest)}
+}
+
+func (s *fakeService) CreateTeamWithParent(ctx context.Context, name string, parentID *int) (*domain.Team, error) {
+	return &domain.Team{ID: 1, Name: name}, nil
+}
+
+func (s *fakeService) CreateUser(ctx context.Context, name string, teamID int) (*domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := &domain.User{ID: 100 + len(s.users), Name: name, TeamID: teamID, IsActive: true}
+	s.users[u.ID] = u
+	return s.userCopy(u.ID), nil
+}
+
+func (s *fakeService) ActivateUser(ctx context.Context, userID int) (*domain.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[userID].IsActive = true
+	return s.userCopy(userID), nil
+}
+
+// MassDeactivateTeamUsers снимает пользователей с открытых PR без замены
+func (s *fakeService) MassDeactivateTeamUsers(ctx context.Context, teamID int, userIDs ...int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range userIDs {
+		s.users[id].IsActive = false
+		for _, pr := range s.prs {
+			if pr.Status != domain.PRStatusOpen {
+				continue
+			}
+			kept := pr.Reviewers[:0]
+			for _, r := range pr.Reviewers {
+				if r.ID != id {
+					kept = append(kept, r)
+				}
+			}
+			pr.Reviewers = kept
+		}
+	}
+	return nil
+}
+
+func (s *fakeService) GetTeamPolicy(ctx context.Context, teamID int) (*domain.TeamPolicy, error) {
+	return domain.DefaultTeamPolicy(teamID), nil
+}
+
+// CreatePRWithChanges назначает двух активных пользователей с наименьшими ID, кроме автора
+func (s *fakeService) CreatePRWithChanges(ctx context.Context, title string, authorID int, files []string, labels []string) (*domain.PullRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pr := &domain.PullRequest{ID: len(s.prs) + 1, Title: title, Status: domain.PRStatusOpen, AuthorID: authorID}
+	for id := 100; id < 100+len(s.users) && len(pr.Reviewers) < 2; id++ {
+		if id != authorID && s.users[id].IsActive {
+			pr.Reviewers = append(pr.Reviewers, domain.User{ID: id})
+		}
+	}
+	s.prs[pr.ID] = pr
+	return s.prCopy(pr.ID), nil
+}
+
+func (s *fakeService) MergePR(ctx context.Context, prID int) (*domain.PullRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pr := s.prCopy(prID)
+	pr.Status = domain.PRStatusMerged
+	if !s.loseMerges {
+		s.prs[prID].Status = domain.PRStatusMerged
+	}
+	return pr, nil
+}
+
+func (s *fakeService) RerollReviewer(ctx context.Context, prID int, oldReviewerID int) (*domain.PullRequest, error) {
+	return nil, domain.ErrNoReviewersFound
+}
+
+func (s *fakeService) GetReviewerPRs(ctx context.Context, reviewerID int) ([]domain.PullRequest, error) {
+	return nil, nil
+}
+
+func (s *fakeService) GetPR(ctx context.Context, prID int) (*domain.PullRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.prCopy(prID), nil
+}
+
+// prCopy - снимок PR с актуальными данными ревьюеров (ответ сериализуется уже без блокировки)
+func (s *fakeService) prCopy(prID int) *domain.PullRequest {
+	pr := *s.prs[prID]
+	pr.Reviewers = nil
+	for _, r := range s.prs[prID].Reviewers {
+		pr.Reviewers = append(pr.Reviewers, *s.userCopy(r.ID))
+	}
+	return &pr
+}
+
+func (s *fakeService) userCopy(userID int) *domain.User {
+	u := *s.users[userID]
+	return &u
+}
+
+func TestRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	fake := newFakeService()
+	srv := httptest.NewServer(api.SetupRouter(api.NewHandler(fake)))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "report.json")
+	var stdout bytes.Buffer
+	args := []string{"-url", srv.URL, "-rate", "300", "-duration", "300ms", "-warmup", "50ms", "-seed", "1",
+		"-mix", "create=3,merge=2,reroll=1,list=1", "-slo-p99", "1s", "-slo-success", "99", "-json", jsonPath}
+	assert.Equal(t, exitOK, run(args, &stdout))
+	assert.Contains(t, stdout.String(), "| create |")
+	assert.Contains(t, stdout.String(), "**Result: PASS**")
+	assert.Contains(t, stdout.String(), "race rounds: no violations")
+
+	data, err := os.ReadFile(jsonPath)
+	assert.NoError(t, err)
+	var rep Report
+	assert.NoError(t, json.Unmarshal(data, &rep))
+	assert.True(t, rep.Passed)
+	assert.Greater(t, rep.Requests, uint64(0))
+	assert.Equal(t, uint64(0), rep.Errors)
+	if assert.NotNil(t, rep.Checks) {
+		assert.Equal(t, 10, rep.Checks.RaceRounds)
+		assert.Equal(t, len(fake.prs)-10, rep.Checks.CheckedPRs)
+		assert.Empty(t, rep.Checks.Violations)
+	}
+	// Отказ в переназначении - не ошибка сервиса
+	for _, op := range rep.Operations {
+		if op.Name == opReroll {
+			assert.Equal(t, op.Requests, op.Rejected)
+		}
+	}
+
+	// Недостижимая цель - код завершения 1
+	args = []string{"-url", srv.URL, "-rate", "100", "-duration", "100ms", "-slo-p99", "1us", "-skip-checks"}
+	assert.Equal(t, exitSLOFailed, run(args, &stdout))
+
+	// Потерянный мердж - нарушение инварианта с номером PR
+	fake.mu.Lock()
+	fake.loseMerges = true
+	fake.mu.Unlock()
+	stdout.Reset()
+	args = []string{"-url", srv.URL, "-rate", "100", "-duration", "100ms", "-mix", "create=1", "-races", "2"}
+	assert.Equal(t, exitViolation, run(args, &stdout))
+	assert.Regexp(t, `\| lost_update \| \d+ \| merge was acknowledged, but status is OPEN \|`, stdout.String())
+
+	// Сервер недоступен - ошибка подготовки
+	assert.Equal(t, exitSetup, run([]string{"-url", "http://127.0.0.1:1"}, &stdout))
+}
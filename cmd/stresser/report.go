@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Report - итоги прогона
+type Report struct {
+	Scenario  string    `json:"scenario"`
+	StartedAt time.Time `json:"started_at"`
+	Mix       string    `json:"mix"`
+	// Длительность замера (без прогрева), секунды
+	DurationSeconds float64 `json:"duration_seconds"`
+	TargetRate      float64 `json:"target_rate"`
+	AchievedRate    float64 `json:"achieved_rate"`
+
+	Requests uint64 `json:"requests"`
+	Errors   uint64 `json:"errors"`
+	Rejected uint64 `json:"rejected"`
+	// Запросы, не отправленные из-за предела одновременных запросов (считаются ошибками)
+	Dropped     uint64  `json:"dropped"`
+	SuccessRate float64 `json:"success_rate"`
+
+	Operations []OperationReport `json:"operations"`
+	SLO        []SLOResult       `json:"slo"`
+	// Итог проверки SLO (инварианты в него не входят)
+	Passed bool `json:"passed"`
+	// Проверка инвариантов (nil - пропущена)
+	Checks *Checks `json:"checks,omitempty"`
+}
+
+// OperationReport - результаты операции; задержки в миллисекундах
+type OperationReport struct {
+	Name     string  `json:"name"`
+	Requests uint64  `json:"requests"`
+	Errors   uint64  `json:"errors"`
+	Rejected uint64  `json:"rejected"`
+	MeanMs   float64 `json:"mean_ms"`
+	P50Ms    float64 `json:"p50_ms"`
+	P95Ms    float64 `json:"p95_ms"`
+	P99Ms    float64 `json:"p99_ms"`
+	MaxMs    float64 `json:"max_ms"`
+	// Самые частые ошибки
+	TopErrors []ErrorCount `json:"top_errors,omitempty"`
+}
+
+type ErrorCount struct {
+	Error string `json:"error"`
+	Count int    `json:"count"`
+}
+
+// SLOResult - проверка одной цели
+type SLOResult struct {
+	Name   string `json:"name"`
+	Target string `json:"target"`
+	Actual string `json:"actual"`
+	Passed bool   `json:"passed"`
+}
+
+const topErrors = 3
+
+// buildReport собирает отчет и проверяет цели сценария
+func buildReport(r *runner, startedAt time.Time, measured time.Duration) *Report {
+	sc := r.sc
+	rep := &Report{
+		Scenario:        sc.Name,
+		StartedAt:       startedAt,
+		Mix:             sc.mix(),
+		DurationSeconds: measured.Seconds(),
+		TargetRate:      sc.Rate,
+		Dropped:         r.dropped.Load(),
+		Passed:          true,
+	}
+
+	for _, name := range knownOperations {
+		stats := r.stats[name]
+		op := OperationReport{
+			Name:     name,
+			Errors:   stats.errors.Load(),
+			Rejected: stats.rejected.Load(),
+			MeanMs:   ms(stats.latency.mean()),
+			P50Ms:    ms(stats.latency.percentile(50)),
+			P95Ms:    ms(stats.latency.percentile(95)),
+			P99Ms:    ms(stats.latency.percentile(99)),
+			MaxMs:    ms(stats.latency.maxValue()),
+		}
+		op.Requests = stats.latency.count() + op.Errors
+		if op.Requests == 0 {
+			continue
+		}
+		op.TopErrors = stats.topErrors()
+		rep.Operations = append(rep.Operations, op)
+		rep.Requests += op.Requests
+		rep.Errors += op.Errors
+		rep.Rejected += op.Rejected
+
+		if target := sc.p99Target(name); target > 0 {
+			rep.addSLO(SLOResult{
+				Name:   name + " p99",
+				Target: fmt.Sprintf("<= %s", target),
+				Actual: fmt.Sprintf("%.1fms", op.P99Ms),
+				Passed: op.P99Ms <= ms(target),
+			})
+		}
+	}
+
+	sent := rep.Requests + rep.Dropped
+	if measured > 0 {
+		rep.AchievedRate = float64(rep.Requests) / measured.Seconds()
+	}
+	if sent > 0 {
+		rep.SuccessRate = 100 * float64(sent-rep.Errors-rep.Dropped) / float64(sent)
+	}
+	if sc.SLO.SuccessRate > 0 {
+		rep.addSLO(SLOResult{
+			Name:   "success rate",
+			Target: fmt.Sprintf(">= %g%%", sc.SLO.SuccessRate),
+			Actual: fmt.Sprintf("%.3f%%", rep.SuccessRate),
+			Passed: sent > 0 && rep.SuccessRate >= sc.SLO.SuccessRate,
+		})
+	}
+	return rep
+}
+
+func (rep *Report) addSLO(res SLOResult) {
+	rep.SLO = append(rep.SLO, res)
+	rep.Passed = rep.Passed && res.Passed
+}
+
+func (s *opStats) topErrors() []ErrorCount {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counts := make([]ErrorCount, 0, len(s.errorSamples))
+	for msg, n := range s.errorSamples {
+		counts = append(counts, ErrorCount{Error: msg, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Error < counts[j].Error
+	})
+	if len(counts) > topErrors {
+		counts = counts[:topErrors]
+	}
+	return counts
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func (rep *Report) writeJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rep)
+}
+
+func (rep *Report) writeMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Stress test: %s\n\n", rep.Scenario)
+	fmt.Fprintf(&b, "- Started: %s\n", rep.StartedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- Mix: %s\n", rep.Mix)
+	fmt.Fprintf(&b, "- Duration: %.1fs, rate %.2f/s (target %g/s)\n", rep.DurationSeconds, rep.AchievedRate, rep.TargetRate)
+	fmt.Fprintf(&b, "- Requests: %d, errors: %d, rejected: %d, dropped: %d, success rate: %.3f%%\n\n",
+		rep.Requests, rep.Errors, rep.Rejected, rep.Dropped, rep.SuccessRate)
+
+	b.WriteString("| Operation | Requests | Errors | Rejected | Mean, ms | p50, ms | p95, ms | p99, ms | Max, ms |\n")
+	b.WriteString("|---|---:|---:|---:|---:|---:|---:|---:|---:|\n")
+	for _, op := range rep.Operations {
+		fmt.Fprintf(&b, "| %s | %d | %d | %d | %.1f | %.1f | %.1f | %.1f | %.1f |\n",
+			op.Name, op.Requests, op.Errors, op.Rejected, op.MeanMs, op.P50Ms, op.P95Ms, op.P99Ms, op.MaxMs)
+	}
+
+	if len(rep.SLO) > 0 {
+		b.WriteString("\n## SLO\n\n| Objective | Target | Actual | Result |\n|---|---|---|---|\n")
+		for _, s := range rep.SLO {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", s.Name, s.Target, s.Actual, passFail(s.Passed))
+		}
+		fmt.Fprintf(&b, "\n**Result: %s**\n", passFail(rep.Passed))
+	}
+
+	if c := rep.Checks; c != nil {
+		fmt.Fprintf(&b, "\n## Invariants\n\nChecked %d PRs, %d race rounds", c.CheckedPRs, c.RaceRounds)
+		if c.Failed > 0 {
+			fmt.Fprintf(&b, ", %d could not be checked", c.Failed)
+		}
+		if len(c.Violations) == 0 {
+			b.WriteString(": no violations\n")
+		} else {
+			fmt.Fprintf(&b, ": %d violations\n\n| Invariant | PR | Details |\n|---|---:|---|\n", len(c.Violations))
+			for _, v := range c.Violations {
+				fmt.Fprintf(&b, "| %s | %d | %s |\n", v.Invariant, v.PRID, v.Details)
+			}
+		}
+	}
+
+	var errorLines []string
+	for _, op := range rep.Operations {
+		for _, e := range op.TopErrors {
+			errorLines = append(errorLines, fmt.Sprintf("- %s: %s (%d)", op.Name, e.Error, e.Count))
+		}
+	}
+	if len(errorLines) > 0 {
+		b.WriteString("\n## Top errors\n\n" + strings.Join(errorLines, "\n") + "\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func passFail(ok bool) string {
+	if ok {
+		return "PASS"
+	}
+	return "FAIL"
+}
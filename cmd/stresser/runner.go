@@ -0,0 +1,348 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/Shishlyannikovvv/project-avito/pkg/client"
+)
+
+// opStats - результаты одной операции
+type opStats struct {
+	latency histogram
+	// Отказы бизнес-логики (409/400 с ошибкой домена): ответ корректный, операция просто неприменима
+	rejected atomic.Uint64
+	errors   atomic.Uint64
+
+	mu sync.Mutex
+	// Число ошибок по тексту (для отчета)
+	errorSamples map[string]int
+}
+
+func (s *opStats) fail(err error) {
+	s.errors.Add(1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.errorSamples == nil {
+		s.errorSamples = make(map[string]int)
+	}
+	s.errorSamples[err.Error()]++
+}
+
+// openPR - открытый PR из пула прогона
+type openPR struct {
+	id        int
+	reviewers []int
+}
+
+// runner выполняет сценарий против работающего сервиса
+type runner struct {
+	sc     *Scenario
+	api    *client.Client
+	teamID int
+	users  []domain.User
+
+	rngMu sync.Mutex
+	rng   *rand.Rand
+
+	poolMu sync.Mutex
+	open   []openPR
+	// Все созданные PR и PR с подтвержденным мерджем с ревьюерами на момент мерджа (для проверки инвариантов)
+	created []int
+	merged  map[int][]int
+
+	stats map[string]*opStats
+	// Запросы, отброшенные из-за предела одновременных запросов
+	dropped atomic.Uint64
+	// Число запросов в полете
+	inFlight atomic.Int64
+	// Фоновые запросы вне замера (возврат деактивированных пользователей)
+	background sync.WaitGroup
+}
+
+func newRunner(sc *Scenario, api *client.Client) *runner {
+	seed := sc.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	r := &runner{sc: sc, api: api, rng: rand.New(rand.NewSource(seed)), merged: make(map[int][]int), stats: make(map[string]*opStats)}
+	for _, name := range knownOperations {
+		r.stats[name] = &opStats{}
+	}
+	return r
+}
+
+// setup создает тестовую команду и пользователей
+func (r *runner) setup(ctx context.Context) error {
+	team, err := r.api.CreateTeam(ctx, fmt.Sprintf("Stresser_%s_%d", r.sc.Name, time.Now().UnixNano()), nil)
+	if err != nil {
+		return fmt.Errorf("create team: %w", err)
+	}
+	r.teamID = team.ID
+	for i := 0; i < r.sc.Users; i++ {
+		u, err := r.api.CreateUser(ctx, fmt.Sprintf("StressUser_%d", i), team.ID)
+		if err != nil {
+			return fmt.Errorf("create user %d: %w", i, err)
+		}
+		r.users = append(r.users, *u)
+	}
+	return nil
+}
+
+// run подает запросы по расписанию до конца прогрева и замера (или до отмены ctx) и ждет ответов.
+// Задержка считается от запланированного момента отправки, а не от фактического: если генератор
+// не успевает, ожидание в очереди тоже попадает в задержку (без "coordinated omission").
+func (r *runner) run(ctx context.Context) time.Duration {
+	start := time.Now()
+	measureFrom := start.Add(r.sc.WarmUp)
+	end := measureFrom.Add(r.sc.Duration)
+
+	var wg sync.WaitGroup
+	next := start
+	for {
+		next = next.Add(r.interval())
+		if next.After(end) {
+			break
+		}
+		if wait := time.Until(next); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+			case <-timer.C:
+			}
+		}
+		if ctx.Err() != nil {
+			end = time.Now()
+			break
+		}
+
+		measured := !next.Before(measureFrom)
+		if r.inFlight.Add(1) > int64(r.sc.MaxInFlight) {
+			r.inFlight.Add(-1)
+			if measured {
+				r.dropped.Add(1)
+			}
+			continue
+		}
+		wg.Add(1)
+		go func(scheduled time.Time, op string) {
+			defer wg.Done()
+			defer r.inFlight.Add(-1)
+			r.execute(ctx, op, scheduled, measured)
+		}(next, r.pick())
+	}
+	wg.Wait()
+	r.background.Wait()
+	return end.Sub(measureFrom)
+}
+
+// interval - время до следующего запроса пуассоновского потока
+func (r *runner) interval() time.Duration {
+	r.rngMu.Lock()
+	defer r.rngMu.Unlock()
+	return time.Duration(r.rng.ExpFloat64() / r.sc.Rate * float64(time.Second))
+}
+
+// pick выбирает операцию с вероятностью по весу
+func (r *runner) pick() string {
+	total := 0.0
+	for _, op := range r.sc.Operations {
+		total += op.Weight
+	}
+	r.rngMu.Lock()
+	x := r.rng.Float64() * total
+	r.rngMu.Unlock()
+	for _, op := range r.sc.Operations {
+		if x < op.Weight {
+			return op.Name
+		}
+		x -= op.Weight
+	}
+	return r.sc.Operations[len(r.sc.Operations)-1].Name
+}
+
+func (r *runner) randomIndex(n int) int {
+	r.rngMu.Lock()
+	defer r.rngMu.Unlock()
+	return r.rng.Intn(n)
+}
+
+func (r *runner) execute(ctx context.Context, op string, scheduled time.Time, measured bool) {
+	ctx, cancel := context.WithTimeout(ctx, r.sc.Timeout)
+	defer cancel()
+
+	// Без открытых PR мерджить и переназначать нечего - вместо этого создаем PR
+	var pr openPR
+	switch op {
+	case opMerge:
+		var ok bool
+		if pr, ok = r.takeOpen(); !ok {
+			op = opCreate
+		}
+	case opReroll:
+		var ok bool
+		if pr, ok = r.peekOpen(); !ok {
+			op = opCreate
+		}
+	}
+
+	var err error
+	switch op {
+	case opCreate:
+		err = r.createPR(ctx)
+	case opMerge:
+		var merged *domain.PullRequest
+		if merged, err = r.api.MergePR(ctx, pr.id); err == nil {
+			r.markMerged(merged)
+		}
+	case opReroll:
+		err = r.reroll(ctx, pr)
+	case opList:
+		_, err = r.api.GetReviewerPRs(ctx, r.users[r.randomIndex(len(r.users))].ID)
+	case opDeactivate:
+		err = r.deactivate(ctx)
+	}
+	elapsed := time.Since(scheduled)
+
+	if !measured {
+		return
+	}
+	stats := r.stats[op]
+	switch {
+	case err == nil:
+		stats.latency.record(elapsed)
+	case isRejection(err):
+		stats.latency.record(elapsed)
+		stats.rejected.Add(1)
+	default:
+		stats.fail(err)
+	}
+}
+
+// isRejection - ответ с ошибкой домена (не ошибка сервиса): PR уже смерджен, нет свободных ревьюеров и т.п.
+func isRejection(err error) bool {
+	var apiErr *client.APIError
+	return errors.As(err, &apiErr) && apiErr.Err != nil && apiErr.StatusCode < http.StatusInternalServerError &&
+		!errors.Is(err, client.ErrUnauthorized) && !errors.Is(err, client.ErrRateLimited)
+}
+
+func (r *runner) createPR(ctx context.Context) error {
+	author := r.users[r.randomIndex(len(r.users))]
+	pr, err := r.api.CreatePR(ctx, fmt.Sprintf("Stress PR by %d", author.ID), author.ID, nil, nil)
+	if err != nil {
+		return err
+	}
+	r.addOpen(pr)
+	return nil
+}
+
+func (r *runner) reroll(ctx context.Context, pr openPR) error {
+	if len(pr.reviewers) == 0 {
+		return nil
+	}
+	updated, err := r.api.RerollReviewer(ctx, pr.id, pr.reviewers[r.randomIndex(len(pr.reviewers))])
+	if err != nil {
+		return err
+	}
+	r.updateOpen(updated)
+	return nil
+}
+
+// deactivate деактивирует случайного участника и сразу активирует его обратно, чтобы не истощать команду.
+// В замер входит только деактивация (с переназначением его ревью).
+func (r *runner) deactivate(ctx context.Context) error {
+	user := r.users[r.randomIndex(len(r.users))]
+	if err := r.api.MassDeactivateTeamUsers(ctx, r.teamID, user.ID); err != nil {
+		return err
+	}
+	r.background.Add(1)
+	go func() {
+		defer r.background.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), r.sc.Timeout)
+		defer cancel()
+		if _, err := r.api.ActivateUser(ctx, user.ID); err != nil {
+			log.Printf("Failed to reactivate user %d: %v", user.ID, err)
+		}
+	}()
+	return nil
+}
+
+// --- Пул открытых PR ---
+
+func (r *runner) addOpen(pr *domain.PullRequest) {
+	r.poolMu.Lock()
+	defer r.poolMu.Unlock()
+	r.open = append(r.open, openPR{id: pr.ID, reviewers: reviewerIDs(pr)})
+	r.created = append(r.created, pr.ID)
+}
+
+// markMerged запоминает подтвержденный мердж вместе с ревьюерами из ответа
+func (r *runner) markMerged(pr *domain.PullRequest) {
+	r.poolMu.Lock()
+	defer r.poolMu.Unlock()
+	r.merged[pr.ID] = reviewerIDs(pr)
+}
+
+// takeOpen забирает случайный открытый PR из пула (для мерджа)
+func (r *runner) takeOpen() (openPR, bool) {
+	r.poolMu.Lock()
+	defer r.poolMu.Unlock()
+	if len(r.open) == 0 {
+		return openPR{}, false
+	}
+	i := r.randomIndex(len(r.open))
+	pr := r.open[i]
+	r.open[i] = r.open[len(r.open)-1]
+	r.open = r.open[:len(r.open)-1]
+	return pr, true
+}
+
+// peekOpen возвращает случайный открытый PR, оставляя его в пуле
+func (r *runner) peekOpen() (openPR, bool) {
+	r.poolMu.Lock()
+	defer r.poolMu.Unlock()
+	if len(r.open) == 0 {
+		return openPR{}, false
+	}
+	pr := r.open[r.randomIndex(len(r.open))]
+	pr.reviewers = append([]int(nil), pr.reviewers...)
+	return pr, true
+}
+
+func (r *runner) updateOpen(pr *domain.PullRequest) {
+	r.poolMu.Lock()
+	defer r.poolMu.Unlock()
+	for i := range r.open {
+		if r.open[i].id == pr.ID {
+			r.open[i].reviewers = reviewerIDs(pr)
+			return
+		}
+	}
+}
+
+func reviewerIDs(pr *domain.PullRequest) []int {
+	ids := make([]int, 0, len(pr.Reviewers))
+	for _, u := range pr.Reviewers {
+		ids = append(ids, u.ID)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// newHTTPClient - клиент с пулом соединений под предел одновременных запросов
+// (по умолчанию на хост держится 2 простаивающих соединения, остальные открывались бы заново)
+func newHTTPClient(sc *Scenario) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = sc.MaxInFlight
+	return &http.Client{Transport: transport, Timeout: sc.Timeout}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Операции нагрузки
+const (
+	opCreate     = "create"     // создание PR случайным автором
+	opMerge      = "merge"      // мердж открытого PR
+	opReroll     = "reroll"     // замена случайного ревьюера открытого PR
+	opList       = "list"       // PR ревьюера
+	opDeactivate = "deactivate" // массовая деактивация одного участника (затем он активируется обратно)
+)
+
+var knownOperations = []string{opCreate, opMerge, opReroll, opList, opDeactivate}
+
+// Scenario - описание нагрузки (файл YAML/JSON, поверх него флаги)
+type Scenario struct {
+	Name   string `yaml:"name" json:"name"`
+	URL    string `yaml:"url" json:"url"`
+	APIKey string `yaml:"api_key" json:"-"`
+	// Интенсивность потока запросов в секунду. Поток открытый: запросы отправляются по расписанию
+	// (пуассоновский поток), не дожидаясь ответов на предыдущие.
+	Rate     float64       `yaml:"rate" json:"rate"`
+	Duration time.Duration `yaml:"duration" json:"duration"`
+	// Прогрев: запросы идут, но в отчет не попадают
+	WarmUp time.Duration `yaml:"warmup" json:"warmup"`
+	// Сколько пользователей создать в тестовой команде
+	Users int `yaml:"users" json:"users"`
+	// Предел одновременных запросов; сверх него запросы отбрасываются и считаются неуспешными
+	MaxInFlight int           `yaml:"max_in_flight" json:"max_in_flight"`
+	Timeout     time.Duration `yaml:"timeout" json:"timeout"`
+	// Зерно генератора случайных чисел (0 - от времени запуска)
+	Seed       int64       `yaml:"seed" json:"seed"`
+	Operations []Operation `yaml:"operations" json:"operations"`
+	SLO        SLO         `yaml:"slo" json:"slo"`
+	// Раунды гонок переназначения, мерджа и деактивации после нагрузки
+	Races int `yaml:"races" json:"races"`
+	// Не проверять инварианты после прогона (и не запускать гонки)
+	SkipChecks bool `yaml:"skip_checks" json:"skip_checks"`
+}
+
+// Operation - операция с весом в смеси и необязательным собственным порогом p99
+type Operation struct {
+	Name   string        `yaml:"name" json:"name"`
+	Weight float64       `yaml:"weight" json:"weight"`
+	P99    time.Duration `yaml:"p99" json:"p99,omitempty"`
+}
+
+// SLO - цели прогона; нулевое значение - цель не проверяется
+type SLO struct {
+	P99 time.Duration `yaml:"p99" json:"p99"`
+	// Доля успешных запросов в процентах, например 99.9
+	SuccessRate float64 `yaml:"success_rate" json:"success_rate"`
+}
+
+// defaultScenario - прежнее поведение стрессера: 5 RPS 10 секунд на 10 пользователях, создание и мердж PR
+func defaultScenario() *Scenario {
+	return &Scenario{
+		Name:        "default",
+		URL:         "http://localhost:8080",
+		Rate:        5,
+		Duration:    10 * time.Second,
+		Users:       10,
+		MaxInFlight: 100,
+		Timeout:     5 * time.Second,
+		Operations:  []Operation{{Name: opCreate, Weight: 1}, {Name: opMerge, Weight: 1}},
+		SLO:         SLO{P99: 300 * time.Millisecond, SuccessRate: 99.9},
+		Races:       10,
+	}
+}
+
+// loadScenario читает сценарий из файла поверх значений по умолчанию (JSON - подмножество YAML)
+func loadScenario(path string) (*Scenario, error) {
+	sc := defaultScenario()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// Смесь операций из файла заменяет смесь по умолчанию целиком
+	sc.Operations = nil
+	if err := yaml.Unmarshal(data, sc); err != nil {
+		return nil, fmt.Errorf("invalid scenario %s: %w", path, err)
+	}
+	return sc, nil
+}
+
+// parseMix разбирает смесь операций вида "create=5,merge=3,reroll=1"
+func parseMix(s string) ([]Operation, error) {
+	var ops []Operation
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, weight, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid mix entry %q, expected name=weight", part)
+		}
+		w, err := strconv.ParseFloat(weight, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight for %s: %w", name, err)
+		}
+		ops = append(ops, Operation{Name: strings.TrimSpace(name), Weight: w})
+	}
+	return ops, nil
+}
+
+func (sc *Scenario) validate() error {
+	var errs []error
+	if sc.Rate <= 0 {
+		errs = append(errs, errors.New("rate must be positive"))
+	}
+	if sc.Duration <= 0 {
+		errs = append(errs, errors.New("duration must be positive"))
+	}
+	if sc.WarmUp < 0 {
+		errs = append(errs, errors.New("warmup must not be negative"))
+	}
+	if sc.Users < 3 {
+		errs = append(errs, errors.New("at least 3 users are needed to assign reviewers"))
+	}
+	if sc.MaxInFlight <= 0 {
+		errs = append(errs, errors.New("max_in_flight must be positive"))
+	}
+	if sc.Timeout <= 0 {
+		errs = append(errs, errors.New("timeout must be positive"))
+	}
+	if sc.SLO.SuccessRate < 0 || sc.SLO.SuccessRate > 100 {
+		errs = append(errs, errors.New("slo.success_rate must be a percentage"))
+	}
+	if sc.Races < 0 {
+		errs = append(errs, errors.New("races must not be negative"))
+	}
+
+	total := 0.0
+	seen := make(map[string]bool)
+	for _, op := range sc.Operations {
+		switch {
+		case !isKnownOperation(op.Name):
+			errs = append(errs, fmt.Errorf("unknown operation %q (known: %s)", op.Name, strings.Join(knownOperations, ", ")))
+		case seen[op.Name]:
+			errs = append(errs, fmt.Errorf("operation %q is listed twice", op.Name))
+		case op.Weight < 0:
+			errs = append(errs, fmt.Errorf("operation %q has negative weight", op.Name))
+		}
+		seen[op.Name] = true
+		total += op.Weight
+	}
+	if total <= 0 {
+		errs = append(errs, errors.New("operation mix must have a positive total weight"))
+	}
+	return errors.Join(errs...)
+}
+
+func isKnownOperation(name string) bool {
+	for _, known := range knownOperations {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
+
+// p99Target - порог p99 операции: собственный, иначе общий
+func (sc *Scenario) p99Target(name string) time.Duration {
+	for _, op := range sc.Operations {
+		if op.Name == name && op.P99 > 0 {
+			return op.P99
+		}
+	}
+	return sc.SLO.P99
+}
+
+// mix - смесь операций в порядке убывания веса (для стабильного описания в отчете)
+func (sc *Scenario) mix() string {
+	ops := append([]Operation(nil), sc.Operations...)
+	sort.SliceStable(ops, func(i, j int) bool { return ops[i].Weight > ops[j].Weight })
+	parts := make([]string, 0, len(ops))
+	for _, op := range ops {
+		parts = append(parts, fmt.Sprintf("%s=%g", op.Name, op.Weight))
+	}
+	return strings.Join(parts, ",")
+}
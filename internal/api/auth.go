@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// authOrgKey - ключ gin.Context с организацией, подтвержденной API-ключом (см. RateLimit)
+const authOrgKey = "auth_org_id"
+
+// OrgAuthenticator определяет организацию по API-ключу (реализуется service.Manager)
+type OrgAuthenticator interface {
+	AuthenticateOrg(ctx context.Context, apiKey string) (*domain.Organization, error)
+}
+
+// Authenticate - middleware, ограничивающее запрос организацией API-ключа (Authorization: Bearer <ключ>).
+// Неизвестный ключ - 401. Запрос без ключа работает с domain.DefaultOrgID, а при required (AUTH_REQUIRED) - 401.
+func Authenticate(auth OrgAuthenticator, required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || apiKey == "" {
+			if required {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "API key required"})
+				return
+			}
+			c.Next()
+			return
+		}
+
+		org, err := auth.AuthenticateOrg(c.Request.Context(), apiKey)
+		if errors.Is(err, domain.ErrOrganizationNotFound) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+			return
+		}
+		if err != nil {
+			log.Printf("Authentication error: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		c.Set(authOrgKey, org.ID)
+		c.Request = c.Request.WithContext(domain.WithOrgID(c.Request.Context(), org.ID))
+		c.Next()
+	}
+}
+
+// AdminAuth - middleware для операций над всей установкой (резервная копия и т.п.): доступ по токену
+// администратора (Authorization: Bearer <токен>), запрос не ограничен организацией.
+// Пустой token - операции отключены (403).
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Admin API is disabled"})
+			return
+		}
+		got, _ := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid admin token"})
+			return
+		}
+
+		c.Request = c.Request.WithContext(domain.WithAllOrgs(c.Request.Context()))
+		c.Next()
+	}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type keyAuthenticator map[string]int
+
+func (a keyAuthenticator) AuthenticateOrg(ctx context.Context, apiKey string) (*domain.Organization, error) {
+	id, ok := a[apiKey]
+	if !ok {
+		return nil, domain.ErrOrganizationNotFound
+	}
+	return &domain.Organization{ID: id}, nil
+}
+
+func TestAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth := keyAuthenticator{"rk_acme": 7}
+
+	newRouter := func(required bool) *gin.Engine {
+		router := gin.New()
+		router.Use(Authenticate(auth, required))
+		router.GET("/org", func(c *gin.Context) {
+			orgID, _ := domain.OrgIDFromContext(c.Request.Context())
+			c.String(http.StatusOK, strconv.Itoa(orgID))
+		})
+		return router
+	}
+	send := func(router *gin.Engine, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/org", nil)
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	router := newRouter(false)
+	w := send(router, "rk_acme")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "7", w.Body.String())
+
+	// Без ключа - организация по умолчанию
+	w = send(router, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, strconv.Itoa(domain.DefaultOrgID), w.Body.String())
+
+	assert.Equal(t, http.StatusUnauthorized, send(router, "rk_unknown").Code)
+
+	router = newRouter(true)
+	assert.Equal(t, http.StatusUnauthorized, send(router, "").Code)
+	assert.Equal(t, http.StatusOK, send(router, "rk_acme").Code)
+}
+
+func TestAdminAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	send := func(token, header string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.GET("/admin", AdminAuth(token), func(c *gin.Context) {
+			_, scoped := domain.OrgIDFromContext(c.Request.Context())
+			c.String(http.StatusOK, strconv.FormatBool(scoped))
+		})
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		if header != "" {
+			req.Header.Set("Authorization", "Bearer "+header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("s3cret", "s3cret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "false", w.Body.String(), "admin requests span all organizations")
+	assert.Equal(t, http.StatusUnauthorized, send("s3cret", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, send("s3cret", "rk_acme").Code)
+	assert.Equal(t, http.StatusForbidden, send("", "").Code)
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// Колонки CSV справочника (экспорт пишет их в этом порядке, импорт принимает в любом)
+var directoryColumns = []string{"team", "user", "login", "email", "chat_handle", "active"}
+
+// readDirectoryCSV разбирает CSV с заголовком; обязательна только колонка team
+func readDirectoryCSV(r io.Reader) ([]domain.DirectoryRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("empty CSV")
+	}
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !isDirectoryColumn(name) {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		index[name] = i
+	}
+	if _, ok := index["team"]; !ok {
+		return nil, fmt.Errorf("missing column \"team\"")
+	}
+
+	var records []domain.DirectoryRecord
+	for line := 1; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			if i, ok := index[name]; ok {
+				return row[i]
+			}
+			return ""
+		}
+		rec := domain.DirectoryRecord{
+			Team:       field("team"),
+			User:       field("user"),
+			Login:      field("login"),
+			Email:      field("email"),
+			ChatHandle: field("chat_handle"),
+		}
+		if active := strings.TrimSpace(field("active")); active != "" {
+			v, err := parseActive(active)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: invalid active value %q", line, active)
+			}
+			rec.IsActive = &v
+		}
+		records = append(records, rec)
+	}
+}
+
+// writeDirectoryCSV пишет справочник в формате, который принимает readDirectoryCSV
+func writeDirectoryCSV(w io.Writer, records []domain.DirectoryRecord) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(directoryColumns); err != nil {
+		return err
+	}
+	for _, rec := range records {
+		active := ""
+		if rec.IsActive != nil {
+			active = strconv.FormatBool(*rec.IsActive)
+		}
+		row := []string{rec.Team, rec.User, rec.Login, rec.Email, rec.ChatHandle, active}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// parseActive принимает true/false, 1/0 и yes/no (как их обычно пишут в таблицах)
+func parseActive(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "yes", "y":
+		return true, nil
+	case "no", "n":
+		return false, nil
+	}
+	return strconv.ParseBool(s)
+}
+
+func isDirectoryColumn(name string) bool {
+	for _, c := range directoryColumns {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDirectoryCSV(t *testing.T) {
+	input := "User, Team ,active,email\n" +
+		"Alice,Backend,yes,alice@example.com\n" +
+		",Frontend,,\n" +
+		"Bob,Backend,false,\n"
+
+	records, err := readDirectoryCSV(strings.NewReader(input))
+	assert.NoError(t, err)
+	if assert.Len(t, records, 3) {
+		assert.Equal(t, "Backend", records[0].Team)
+		assert.Equal(t, "alice@example.com", records[0].Email)
+		assert.True(t, *records[0].IsActive)
+		assert.Equal(t, domain.DirectoryRecord{Team: "Frontend"}, records[1])
+		assert.False(t, *records[2].IsActive)
+	}
+
+	// Выгрузка читается обратно без потерь
+	var buf bytes.Buffer
+	assert.NoError(t, writeDirectoryCSV(&buf, records))
+	assert.True(t, strings.HasPrefix(buf.String(), "team,user,login,email,chat_handle,active\n"))
+	again, err := readDirectoryCSV(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, records, again)
+
+	for _, bad := range []string{"", "user\nAlice\n", "team,role\nA,dev\n", "team,active\nA,maybe\n"} {
+		_, err := readDirectoryCSV(strings.NewReader(bad))
+		assert.Error(t, err, bad)
+	}
+}
@@ -1,11 +1,15 @@
 package api
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/Shishlyannikovvv/project-avito/internal/snapshot"
+	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin"
 )
 
@@ -22,6 +26,8 @@ func NewHandler(s domain.Service) *Handler {
 
 type createTeamRequest struct {
 	Name string `json:"name" binding:"required"`
+	// Родительская команда (отдел); не задана - команда верхнего уровня
+	ParentID *int `json:"parent_id"`
 }
 
 func (h *Handler) CreateTeam(c *gin.Context) {
@@ -31,7 +37,7 @@ func (h *Handler) CreateTeam(c *gin.Context) {
 		return
 	}
 
-	team, err := h.service.CreateTeam(c.Request.Context(), req.Name)
+	team, err := h.service.CreateTeamWithParent(c.Request.Context(), req.Name, req.ParentID)
 	if err != nil {
 		handleServiceError(c, err)
 		return
@@ -40,38 +46,919 @@ func (h *Handler) CreateTeam(c *gin.Context) {
 	c.JSON(http.StatusCreated, team)
 }
 
+func (h *Handler) GetTeam(c *gin.Context) {
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	team, err := h.service.GetTeam(c.Request.Context(), teamID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+type updateTeamRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+func (h *Handler) UpdateTeam(c *gin.Context) {
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var req updateTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	team, err := h.service.RenameTeam(c.Request.Context(), teamID, req.Name)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+type archiveTeamRequest struct {
+	// reassign (по умолчанию) | close
+	Strategy string `json:"strategy"`
+}
+
+func (h *Handler) ArchiveTeam(c *gin.Context) {
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var req archiveTeamRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+			return
+		}
+	}
+
+	team, err := h.service.ArchiveTeam(c.Request.Context(), teamID, req.Strategy)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+type moveMembersRequest struct {
+	ToTeamID int `json:"to_team_id" binding:"required"`
+	// Не задано - все участники команды
+	UserIDs []int `json:"user_ids"`
+}
+
+func (h *Handler) MoveTeamMembers(c *gin.Context) {
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var req moveMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	users, err := h.service.MoveTeamMembers(c.Request.Context(), teamID, req.ToTeamID, req.UserIDs...)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"moved": users})
+}
+
+type moveTeamRequest struct {
+	// null - перенос на верхний уровень
+	ParentID *int `json:"parent_id"`
+}
+
+func (h *Handler) MoveTeam(c *gin.Context) {
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var req moveTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	team, err := h.service.MoveTeam(c.Request.Context(), teamID, req.ParentID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+func (h *Handler) GetSubtreeMembers(c *gin.Context) {
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	users, err := h.service.GetSubtreeMembers(c.Request.Context(), teamID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": users})
+}
+
+type teamPolicyRequest struct {
+	MinReviewers      int    `json:"min_reviewers"`
+	MaxReviewers      int    `json:"max_reviewers" binding:"required"`
+	SelfTeamOnly      bool   `json:"self_team_only"`
+	EscalateUpTree    bool   `json:"escalate_up_tree"`
+	FallbackTeamIDs   []int  `json:"fallback_team_ids"`
+	RequiredSeniority string `json:"required_seniority"`
+	// Лимит открытых ревью по умолчанию для участников команды (0 - без лимита)
+	DefaultMaxOpenReviews int `json:"default_max_open_reviews"`
+	// Напоминание и замена зависшего ревьюера, в часах (0 - выключено)
+	RemindAfterHours   int `json:"remind_after_hours"`
+	EscalateAfterHours int `json:"escalate_after_hours"`
+}
+
+func (h *Handler) GetTeamPolicy(c *gin.Context) {
+	idStr := c.Param("id")
+	teamID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	policy, err := h.service.GetTeamPolicy(c.Request.Context(), teamID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *Handler) UpdateTeamPolicy(c *gin.Context) {
+	idStr := c.Param("id")
+	teamID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var req teamPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	policy, err := h.service.UpdateTeamPolicy(c.Request.Context(), &domain.TeamPolicy{
+		TeamID:                teamID,
+		MinReviewers:          req.MinReviewers,
+		MaxReviewers:          req.MaxReviewers,
+		SelfTeamOnly:          req.SelfTeamOnly,
+		EscalateUpTree:        req.EscalateUpTree,
+		FallbackTeamIDs:       req.FallbackTeamIDs,
+		RequiredSeniority:     req.RequiredSeniority,
+		DefaultMaxOpenReviews: req.DefaultMaxOpenReviews,
+		RemindAfterHours:      req.RemindAfterHours,
+		EscalateAfterHours:    req.EscalateAfterHours,
+	})
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *Handler) GetCodeOwners(c *gin.Context) {
+	idStr := c.Param("id")
+	teamID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	rules, err := h.service.GetCodeOwners(c.Request.Context(), teamID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// UploadCodeOwners принимает файл CODEOWNERS как есть (тело запроса - text/plain)
+func (h *Handler) UploadCodeOwners(c *gin.Context) {
+	idStr := c.Param("id")
+	teamID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	report, err := h.service.UploadCodeOwners(c.Request.Context(), teamID, c.Request.Body)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// --- Directory Import/Export ---
+
+// ImportDirectory принимает справочник в CSV (Content-Type: text/csv) или JSON-массивом строк.
+// ?dry_run=true - только отчет. При ошибках в строках ничего не применяется, ответ 422 с отчетом.
+func (h *Handler) ImportDirectory(c *gin.Context) {
+	var records []domain.DirectoryRecord
+	var err error
+	if c.ContentType() == "text/csv" {
+		records, err = readDirectoryCSV(c.Request.Body)
+	} else {
+		err = c.ShouldBindJSON(&records)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	report, err := h.service.ImportDirectory(c.Request.Context(), records, c.Query("dry_run") == "true")
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if report.Failed > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, report)
+}
+
+// ExportDirectory выгружает справочник в формате импорта (?format=csv, по умолчанию JSON)
+func (h *Handler) ExportDirectory(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format"})
+		return
+	}
+
+	records, err := h.service.ExportDirectory(c.Request.Context())
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	if format == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="directory.csv"`)
+		c.Status(http.StatusOK)
+		if err := writeDirectoryCSV(c.Writer, records); err != nil {
+			log.Printf("Export error: %v", err)
+		}
+		return
+	}
+	c.JSON(http.StatusOK, records)
+}
+
+// --- Administration ---
+
+type createOrganizationRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// CreateOrganization создает организацию; API-ключ возвращается только в этом ответе
+func (h *Handler) CreateOrganization(c *gin.Context) {
+	var req createOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	org, apiKey, err := h.service.CreateOrganization(c.Request.Context(), req.Name)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"organization": org, "api_key": apiKey})
+}
+
+// --- Snapshot ---
+
+// ExportSnapshot отдает резервную копию всех организаций (JSON lines, см. пакет snapshot).
+// ?anonymize=true - с псевдонимами вместо персональных данных
+func (h *Handler) ExportSnapshot(c *gin.Context) {
+	snap, err := h.service.ExportSnapshot(c.Request.Context(), c.Query("anonymize") == "true")
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", `attachment; filename="snapshot.jsonl"`)
+	c.Status(http.StatusOK)
+	if err := snapshot.Write(c.Writer, snap, time.Now()); err != nil {
+		log.Printf("Snapshot export error: %v", err)
+	}
+}
+
+// ImportSnapshot восстанавливает резервную копию в пустую базу
+func (h *Handler) ImportSnapshot(c *gin.Context) {
+	snap, err := snapshot.Read(c.Request.Body)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	if err := h.service.ImportSnapshot(c.Request.Context(), snap); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"organizations": len(snap.Organizations),
+		"teams":         len(snap.Teams),
+		"users":         len(snap.Users),
+		"pull_requests": len(snap.PullRequests),
+	})
+}
+
 // --- User Management ---
 
-type createUserRequest struct {
-	Name   string `json:"name" binding:"required"`
-	TeamID int    `json:"team_id" binding:"required"`
+type createUserRequest struct {
+	Name   string `json:"name" binding:"required"`
+	TeamID int    `json:"team_id" binding:"required"`
+}
+
+func (h *Handler) CreateUser(c *gin.Context) {
+	var req createUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	user, err := h.service.CreateUser(c.Request.Context(), req.Name, req.TeamID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+func (h *Handler) DeactivateUser(c *gin.Context) {
+	idStr := c.Param("id")
+	userID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	// ?hard=true - окончательное удаление с обезличиванием вместо деактивации
+	if c.Query("hard") == "true" {
+		err = h.service.HardDeleteUser(c.Request.Context(), userID)
+	} else {
+		err = h.service.DeleteUser(c.Request.Context(), userID)
+	}
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) ActivateUser(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	user, err := h.service.ActivateUser(c.Request.Context(), userID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+type updateUserRequest struct {
+	Name *string `json:"name"`
+	// Перевод в другую основную команду
+	TeamID *int `json:"team_id"`
+}
+
+func (h *Handler) UpdateUser(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req updateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	user, err := h.service.UpdateUser(c.Request.Context(), userID, domain.UserUpdate{Name: req.Name, TeamID: req.TeamID})
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+type setSeniorityRequest struct {
+	Seniority string `json:"seniority" binding:"required"`
+}
+
+func (h *Handler) SetUserSeniority(c *gin.Context) {
+	idStr := c.Param("id")
+	userID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req setSeniorityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	user, err := h.service.SetUserSeniority(c.Request.Context(), userID, req.Seniority)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+type setCapacityRequest struct {
+	// null - использовать лимит команды по умолчанию
+	MaxOpenReviews *int `json:"max_open_reviews"`
+}
+
+func (h *Handler) SetUserMaxOpenReviews(c *gin.Context) {
+	idStr := c.Param("id")
+	userID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req setCapacityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	user, err := h.service.SetUserMaxOpenReviews(c.Request.Context(), userID, req.MaxOpenReviews)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+type setExpertiseRequest struct {
+	Login string   `json:"login"`
+	Tags  []string `json:"tags"`
+}
+
+func (h *Handler) SetUserExpertise(c *gin.Context) {
+	idStr := c.Param("id")
+	userID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req setExpertiseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	user, err := h.service.SetUserExpertise(c.Request.Context(), userID, req.Login, req.Tags)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+type setNotificationsRequest struct {
+	// Пустой адрес - не отправлять писем
+	Email  string   `json:"email"`
+	OptOut []string `json:"opt_out"`
+}
+
+func (h *Handler) SetUserNotifications(c *gin.Context) {
+	idStr := c.Param("id")
+	userID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req setNotificationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	user, err := h.service.SetUserNotifications(c.Request.Context(), userID, req.Email, req.OptOut)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+type setChatHandleRequest struct {
+	// Пустой ник - упоминать по имени
+	Handle string `json:"handle"`
+}
+
+func (h *Handler) SetUserChatHandle(c *gin.Context) {
+	idStr := c.Param("id")
+	userID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req setChatHandleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	user, err := h.service.SetUserChatHandle(c.Request.Context(), userID, req.Handle)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+type massDeactivateRequest struct {
+	// Пустой список - деактивировать всю команду
+	UserIDs []int `json:"user_ids"`
+}
+
+func (h *Handler) MassDeactivate(c *gin.Context) {
+	idStr := c.Param("id")
+	teamID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var req massDeactivateRequest
+	// Тело необязательно
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+			return
+		}
+	}
+
+	if err := h.service.MassDeactivateTeamUsers(c.Request.Context(), teamID, req.UserIDs...); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// --- User Unavailability ---
+
+type unavailabilityRequest struct {
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+	Reason   string    `json:"reason"`
+}
+
+func (h *Handler) AddUnavailability(c *gin.Context) {
+	idStr := c.Param("id")
+	userID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req unavailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	window, err := h.service.AddUnavailability(c.Request.Context(), &domain.UserUnavailability{
+		UserID:   userID,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+		Reason:   req.Reason,
+	})
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, window)
+}
+
+func (h *Handler) ListUnavailability(c *gin.Context) {
+	idStr := c.Param("id")
+	userID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	windows, err := h.service.ListUnavailability(c.Request.Context(), userID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, windows)
+}
+
+func (h *Handler) UpdateUnavailability(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	windowID, err := strconv.Atoi(c.Param("windowId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid unavailability ID"})
+		return
+	}
+
+	var req unavailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	window, err := h.service.UpdateUnavailability(c.Request.Context(), &domain.UserUnavailability{
+		ID:       windowID,
+		UserID:   userID,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+		Reason:   req.Reason,
+	})
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, window)
 }
 
-func (h *Handler) CreateUser(c *gin.Context) {
-	var req createUserRequest
+func (h *Handler) DeleteUnavailability(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	windowID, err := strconv.Atoi(c.Param("windowId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid unavailability ID"})
+		return
+	}
+
+	if err := h.service.DeleteUnavailability(c.Request.Context(), userID, windowID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// --- Notification Templates ---
+
+func (h *Handler) GetNotificationTemplates(c *gin.Context) {
+	idStr := c.Param("id")
+	teamID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	templates, err := h.service.GetNotificationTemplates(c.Request.Context(), teamID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+type notificationTemplateRequest struct {
+	Subject string `json:"subject" binding:"required"`
+	Body    string `json:"body" binding:"required"`
+}
+
+func (h *Handler) SaveNotificationTemplate(c *gin.Context) {
+	idStr := c.Param("id")
+	teamID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var req notificationTemplateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
 		return
 	}
 
-	user, err := h.service.CreateUser(c.Request.Context(), req.Name, req.TeamID)
+	tpl, err := h.service.SaveNotificationTemplate(c.Request.Context(), &domain.NotificationTemplate{
+		TeamID:    teamID,
+		EventType: c.Param("event"),
+		Subject:   req.Subject,
+		Body:      req.Body,
+	})
 	if err != nil {
 		handleServiceError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, user)
+	c.JSON(http.StatusOK, tpl)
 }
 
-func (h *Handler) DeactivateUser(c *gin.Context) {
+func (h *Handler) DeleteNotificationTemplate(c *gin.Context) {
 	idStr := c.Param("id")
-	userID, err := strconv.Atoi(idStr)
+	teamID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	if err := h.service.DeleteNotificationTemplate(c.Request.Context(), teamID, c.Param("event")); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// --- Chat Webhook ---
+
+type chatWebhookRequest struct {
+	URL string `json:"url" binding:"required"`
+}
+
+func (h *Handler) SetTeamChatWebhook(c *gin.Context) {
+	idStr := c.Param("id")
+	teamID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	var req chatWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	webhook, err := h.service.SetTeamChatWebhook(c.Request.Context(), teamID, req.URL)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+func (h *Handler) DeleteTeamChatWebhook(c *gin.Context) {
+	idStr := c.Param("id")
+	teamID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	if err := h.service.DeleteTeamChatWebhook(c.Request.Context(), teamID); err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// --- Team Membership ---
+
+type teamMembershipRequest struct {
+	// По умолчанию участник может и создавать PR, и ревьюить
+	CanAuthor *bool `json:"can_author"`
+	CanReview *bool `json:"can_review"`
+}
+
+func (h *Handler) GetTeamMembers(c *gin.Context) {
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	members, err := h.service.GetTeamMembers(c.Request.Context(), teamID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": members})
+}
+
+func (h *Handler) SetTeamMembership(c *gin.Context) {
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	userID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req teamMembershipRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+			return
+		}
+	}
+
+	membership := &domain.TeamMembership{TeamID: teamID, UserID: userID, CanAuthor: true, CanReview: true}
+	if req.CanAuthor != nil {
+		membership.CanAuthor = *req.CanAuthor
+	}
+	if req.CanReview != nil {
+		membership.CanReview = *req.CanReview
+	}
+
+	saved, err := h.service.SetTeamMembership(c.Request.Context(), membership)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, saved)
+}
+
+func (h *Handler) RemoveTeamMember(c *gin.Context) {
+	teamID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	userID, err := strconv.Atoi(c.Param("userId"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
 		return
 	}
 
-	if err := h.service.DeleteUser(c.Request.Context(), userID); err != nil {
+	if err := h.service.RemoveTeamMember(c.Request.Context(), teamID, userID); err != nil {
 		handleServiceError(c, err)
 		return
 	}
@@ -79,11 +966,56 @@ func (h *Handler) DeactivateUser(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+func (h *Handler) GetUserTeams(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	teams, err := h.service.GetUserTeams(c.Request.Context(), userID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"teams": teams})
+}
+
+type setPrimaryTeamRequest struct {
+	TeamID int `json:"team_id" binding:"required"`
+}
+
+func (h *Handler) SetPrimaryTeam(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req setPrimaryTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	user, err := h.service.SetPrimaryTeam(c.Request.Context(), userID, req.TeamID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
 // --- PR Management ---
 
 type createPRRequest struct {
 	Title    string `json:"title" binding:"required"`
 	AuthorID int    `json:"author_id" binding:"required"`
+	// Необязательные: по ним подбираются владельцы кода и эксперты
+	Files  []string `json:"files"`
+	Labels []string `json:"labels"`
 }
 
 func (h *Handler) CreatePR(c *gin.Context) {
@@ -93,7 +1025,7 @@ func (h *Handler) CreatePR(c *gin.Context) {
 		return
 	}
 
-	pr, err := h.service.CreatePR(c.Request.Context(), req.Title, req.AuthorID)
+	pr, err := h.service.CreatePRWithChanges(c.Request.Context(), req.Title, req.AuthorID, req.Files, req.Labels)
 	if err != nil {
 		handleServiceError(c, err)
 		return
@@ -102,6 +1034,40 @@ func (h *Handler) CreatePR(c *gin.Context) {
 	c.JSON(http.StatusCreated, pr)
 }
 
+func (h *Handler) GetPR(c *gin.Context) {
+	idStr := c.Param("id")
+	prID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid PR ID"})
+		return
+	}
+
+	pr, err := h.service.GetPR(c.Request.Context(), prID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pr)
+}
+
+func (h *Handler) GetPRHistory(c *gin.Context) {
+	idStr := c.Param("id")
+	prID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid PR ID"})
+		return
+	}
+
+	history, err := h.service.GetPRHistory(c.Request.Context(), prID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
 func (h *Handler) MergePR(c *gin.Context) {
 	idStr := c.Param("id")
 	prID, err := strconv.Atoi(idStr)
@@ -121,6 +1087,8 @@ func (h *Handler) MergePR(c *gin.Context) {
 
 type rerollReviewerRequest struct {
 	OldReviewerID int `json:"old_reviewer_id" binding:"required"`
+	// Необязательный: конкретный новый ревьюер вместо случайного
+	NewReviewerID int `json:"new_reviewer_id"`
 }
 
 func (h *Handler) RerollReviewer(c *gin.Context) {
@@ -137,7 +1105,60 @@ func (h *Handler) RerollReviewer(c *gin.Context) {
 		return
 	}
 
-	pr, err := h.service.RerollReviewer(c.Request.Context(), prID, req.OldReviewerID)
+	var pr *domain.PullRequest
+	if req.NewReviewerID != 0 {
+		pr, err = h.service.ReplaceReviewer(c.Request.Context(), prID, req.OldReviewerID, req.NewReviewerID)
+	} else {
+		pr, err = h.service.RerollReviewer(c.Request.Context(), prID, req.OldReviewerID)
+	}
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pr)
+}
+
+type addReviewerRequest struct {
+	UserID int `json:"user_id" binding:"required"`
+}
+
+func (h *Handler) AddReviewer(c *gin.Context) {
+	idStr := c.Param("id")
+	prID, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid PR ID"})
+		return
+	}
+
+	var req addReviewerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	pr, err := h.service.AddReviewer(c.Request.Context(), prID, req.UserID)
+	if err != nil {
+		handleServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pr)
+}
+
+func (h *Handler) RemoveReviewer(c *gin.Context) {
+	prID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid PR ID"})
+		return
+	}
+	userID, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	pr, err := h.service.RemoveReviewer(c.Request.Context(), prID, userID)
 	if err != nil {
 		handleServiceError(c, err)
 		return
@@ -163,24 +1184,162 @@ func (h *Handler) GetPRsByReviewer(c *gin.Context) {
 	c.JSON(http.StatusOK, prs)
 }
 
+// --- Event Stream ---
+
+const (
+	// Сколько событий читать из журнала за раз
+	streamBatchSize = 100
+	// Как часто перечитывать журнал (события других реплик не будят подписчиков)
+	streamPollInterval = 2 * time.Second
+	// Комментарий-пинг, чтобы прокси не закрывали простаивающее соединение
+	streamHeartbeat = 15 * time.Second
+)
+
+// StreamEvents отдает доменные события в формате Server-Sent Events.
+// Фильтры: team_id, user_id. При переподключении поток продолжается после Last-Event-ID
+// (заголовок или параметр last_event_id), без него - только новые события.
+func (h *Handler) StreamEvents(c *gin.Context) {
+	var filter domain.EventFilter
+	if teamStr := c.Query("team_id"); teamStr != "" {
+		teamID, err := strconv.Atoi(teamStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+			return
+		}
+		filter.TeamID = teamID
+	}
+	if userStr := c.Query("user_id"); userStr != "" {
+		userID, err := strconv.Atoi(userStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+		filter.UserID = userID
+	}
+
+	ctx := c.Request.Context()
+	cursor := c.GetHeader("Last-Event-ID")
+	if cursor == "" {
+		cursor = c.Query("last_event_id")
+	}
+	var lastID int64
+	if cursor != "" {
+		id, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || id < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Last-Event-ID"})
+			return
+		}
+		lastID = id
+	} else {
+		id, err := h.service.GetLatestEventID(ctx)
+		if err != nil {
+			handleServiceError(c, err)
+			return
+		}
+		lastID = id
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // nginx не должен буферизовать поток
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	poll := time.NewTicker(streamPollInterval)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		// Канал берем до чтения журнала, чтобы не пропустить событие между чтением и ожиданием
+		changed := h.service.EventsChanged()
+		events, err := h.service.GetEventsAfter(ctx, lastID, filter, streamBatchSize)
+		if err != nil {
+			// Ответ уже начат - просто закрываем поток, клиент переподключится с Last-Event-ID
+			if ctx.Err() == nil {
+				log.Printf("Event stream failed: %v", err)
+			}
+			return
+		}
+		for _, e := range events {
+			c.Render(-1, sse.Event{Id: strconv.FormatInt(e.ID, 10), Event: e.Type, Data: e})
+			lastID = e.ID
+		}
+		if len(events) > 0 {
+			c.Writer.Flush()
+		}
+		if len(events) == streamBatchSize {
+			continue // в журнале есть еще
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-changed:
+		case <-poll.C:
+		case <-heartbeat.C:
+			c.Writer.WriteString(": ping\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
 // --- Error Handling Helper ---
 
 func handleServiceError(c *gin.Context, err error) {
 	log.Printf("Service error: %v", err)
-	switch err {
-	case domain.ErrUserNotFound, domain.ErrTeamNotFound, domain.ErrPRNotFound:
-		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
-	case domain.ErrPRAlreadyMerged:
-		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-	case domain.ErrNoReviewersFound:
-		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-	default:
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	status, message := http.StatusInternalServerError, "Internal server error"
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound), errors.Is(err, domain.ErrTeamNotFound),
+		errors.Is(err, domain.ErrPRNotFound), errors.Is(err, domain.ErrUnavailabilityNotFound),
+		errors.Is(err, domain.ErrTemplateNotFound), errors.Is(err, domain.ErrChatWebhookNotFound),
+		errors.Is(err, domain.ErrOrganizationNotFound), errors.Is(err, domain.ErrMembershipNotFound):
+		status, message = http.StatusNotFound, "Resource not found"
+	case errors.Is(err, domain.ErrPRAlreadyMerged), errors.Is(err, domain.ErrTeamAlreadyExists),
+		errors.Is(err, domain.ErrPRClosed), errors.Is(err, domain.ErrTeamArchived):
+		status, message = http.StatusConflict, err.Error()
+	case errors.Is(err, domain.ErrNoReviewersFound):
+		status, message = http.StatusConflict, err.Error()
+	case errors.Is(err, domain.ErrReviewerNotActive), errors.Is(err, domain.ErrReviewerIsAuthor),
+		errors.Is(err, domain.ErrAlreadyReviewer), errors.Is(err, domain.ErrNotReviewer),
+		errors.Is(err, domain.ErrReviewerNotEligible), errors.Is(err, domain.ErrReviewerLimitReached),
+		errors.Is(err, domain.ErrPrimaryTeamMembership), errors.Is(err, domain.ErrAuthorNotAllowed),
+		errors.Is(err, domain.ErrTeamCycle), errors.Is(err, domain.ErrUserDeleted),
+		errors.Is(err, domain.ErrSnapshotNotEmpty), errors.Is(err, domain.ErrAnonymizationKeyMissing):
+		status, message = http.StatusConflict, err.Error()
+	case errors.Is(err, domain.ErrInvalidPolicy), errors.Is(err, domain.ErrInvalidSeniority),
+		errors.Is(err, domain.ErrInvalidPeriod), errors.Is(err, domain.ErrInvalidCapacity),
+		errors.Is(err, domain.ErrInvalidCodeOwners), errors.Is(err, domain.ErrInvalidEmail),
+		errors.Is(err, domain.ErrInvalidEventType), errors.Is(err, domain.ErrInvalidTemplate),
+		errors.Is(err, domain.ErrInvalidWebhookURL), errors.Is(err, domain.ErrInvalidOrgName),
+		errors.Is(err, domain.ErrInvalidUserName), errors.Is(err, domain.ErrInvalidTeamName),
+		errors.Is(err, domain.ErrInvalidStrategy), errors.Is(err, domain.ErrInvalidSnapshot):
+		status, message = http.StatusBadRequest, err.Error()
+	}
+
+	// code - какая именно ошибка домена (например, какой ресурс не найден) для клиентов API
+	body := gin.H{"error": message}
+	if status != http.StatusInternalServerError {
+		body["code"] = domain.ErrorCode(err)
 	}
+	c.JSON(status, body)
 }
 
 func (h *Handler) GetStats(c *gin.Context) {
-	stats, err := h.service.GetReviewerStats(c.Request.Context())
+	var stats map[int]int
+	var err error
+	// ?team_id=N - только ревьюеры из поддерева команды N
+	if teamIDStr := c.Query("team_id"); teamIDStr != "" {
+		teamID, convErr := strconv.Atoi(teamIDStr)
+		if convErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+			return
+		}
+		stats, err = h.service.GetTeamReviewerStats(c.Request.Context(), teamID)
+	} else {
+		stats, err = h.service.GetReviewerStats(c.Request.Context())
+	}
 	if err != nil {
 		handleServiceError(c, err)
 		return
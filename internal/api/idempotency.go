@@ -0,0 +1,151 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// Выставляется на ответе, повторенном из сохраненного
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyStore - хранилище ключей идемпотентности (реализуется storage.Repository)
+type IdempotencyStore interface {
+	CreateIdempotencyRecord(ctx context.Context, record *domain.IdempotencyRecord) (bool, error)
+	GetIdempotencyRecord(ctx context.Context, key string) (*domain.IdempotencyRecord, error)
+	CompleteIdempotencyRecord(ctx context.Context, record *domain.IdempotencyRecord) error
+	DeleteIdempotencyRecord(ctx context.Context, key string) error
+}
+
+// Idempotency - middleware для POST-запросов с заголовком Idempotency-Key.
+// Первый запрос с ключом выполняется и его ответ сохраняется на ttl; повтор с тем же телом
+// получает сохраненный ответ, с другим телом - 422. Пока первый запрос выполняется, повтор получает 409.
+// Ответы 5xx не сохраняются, чтобы запрос можно было повторить; ключ, брошенный упавшим процессом,
+// хранилище отдает заново по истечении короткой аренды.
+func Idempotency(store IdempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key is too long"})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		now := time.Now()
+		record := &domain.IdempotencyRecord{
+			Key:         key,
+			RequestHash: requestHash(c.Request.Method, c.Request.URL.Path, c.Request.URL.RawQuery, body),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		}
+		created, err := store.CreateIdempotencyRecord(ctx, record)
+		if err != nil {
+			log.Printf("Idempotency store error: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+		if !created {
+			replayIdempotent(c, store, record)
+			return
+		}
+
+		// Ответ пишется и клиенту, и в буфер для сохранения
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		// Ключ освобождается, если запрос не дал сохраняемого ответа
+		completed := false
+		defer func() {
+			if !completed {
+				if err := store.DeleteIdempotencyRecord(context.WithoutCancel(ctx), key); err != nil {
+					log.Printf("Failed to release idempotency key: %v", err)
+				}
+			}
+		}()
+
+		c.Next()
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+		record.StatusCode = status
+		record.ContentType = writer.Header().Get("Content-Type")
+		record.Body = writer.body.Bytes()
+		if err := store.CompleteIdempotencyRecord(context.WithoutCancel(ctx), record); err != nil {
+			log.Printf("Failed to save idempotent response: %v", err)
+			return
+		}
+		completed = true
+	}
+}
+
+// replayIdempotent отвечает на повтор запроса с уже занятым ключом
+func replayIdempotent(c *gin.Context, store IdempotencyStore, record *domain.IdempotencyRecord) {
+	stored, err := store.GetIdempotencyRecord(c.Request.Context(), record.Key)
+	switch {
+	case errors.Is(err, domain.ErrIdempotencyKeyNotFound):
+		// Первый запрос только что завершился ошибкой и освободил ключ
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "Request with this Idempotency-Key is being retried, try again"})
+	case err != nil:
+		log.Printf("Idempotency store error: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	case stored.RequestHash != record.RequestHash:
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used with a different request"})
+	case stored.StatusCode == 0:
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "Request with this Idempotency-Key is still in progress"})
+	default:
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(stored.StatusCode, stored.ContentType, stored.Body)
+		c.Abort()
+	}
+}
+
+// requestHash учитывает query: POST /import?dry_run=true и POST /import - разные запросы
+func requestHash(method, path, query string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "?" + query + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingWriter копирует тело ответа в буфер
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryStore - IdempotencyStore в памяти
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]domain.IdempotencyRecord
+}
+
+func (s *memoryStore) CreateIdempotencyRecord(ctx context.Context, record *domain.IdempotencyRecord) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, ok := s.records[record.Key]; ok && existing.ExpiresAt.After(record.CreatedAt) {
+		return false, nil
+	}
+	s.records[record.Key] = *record
+	return true, nil
+}
+
+func (s *memoryStore) GetIdempotencyRecord(ctx context.Context, key string) (*domain.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[key]
+	if !ok {
+		return nil, domain.ErrIdempotencyKeyNotFound
+	}
+	return &record, nil
+}
+
+func (s *memoryStore) CompleteIdempotencyRecord(ctx context.Context, record *domain.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[record.Key] = *record
+	return nil
+}
+
+func (s *memoryStore) DeleteIdempotencyRecord(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+func newIdempotentRouter(store IdempotencyStore, calls *int, status *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Idempotency(store, time.Hour))
+	router.POST("/prs", func(c *gin.Context) {
+		*calls++
+		c.JSON(*status, gin.H{"id": *calls})
+	})
+	return router
+}
+
+func post(router *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	return postTo(router, "/prs", key, body)
+}
+
+func postTo(router *gin.Engine, target, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	calls, status := 0, http.StatusCreated
+	router := newIdempotentRouter(&memoryStore{records: make(map[string]domain.IdempotencyRecord)}, &calls, &status)
+
+	first := post(router, "ci-run-1", `{"title":"A"}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+
+	second := post(router, "ci-run-1", `{"title":"A"}`)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 1, calls)
+
+	// Тот же ключ с другим телом
+	w := post(router, "ci-run-1", `{"title":"B"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, 1, calls)
+
+	// Без ключа запрос выполняется каждый раз
+	post(router, "", `{"title":"A"}`)
+	post(router, "", `{"title":"A"}`)
+	assert.Equal(t, 3, calls)
+}
+
+func TestIdempotencyServerErrorReleasesKey(t *testing.T) {
+	calls, status := 0, http.StatusInternalServerError
+	store := &memoryStore{records: make(map[string]domain.IdempotencyRecord)}
+	router := newIdempotentRouter(store, &calls, &status)
+
+	assert.Equal(t, http.StatusInternalServerError, post(router, "k", `{}`).Code)
+	assert.Empty(t, store.records)
+
+	status = http.StatusOK
+	assert.Equal(t, http.StatusOK, post(router, "k", `{}`).Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyInProgress(t *testing.T) {
+	store := &memoryStore{records: make(map[string]domain.IdempotencyRecord)}
+	hash := requestHash(http.MethodPost, "/prs", "", []byte(`{}`))
+	store.records["k"] = domain.IdempotencyRecord{Key: "k", RequestHash: hash, ExpiresAt: time.Now().Add(time.Hour)}
+
+	calls, status := 0, http.StatusOK
+	router := newIdempotentRouter(store, &calls, &status)
+	assert.Equal(t, http.StatusConflict, post(router, "k", `{}`).Code)
+	assert.Equal(t, 0, calls)
+}
+
+func TestIdempotencyQueryIsPartOfRequest(t *testing.T) {
+	calls, status := 0, http.StatusOK
+	router := newIdempotentRouter(&memoryStore{records: make(map[string]domain.IdempotencyRecord)}, &calls, &status)
+
+	assert.Equal(t, http.StatusOK, postTo(router, "/prs?dry_run=true", "k", `{}`).Code)
+	// Тот же ключ без dry_run не должен получить отчет пробного запуска
+	assert.Equal(t, http.StatusUnprocessableEntity, postTo(router, "/prs", "k", `{}`).Code)
+	assert.Equal(t, http.StatusOK, postTo(router, "/prs?dry_run=true", "k", `{}`).Code)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit - middleware с лимитами маршрутов из limiter. Ставится после Authenticate: клиент определяется
+// по организации проверенного API-ключа, а без него - по IP (произвольный токен не дает новой корзины).
+// При превышении отвечает 429 с Retry-After.
+// Если хранилище лимитов недоступно, запрос пропускается.
+func RateLimit(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.Request.Method + " " + c.FullPath()
+		res, limited, err := limiter.Allow(c.Request.Context(), route, clientKey(c))
+		if err != nil {
+			log.Printf("Rate limit store error: %v", err)
+			c.Next()
+			return
+		}
+		if !limited {
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Policy", limiter.Policy(route))
+		c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+		if !res.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// clientKey - ключ клиента для корзины
+func clientKey(c *gin.Context) string {
+	if orgID := c.GetInt(authOrgKey); orgID != 0 {
+		return "org:" + strconv.Itoa(orgID)
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
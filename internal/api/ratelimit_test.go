@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/ratelimit"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg, err := ratelimit.ParseConfig("", "POST /prs=2/m")
+	assert.NoError(t, err)
+
+	router := gin.New()
+	router.Use(Authenticate(keyAuthenticator{"rk_acme": 7}, false), RateLimit(ratelimit.NewLimiter(ratelimit.NewMemoryStore(), cfg)))
+	router.POST("/prs", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	router.GET("/prs/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodPost, "/prs", "")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2;w=60", w.Header().Get("RateLimit-Policy"))
+
+	send(http.MethodPost, "/prs", "")
+	w = send(http.MethodPost, "/prs", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+	// Организация ключа считается отдельно от своего IP
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/prs", "rk_acme").Code)
+	assert.Equal(t, http.StatusCreated, send(http.MethodPost, "/prs", "rk_acme").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(http.MethodPost, "/prs", "rk_acme").Code)
+
+	// Выдуманный токен не обходит лимит
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodPost, "/prs", "fake-1").Code)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/prs", nil)
+	c.Request.RemoteAddr = "10.0.0.1:1234"
+	c.Request.Header.Set("Authorization", "Bearer fake-2")
+	assert.Equal(t, "ip:10.0.0.1", clientKey(c))
+
+	// Маршрут без лимита
+	w = send(http.MethodGet, "/prs/1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
@@ -4,33 +4,100 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// SetupRouter настраивает все маршруты для приложения
-func SetupRouter(handler *Handler) *gin.Engine {
+// SetupRouter настраивает все маршруты для приложения; middleware применяются ко всем маршрутам API
+func SetupRouter(handler *Handler, middleware ...gin.HandlerFunc) *gin.Engine {
 	// Использование gin.ReleaseMode для продакшена, но пока оставим Default
 	router := gin.Default()
 
-	api := router.Group("/api/v1")
+	api := router.Group("/api/v1", middleware...)
 	{
 		// Teams
 		api.POST("/teams", handler.CreateTeam)
+		api.GET("/teams/:id", handler.GetTeam)
+		api.PATCH("/teams/:id", handler.UpdateTeam)                      // Переименование
+		api.POST("/teams/:id/archive", handler.ArchiveTeam)              // strategy: reassign | close
+		api.POST("/teams/:id/members/move", handler.MoveTeamMembers)     // Перевод участников в другую команду
+		api.PUT("/teams/:id/parent", handler.MoveTeam)                   // Перенос команды в другой отдел
+		api.GET("/teams/:id/subtree/members", handler.GetSubtreeMembers) // Участники команды и всех подкоманд
+		api.GET("/teams/:id/policy", handler.GetTeamPolicy)
+		api.PUT("/teams/:id/policy", handler.UpdateTeamPolicy) // Политика назначения ревьюеров
+		api.GET("/teams/:id/codeowners", handler.GetCodeOwners)
+		api.PUT("/teams/:id/codeowners", handler.UploadCodeOwners) // Файл CODEOWNERS как есть
+		api.GET("/teams/:id/templates", handler.GetNotificationTemplates)
+		api.PUT("/teams/:id/templates/:event", handler.SaveNotificationTemplate) // Шаблон письма для события
+		api.DELETE("/teams/:id/templates/:event", handler.DeleteNotificationTemplate)
+		api.PUT("/teams/:id/chat-webhook", handler.SetTeamChatWebhook) // Входящий вебхук Slack/Mattermost
+		api.DELETE("/teams/:id/chat-webhook", handler.DeleteTeamChatWebhook)
+		api.GET("/teams/:id/members", handler.GetTeamMembers)
+		api.PUT("/teams/:id/members/:userId", handler.SetTeamMembership) // Добавление в команду и флаги can_author/can_review
+		api.DELETE("/teams/:id/members/:userId", handler.RemoveTeamMember)
 
 		// Users
 		api.POST("/users", handler.CreateUser)
-		api.DELETE("/users/:id", handler.DeactivateUser) // Деактивация пользователя
+		api.DELETE("/users/:id", handler.DeactivateUser) // Деактивация пользователя (?hard=true - удаление)
+		api.PATCH("/users/:id", handler.UpdateUser)      // Имя и перевод в другую команду
+		api.POST("/users/:id/activate", handler.ActivateUser)
+		api.PUT("/users/:id/seniority", handler.SetUserSeniority)
+		api.PUT("/users/:id/capacity", handler.SetUserMaxOpenReviews)     // Лимит открытых ревью
+		api.PUT("/users/:id/expertise", handler.SetUserExpertise)         // Логин для CODEOWNERS и теги экспертизы
+		api.PUT("/users/:id/notifications", handler.SetUserNotifications) // Email и отписка от событий
+		api.PUT("/users/:id/chat", handler.SetUserChatHandle)             // Ник в чате для упоминаний
+		api.GET("/users/:id/teams", handler.GetUserTeams)
+		api.PUT("/users/:id/primary-team", handler.SetPrimaryTeam) // Команда, от имени которой создаются PR
+
+		// Массовый импорт и выгрузка команд и пользователей (CSV или JSON)
+		api.POST("/import", handler.ImportDirectory) // ?dry_run=true - только отчет
+		api.GET("/export", handler.ExportDirectory)  // ?format=csv | json
+
+		// Периоды отсутствия (отпуск и т.п.)
+		api.POST("/users/:id/unavailability", handler.AddUnavailability)
+		api.GET("/users/:id/unavailability", handler.ListUnavailability)
+		api.PUT("/users/:id/unavailability/:windowId", handler.UpdateUnavailability)
+		api.DELETE("/users/:id/unavailability/:windowId", handler.DeleteUnavailability)
 
 		// Additional Tasks
 		api.POST("/teams/:id/deactivate", handler.MassDeactivate) // Массовая деактивация
 
 		// Pull Requests
-		api.POST("/prs", handler.CreatePR)          // Создание PR с автоназначением
+		api.POST("/prs", handler.CreatePR) // Создание PR с автоназначением
+		api.GET("/prs/:id", handler.GetPR) // PR с положением в очереди ожидания ревьюера
+		api.GET("/prs/:id/history", handler.GetPRHistory)
 		api.POST("/prs/:id/merge", handler.MergePR) // Мердж PR (идемпотентный)
 
 		// Переназначение ревьювера
-		api.POST("/prs/:id/reroll", handler.RerollReviewer)
+		api.POST("/prs/:id/reroll", handler.RerollReviewer) // new_reviewer_id - явный выбор замены
+
+		// Ручное управление ревьюерами
+		api.POST("/prs/:id/reviewers", handler.AddReviewer)
+		api.DELETE("/prs/:id/reviewers/:userId", handler.RemoveReviewer)
 
 		// Получение PR для ревьювера
 		api.GET("/users/:id/prs", handler.GetPRsByReviewer)
+
+		// Статистика назначений
+		api.GET("/stats/reviewers", handler.GetStats) // ?team_id= - по поддереву команды
+
+		// Поток событий (SSE) для дашбордов вместо опроса
+		api.GET("/events/stream", handler.StreamEvents) // ?team_id=&user_id=, переподключение по Last-Event-ID
 	}
 
 	return router
 }
+
+// SetupGraphQLRoute добавляет эндпоинт GraphQL за теми же middleware, что и REST API
+// (ключ, лимиты запросов, идемпотентность): мутации GraphQL меняют те же данные
+func SetupGraphQLRoute(router *gin.Engine, serve gin.HandlerFunc, middleware ...gin.HandlerFunc) {
+	handlers := append(append([]gin.HandlerFunc(nil), middleware...), serve)
+	router.POST("/graphql", handlers...)
+}
+
+// SetupAdminRoutes добавляет маршруты администратора установки (доступ по токену, см. AdminAuth)
+func SetupAdminRoutes(router *gin.Engine, handler *Handler, token string) {
+	admin := router.Group("/api/v1/admin", AdminAuth(token))
+	{
+		admin.POST("/orgs", handler.CreateOrganization) // Новая организация и ее API-ключ
+		// Резервная копия и восстановление всей установки
+		admin.GET("/snapshot", handler.ExportSnapshot)  // ?anonymize=true - с псевдонимами
+		admin.POST("/snapshot", handler.ImportSnapshot) // Только в пустую базу
+	}
+}
@@ -8,8 +8,123 @@ var (
 	ErrTeamNotFound = errors.New("team not found")
 	ErrPRNotFound   = errors.New("pull request not found")
 
+	ErrTeamPolicyNotFound     = errors.New("team policy not found")
+	ErrUnavailabilityNotFound = errors.New("unavailability window not found")
+	ErrTemplateNotFound       = errors.New("notification template not found")
+	ErrChatWebhookNotFound    = errors.New("chat webhook not found")
+	ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+	ErrOrganizationNotFound   = errors.New("organization not found")
+	ErrMembershipNotFound     = errors.New("user is not a member of this team")
+
+	ErrTeamAlreadyExists = errors.New("team with this name already exists")
+
 	// Ошибки бизнес-логики
 	ErrPRAlreadyMerged   = errors.New("pull request already merged")
+	ErrPRClosed          = errors.New("pull request is closed")
+	ErrTeamArchived      = errors.New("team is archived")
 	ErrReviewerNotActive = errors.New("reviewer is not active")
 	ErrNoReviewersFound  = errors.New("no eligible reviewers found")
+
+	// Ошибки ручного выбора ревьюеров
+	ErrReviewerIsAuthor     = errors.New("author cannot review own pull request")
+	ErrAlreadyReviewer      = errors.New("user is already a reviewer on this pull request")
+	ErrNotReviewer          = errors.New("user is not a reviewer on this pull request")
+	ErrReviewerNotEligible  = errors.New("user is not eligible to review this pull request")
+	ErrReviewerLimitReached = errors.New("reviewer count would violate team policy")
+
+	// Ошибки участия в командах
+	ErrPrimaryTeamMembership = errors.New("cannot leave primary team, change primary team first")
+	ErrAuthorNotAllowed      = errors.New("user cannot author pull requests in this team")
+
+	// Иерархия команд
+	ErrTeamCycle = errors.New("team cannot be moved under itself or its descendant")
+
+	ErrUserDeleted = errors.New("user has been deleted")
+
+	// Снимок восстанавливается только в пустую базу
+	ErrSnapshotNotEmpty = errors.New("database is not empty")
+	// Обезличенный снимок требует секретного ключа псевдонимов
+	ErrAnonymizationKeyMissing = errors.New("anonymization key is not configured")
+
+	// Ошибки валидации
+	ErrInvalidPolicy     = errors.New("invalid team policy")
+	ErrInvalidSeniority  = errors.New("invalid seniority level")
+	ErrInvalidPeriod     = errors.New("invalid period: end must be after start")
+	ErrInvalidCapacity   = errors.New("invalid review capacity")
+	ErrInvalidCodeOwners = errors.New("invalid CODEOWNERS file")
+	ErrInvalidEmail      = errors.New("invalid email address")
+	ErrInvalidEventType  = errors.New("unknown notification event type")
+	ErrInvalidTemplate   = errors.New("invalid notification template")
+	ErrInvalidWebhookURL = errors.New("invalid webhook URL")
+	ErrInvalidOrgName    = errors.New("invalid organization name")
+	ErrInvalidUserName   = errors.New("invalid user name")
+	ErrInvalidTeamName   = errors.New("invalid team name")
+	ErrInvalidStrategy   = errors.New("invalid archive strategy")
+	ErrInvalidSnapshot   = errors.New("invalid snapshot")
 )
+
+// errorCodes - машиночитаемые коды ошибок домена (поле code в ответе REST API)
+var errorCodes = []struct {
+	code string
+	err  error
+}{
+	{"user_not_found", ErrUserNotFound},
+	{"team_not_found", ErrTeamNotFound},
+	{"pr_not_found", ErrPRNotFound},
+	{"unavailability_not_found", ErrUnavailabilityNotFound},
+	{"template_not_found", ErrTemplateNotFound},
+	{"chat_webhook_not_found", ErrChatWebhookNotFound},
+	{"organization_not_found", ErrOrganizationNotFound},
+	{"membership_not_found", ErrMembershipNotFound},
+	{"team_already_exists", ErrTeamAlreadyExists},
+	{"pr_already_merged", ErrPRAlreadyMerged},
+	{"pr_closed", ErrPRClosed},
+	{"team_archived", ErrTeamArchived},
+	{"reviewer_not_active", ErrReviewerNotActive},
+	{"no_reviewers_found", ErrNoReviewersFound},
+	{"reviewer_is_author", ErrReviewerIsAuthor},
+	{"already_reviewer", ErrAlreadyReviewer},
+	{"not_reviewer", ErrNotReviewer},
+	{"reviewer_not_eligible", ErrReviewerNotEligible},
+	{"reviewer_limit_reached", ErrReviewerLimitReached},
+	{"primary_team_membership", ErrPrimaryTeamMembership},
+	{"author_not_allowed", ErrAuthorNotAllowed},
+	{"team_cycle", ErrTeamCycle},
+	{"user_deleted", ErrUserDeleted},
+	{"snapshot_not_empty", ErrSnapshotNotEmpty},
+	{"anonymization_key_missing", ErrAnonymizationKeyMissing},
+	{"invalid_policy", ErrInvalidPolicy},
+	{"invalid_seniority", ErrInvalidSeniority},
+	{"invalid_period", ErrInvalidPeriod},
+	{"invalid_capacity", ErrInvalidCapacity},
+	{"invalid_codeowners", ErrInvalidCodeOwners},
+	{"invalid_email", ErrInvalidEmail},
+	{"invalid_event_type", ErrInvalidEventType},
+	{"invalid_template", ErrInvalidTemplate},
+	{"invalid_webhook_url", ErrInvalidWebhookURL},
+	{"invalid_org_name", ErrInvalidOrgName},
+	{"invalid_user_name", ErrInvalidUserName},
+	{"invalid_team_name", ErrInvalidTeamName},
+	{"invalid_strategy", ErrInvalidStrategy},
+	{"invalid_snapshot", ErrInvalidSnapshot},
+}
+
+// ErrorCode возвращает код ошибки домена, которую содержит err ("" - ошибка не из домена)
+func ErrorCode(err error) string {
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return ""
+}
+
+// ErrorByCode - ошибка домена по ее коду (nil - код неизвестен)
+func ErrorByCode(code string) error {
+	for _, c := range errorCodes {
+		if c.code == code {
+			return c.err
+		}
+	}
+	return nil
+}
@@ -1,43 +1,259 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"io"
+	"time"
+)
 
 // Repository описывает методы работы с базой данных
+// Все запросы ограничены организацией из контекста (см. WithOrgID): чужие записи не находятся.
 type Repository interface {
+	// Организации
+	CreateOrganization(ctx context.Context, org *Organization) error
+	GetOrganizationByAPIKeyHash(ctx context.Context, hash string) (*Organization, error)
+
 	// Team methods
 	CreateTeam(ctx context.Context, team *Team) error
 	GetTeamByName(ctx context.Context, name string) (*Team, error)
+	GetTeamByID(ctx context.Context, id int) (*Team, error)
+	GetTeamsByIDs(ctx context.Context, ids []int) ([]Team, error)
+	// Иерархия: дочерние команды (parentID == nil - команды верхнего уровня), перенос и ID поддерева (с самой командой)
+	GetChildTeams(ctx context.Context, parentID *int) ([]Team, error)
+	SetTeamParent(ctx context.Context, teamID int, parentID *int) error
+	GetSubtreeTeamIDs(ctx context.Context, teamID int) ([]int, error)
+	RenameTeam(ctx context.Context, teamID int, name string) error
+	ArchiveTeam(ctx context.Context, teamID int, at time.Time) error
+
+	// Team policy methods
+	GetTeamPolicy(ctx context.Context, teamID int) (*TeamPolicy, error)
+	SaveTeamPolicy(ctx context.Context, policy *TeamPolicy) error
+
+	// Code owners methods
+	GetCodeOwnerRules(ctx context.Context, teamID int) ([]CodeOwnerRule, error)
+	ReplaceCodeOwnerRules(ctx context.Context, teamID int, rules []CodeOwnerRule) error
 
 	// Statistic methods
 	GetReviewerStats(ctx context.Context) (map[int]int, error) // Возвращает map[UserID]Count
+	// То же только по ревьюерам - участникам команд teamIDs
+	GetReviewerStatsForTeams(ctx context.Context, teamIDs []int) (map[int]int, error)
+
+	// Notification template methods
+	GetNotificationTemplate(ctx context.Context, teamID int, eventType string) (*NotificationTemplate, error)
+	GetNotificationTemplates(ctx context.Context, teamID int) ([]NotificationTemplate, error)
+	SaveNotificationTemplate(ctx context.Context, tpl *NotificationTemplate) error
+	DeleteNotificationTemplate(ctx context.Context, teamID int, eventType string) error
+
+	// Chat webhook methods
+	GetTeamChatWebhook(ctx context.Context, teamID int) (*TeamChatWebhook, error)
+	GetTeamChatWebhooks(ctx context.Context) ([]TeamChatWebhook, error)
+	SaveTeamChatWebhook(ctx context.Context, webhook *TeamChatWebhook) error
+	DeleteTeamChatWebhook(ctx context.Context, teamID int) error
 
 	// User methods
 	CreateUser(ctx context.Context, user *User) error
 	GetUserByID(ctx context.Context, id int) (*User, error)
+	GetUsersByIDs(ctx context.Context, ids []int) ([]User, error)
 	DeactivateUser(ctx context.Context, id int) error
+	ActivateUser(ctx context.Context, id int) error
+	SetUserName(ctx context.Context, id int, name string) error
+	// Перевод в команду toTeamID: участие в основной команде заменяется участием в новой
+	TransferUser(ctx context.Context, id int, toTeamID int) error
+	// Обезличивает пользователя, снимает с команд и удаляет его периоды отсутствия
+	AnonymizeUser(ctx context.Context, id int, at time.Time) error
+	SetUserSeniority(ctx context.Context, id int, seniority string) error
+	SetUserMaxOpenReviews(ctx context.Context, id int, limit *int) error
+	SetUserExpertise(ctx context.Context, id int, login string, tags []string) error
+	GetUsersByLogins(ctx context.Context, logins []string) ([]User, error)
+	SetUserNotifications(ctx context.Context, id int, email string, optOut []string) error
+	SetUserChatHandle(ctx context.Context, id int, handle string) error
 
-	// Для алгоритма выбора случайного ревьюера нам нужно получать всех юзеров команды
+	// Активные участники команды (по TeamMembership, не только основной команды)
 	GetUsersByTeam(ctx context.Context, teamID int) ([]User, error)
+	// Активные участники команды, которые могут ревьюить ее PR (кандидаты в ревьюеры; у архивной команды их нет)
+	GetTeamReviewers(ctx context.Context, teamID int) ([]User, error)
+	// Участие в командах teamIDs, включая неактивных пользователей (с загруженным User)
+	GetMembersOfTeams(ctx context.Context, teamIDs []int) ([]TeamMembership, error)
+
+	// Team membership methods
+	GetUserMemberships(ctx context.Context, userID int) ([]TeamMembership, error)
+	SaveTeamMembership(ctx context.Context, membership *TeamMembership) error
+	DeleteTeamMembership(ctx context.Context, teamID int, userID int) error
+	// Основная команда должна быть среди команд пользователя
+	SetUserPrimaryTeam(ctx context.Context, userID int, teamID int) error
+
+	// Unavailability methods
+	CreateUnavailability(ctx context.Context, window *UserUnavailability) error
+	GetUnavailabilityByID(ctx context.Context, id int) (*UserUnavailability, error)
+	GetUnavailabilityByUser(ctx context.Context, userID int) ([]UserUnavailability, error)
+	UpdateUnavailability(ctx context.Context, window *UserUnavailability) error
+	DeleteUnavailability(ctx context.Context, id int) error
+	// Возвращает ID тех из userIDs, кто отсутствует в момент at
+	GetUnavailableUserIDs(ctx context.Context, userIDs []int, at time.Time) (map[int]bool, error)
+	// Окна, начавшиеся к моменту at, по которым еще не было переназначения
+	GetPendingUnavailabilityStarts(ctx context.Context, at time.Time) ([]UserUnavailability, error)
+	MarkUnavailabilityReassigned(ctx context.Context, id int, at time.Time) error
 
 	// PR methods
 	CreatePR(ctx context.Context, pr *PullRequest) error
 	GetPRByID(ctx context.Context, id int) (*PullRequest, error)
-	UpdatePR(ctx context.Context, pr *PullRequest) error // Для смены статуса или ревьюеров
+	// Смена статуса или ревьюеров: change применяется к PR, перечитанному под блокировкой строки,
+	// и только если он еще открыт (иначе ErrPRAlreadyMerged / ErrPRClosed). Возвращает сохраненный PR.
+	UpdateOpenPR(ctx context.Context, id int, change func(pr *PullRequest) error) (*PullRequest, error)
 	GetPRsByReviewer(ctx context.Context, reviewerID int) ([]PullRequest, error)
+	// Открытые PR авторов команды (с автором и ревьюерами)
+	GetOpenPRsByTeam(ctx context.Context, teamID int) ([]PullRequest, error)
+	// Пакетные варианты для GraphQL (с автором и ревьюерами)
+	GetOpenPRsByTeams(ctx context.Context, teamIDs []int) ([]PullRequest, error)
+	GetOpenPRsByReviewers(ctx context.Context, reviewerIDs []int) ([]PullRequest, error)
+	// Количество открытых PR на ревью у каждого из userIDs
+	GetOpenReviewCounts(ctx context.Context, userIDs []int) (map[int]int, error)
+	// Блокирует назначение пользователей userIDs ревьюерами до вызова unlock (в том числе в других репликах):
+	// подбор с проверкой лимита открытых ревью и запись назначения выполняются под этой блокировкой
+	LockReviewers(ctx context.Context, userIDs []int) (unlock func(), err error)
+
+	// Открытые назначения ревьюеров (для напоминаний и эскалации)
+	GetOpenAssignments(ctx context.Context) ([]ReviewAssignment, error)
+	MarkReviewReminded(ctx context.Context, prID int, reviewerID int, at time.Time) error
+
+	// PR history methods
+	AddPRHistory(ctx context.Context, entry *PRHistoryEntry) error
+	GetPRHistory(ctx context.Context, prID int) ([]PRHistoryEntry, error)
+
+	// Журнал событий (outbox)
+	AddEvent(ctx context.Context, event *Event) error
+	// События с ID > afterID по возрастанию ID, не больше limit
+	GetEventsAfter(ctx context.Context, afterID int64, filter EventFilter, limit int) ([]Event, error)
+	GetLatestEventID(ctx context.Context) (int64, error)
+	DeleteEventsBefore(ctx context.Context, before time.Time) (int64, error)
+
+	// Ключи идемпотентности. Create занимает ключ (false - ключ уже занят и не истек).
+	CreateIdempotencyRecord(ctx context.Context, record *IdempotencyRecord) (bool, error)
+	GetIdempotencyRecord(ctx context.Context, key string) (*IdempotencyRecord, error)
+	// Сохраняет ответ выполненного запроса
+	CompleteIdempotencyRecord(ctx context.Context, record *IdempotencyRecord) error
+	DeleteIdempotencyRecord(ctx context.Context, key string) error
+	DeleteExpiredIdempotencyRecords(ctx context.Context, now time.Time) (int64, error)
+
+	// Очередь ожидания ревьюера (по возрастанию WaitingSince)
+	GetWaitingPRs(ctx context.Context) ([]PullRequest, error)
+	// PR очереди перед pr, авторы которых из команд teamIDs (конкурирующие за тех же ревьюеров)
+	CountWaitingPRsAhead(ctx context.Context, pr *PullRequest, teamIDs []int) (int, error)
+
+	// Справочник: все команды и пользователи организации (по возрастанию ID)
+	GetTeams(ctx context.Context) ([]Team, error)
+	GetUsers(ctx context.Context) ([]User, error)
+	// Импорт справочника одной транзакцией; при dryRun или ошибке в строке изменения откатываются
+	ImportDirectory(ctx context.Context, records []DirectoryRecord, dryRun bool) ([]ImportRowResult, error)
+
+	// Снимок всех организаций (без ограничения контекстом); восстановление - одной транзакцией
+	// в пустую базу с сохранением ID
+	ExportSnapshot(ctx context.Context) (*Snapshot, error)
+	ImportSnapshot(ctx context.Context, snapshot *Snapshot) error
 }
 
 // Service описывает бизнес-логику (то, что вызывается из HTTP хендлеров)
 type Service interface {
+	// Организации: создание возвращает API-ключ (показывается один раз), по ключу определяется организация запроса
+	CreateOrganization(ctx context.Context, name string) (*Organization, string, error)
+	AuthenticateOrg(ctx context.Context, apiKey string) (*Organization, error)
+
 	// Команды и пользователи
 	CreateTeam(ctx context.Context, name string) (*Team, error)
+	// То же, но внутри родительской команды (parentID == nil - команда верхнего уровня)
+	CreateTeamWithParent(ctx context.Context, name string, parentID *int) (*Team, error)
+	// Перенос команды вместе с поддеревом; участники команды и всех ее потомков
+	MoveTeam(ctx context.Context, teamID int, parentID *int) (*Team, error)
+	GetSubtreeMembers(ctx context.Context, teamID int) ([]User, error)
+	GetTeam(ctx context.Context, teamID int) (*Team, error)
+	RenameTeam(ctx context.Context, teamID int, name string) (*Team, error)
+	// Архивация: участники команды (основной) деактивируются, их ревью переназначаются,
+	// открытые PR команды остаются (ArchiveReassign) или закрываются (ArchiveClose)
+	ArchiveTeam(ctx context.Context, teamID int, strategy string) (*Team, error)
+	// Перевод участников (всех, если userIDs не переданы) в другую команду
+	MoveTeamMembers(ctx context.Context, fromTeamID int, toTeamID int, userIDs ...int) ([]User, error)
 	CreateUser(ctx context.Context, name string, teamID int) (*User, error)
 	DeleteUser(ctx context.Context, userID int) error // Soft delete / деактивация
+	ActivateUser(ctx context.Context, userID int) (*User, error)
+	// Имя и перевод в другую команду (открытые ревью для старой команды переназначаются)
+	UpdateUser(ctx context.Context, userID int, update UserUpdate) (*User, error)
+	// Окончательное удаление: ревью переназначаются, в истории пользователь остается обезличенным
+	HardDeleteUser(ctx context.Context, userID int) error
+	SetUserSeniority(ctx context.Context, userID int, seniority string) (*User, error)
+	SetUserMaxOpenReviews(ctx context.Context, userID int, limit *int) (*User, error)
+	SetUserExpertise(ctx context.Context, userID int, login string, tags []string) (*User, error)
+	// Адрес для уведомлений и события, от которых пользователь отписался
+	SetUserNotifications(ctx context.Context, userID int, email string, optOut []string) (*User, error)
+	SetUserChatHandle(ctx context.Context, userID int, handle string) (*User, error)
+	// Деактивация пользователей команды (всех, если userIDs не переданы) с переназначением их ревью
+	MassDeactivateTeamUsers(ctx context.Context, teamID int, userIDs ...int) error
+
+	// Участие в командах: добавление или изменение флагов, выход (кроме основной команды), смена основной команды
+	GetTeamMembers(ctx context.Context, teamID int) ([]TeamMembership, error)
+	GetUserTeams(ctx context.Context, userID int) ([]TeamMembership, error)
+	SetTeamMembership(ctx context.Context, membership *TeamMembership) (*TeamMembership, error)
+	RemoveTeamMember(ctx context.Context, teamID int, userID int) error
+	SetPrimaryTeam(ctx context.Context, userID int, teamID int) (*User, error)
+
+	// Периоды отсутствия
+	AddUnavailability(ctx context.Context, window *UserUnavailability) (*UserUnavailability, error)
+	ListUnavailability(ctx context.Context, userID int) ([]UserUnavailability, error)
+	UpdateUnavailability(ctx context.Context, window *UserUnavailability) (*UserUnavailability, error)
+	DeleteUnavailability(ctx context.Context, userID int, windowID int) error
+
+	// Политика назначения ревьюеров
+	GetTeamPolicy(ctx context.Context, teamID int) (*TeamPolicy, error)
+	UpdateTeamPolicy(ctx context.Context, policy *TeamPolicy) (*TeamPolicy, error)
+
+	// Владельцы кода (CODEOWNERS)
+	GetCodeOwners(ctx context.Context, teamID int) ([]CodeOwnerRule, error)
+	UploadCodeOwners(ctx context.Context, teamID int, content io.Reader) (*CodeOwnersReport, error)
+
+	// Шаблоны уведомлений команды (без шаблона используется стандартный текст)
+	GetNotificationTemplates(ctx context.Context, teamID int) ([]NotificationTemplate, error)
+	SaveNotificationTemplate(ctx context.Context, tpl *NotificationTemplate) (*NotificationTemplate, error)
+	DeleteNotificationTemplate(ctx context.Context, teamID int, eventType string) error
+	// Вебхук чата команды для уведомлений и ежедневного дайджеста
+	SetTeamChatWebhook(ctx context.Context, teamID int, url string) (*TeamChatWebhook, error)
+	DeleteTeamChatWebhook(ctx context.Context, teamID int) error
 
 	// PR логика
 	CreatePR(ctx context.Context, title string, authorID int) (*PullRequest, error)
+	// То же, но с измененными файлами и метками для подбора владельцев кода и экспертов
+	CreatePRWithChanges(ctx context.Context, title string, authorID int, files []string, labels []string) (*PullRequest, error)
 	MergePR(ctx context.Context, prID int) (*PullRequest, error)
+	GetPR(ctx context.Context, prID int) (*PullRequest, error)
+	GetPRHistory(ctx context.Context, prID int) ([]PRHistoryEntry, error)
 
 	// Доп функционал (переназначение)
 	RerollReviewer(ctx context.Context, prID int, oldReviewerID int) (*PullRequest, error)
+	// Ручное управление ревьюерами (с теми же проверками, что и автоназначение)
+	ReplaceReviewer(ctx context.Context, prID int, oldReviewerID int, newReviewerID int) (*PullRequest, error)
+	AddReviewer(ctx context.Context, prID int, userID int) (*PullRequest, error)
+	RemoveReviewer(ctx context.Context, prID int, userID int) (*PullRequest, error)
 	GetReviewerPRs(ctx context.Context, reviewerID int) ([]PullRequest, error)
+	// Статистика: ID пользователя -> сколько PR ему назначено
+	GetReviewerStats(ctx context.Context) (map[int]int, error)
+	// То же по ревьюерам из поддерева команды
+	GetTeamReviewerStats(ctx context.Context, teamID int) (map[int]int, error)
+
+	// Поток событий: чтение журнала после курсора и сигнал о новых событиях
+	GetEventsAfter(ctx context.Context, afterID int64, filter EventFilter, limit int) ([]Event, error)
+	GetLatestEventID(ctx context.Context) (int64, error)
+	// Канал закрывается при появлении следующего события в этом процессе
+	EventsChanged() <-chan struct{}
+
+	// Массовый импорт команд и пользователей (upsert по естественному ключу) и выгрузка в том же формате
+	ImportDirectory(ctx context.Context, records []DirectoryRecord, dryRun bool) (*ImportReport, error)
+	ExportDirectory(ctx context.Context) ([]DirectoryRecord, error)
+
+	// Резервная копия и восстановление всего состояния сервиса (только для администратора установки).
+	// anonymize - персональные данные заменяются псевдонимами (для стендов из рабочих данных)
+	ExportSnapshot(ctx context.Context, anonymize bool) (*Snapshot, error)
+	ImportSnapshot(ctx context.Context, snapshot *Snapshot) error
+}
+
+// EventPublisher получает доменные события (напоминания, эскалации и т.п.)
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event)
 }
@@ -1,15 +1,64 @@
 package domain
 
+import "time"
+
 // Статусы Pull Request
 const (
 	PRStatusOpen   = "OPEN"
 	PRStatusMerged = "MERGED"
+	// Закрыт без мерджа (например, при архивации команды автора)
+	PRStatusClosed = "CLOSED"
 )
 
-// Team - команда пользователей
-type Team struct {
+// Что делать с открытыми PR команды при архивации
+const (
+	// PR остаются открытыми, ревью деактивированных участников переназначаются
+	ArchiveReassign = "reassign"
+	// PR закрываются без мерджа
+	ArchiveClose = "close"
+)
+
+// Уровни сеньорности (по возрастанию)
+const (
+	SeniorityJunior = "junior"
+	SeniorityMiddle = "middle"
+	SenioritySenior = "senior"
+)
+
+// SeniorityRank возвращает вес уровня для сравнения (0 - неизвестный уровень)
+func SeniorityRank(level string) int {
+	switch level {
+	case SeniorityJunior:
+		return 1
+	case SeniorityMiddle:
+		return 2
+	case SenioritySenior:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Organization - арендатор: отдел со своими командами, пользователями и PR.
+// Данные разных организаций не видны друг другу.
+type Organization struct {
 	ID   int    `json:"id" gorm:"primaryKey"`
 	Name string `json:"name" gorm:"unique"`
+	// SHA-256 API-ключа организации (сам ключ показывается только при создании)
+	APIKeyHash string    `json:"-" gorm:"index"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Team - команда пользователей
+type Team struct {
+	ID int `json:"id" gorm:"primaryKey"`
+	// Название уникально в пределах организации
+	OrgID int    `json:"org_id" gorm:"not null;default:1;uniqueIndex:idx_org_team_name"`
+	Name  string `json:"name" gorm:"uniqueIndex:idx_org_team_name"`
+	// Родительская команда (отдел); nil - команда верхнего уровня
+	ParentID *int `json:"parent_id" gorm:"index"`
+	// Момент архивации. Архивная команда не участвует в подборе ревьюеров, но остается в истории.
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
 }
 
 // User - участник команды
@@ -17,9 +66,46 @@ type User struct {
 	ID       int    `json:"id" gorm:"primaryKey"`
 	Name     string `json:"name"`
 	IsActive bool   `json:"is_active"`
-	// Внешний ключ для связи с командой
+	// Уровень: junior | middle | senior
+	Seniority string `json:"seniority" gorm:"not null;default:'junior'"`
+	// Максимум одновременно открытых ревью (nil - берется значение по умолчанию из политики команды)
+	MaxOpenReviews *int `json:"max_open_reviews"`
+	// Логин, которым пользователь указывается в CODEOWNERS (@login)
+	Login string `json:"login,omitempty" gorm:"index"`
+	// Области экспертизы; сопоставляются с метками PR
+	ExpertiseTags []string `json:"expertise_tags" gorm:"serializer:json"`
+	// Адрес для уведомлений (пусто - письма не отправляются)
+	Email string `json:"email,omitempty"`
+	// Типы событий, о которых пользователь не хочет получать уведомления
+	NotifyOptOut []string `json:"notify_opt_out" gorm:"serializer:json"`
+	// Ник в чате для упоминаний (Slack member ID вида U123ABC или имя пользователя Mattermost)
+	ChatHandle string `json:"chat_handle,omitempty"`
+	// Основная команда: в ней пользователь создает PR. Участие в других командах - TeamMembership.
 	TeamID int   `json:"team_id"`
 	Team   *Team `json:"team,omitempty" gorm:"foreignKey:TeamID"`
+	// Организация команды (денормализована для ограничения запросов)
+	OrgID int `json:"-" gorm:"not null;default:1;index"`
+	// Момент удаления. Удаленный пользователь обезличивается, но запись остается для истории PR
+	// (это не soft delete GORM: запись по-прежнему находится обычными запросами).
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// UserUpdate - изменяемые поля профиля (nil - не менять)
+type UserUpdate struct {
+	Name *string
+	// Перевод в другую основную команду
+	TeamID *int
+}
+
+// TeamMembership - участие пользователя в команде. Пользователь может состоять в нескольких командах,
+// одна из них основная (User.TeamID).
+type TeamMembership struct {
+	TeamID int `json:"team_id" gorm:"primaryKey;autoIncrement:false"`
+	UserID int `json:"user_id" gorm:"primaryKey;autoIncrement:false;index"`
+	// Может ли участник создавать PR от имени команды и ревьюить PR ее авторов
+	CanAuthor bool  `json:"can_author" gorm:"not null"`
+	CanReview bool  `json:"can_review" gorm:"not null"`
+	User      *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
 }
 
 // PullRequest - основная сущность задачи
@@ -30,4 +116,275 @@ type PullRequest struct {
 	AuthorID  int    `json:"author_id"`
 	Author    *User  `json:"author,omitempty" gorm:"foreignKey:AuthorID"`
 	Reviewers []User `json:"reviewers" gorm:"many2many:pr_reviewers;"`
+	// Организация автора
+	OrgID int `json:"-" gorm:"not null;default:1;index"`
+
+	// Измененные файлы и метки - по ним подбираются владельцы кода и эксперты
+	ChangedFiles []string `json:"changed_files,omitempty" gorm:"serializer:json"`
+	Labels       []string `json:"labels,omitempty" gorm:"serializer:json"`
+
+	// Момент постановки в очередь ожидания ревьюера (nil - PR не в очереди)
+	WaitingSince *time.Time `json:"waiting_since,omitempty" gorm:"index"`
+
+	// Отчет о назначении ревьюеров (заполняется при создании/переназначении, в БД не хранится)
+	Assignment *AssignmentReport `json:"assignment,omitempty" gorm:"-"`
+	// Положение в очереди ожидания ревьюера (в БД не хранится)
+	Queue *QueueInfo `json:"queue,omitempty" gorm:"-"`
+}
+
+// QueueInfo - положение PR в очереди ожидания свободного ревьюера
+type QueueInfo struct {
+	Position   int     `json:"position"` // 1 - следующий на назначение
+	AgeSeconds float64 `json:"age_seconds"`
+}
+
+// TeamPolicy - правила назначения ревьюеров для PR авторов команды
+type TeamPolicy struct {
+	TeamID       int `json:"team_id" gorm:"primaryKey;autoIncrement:false"`
+	MinReviewers int `json:"min_reviewers"`
+	MaxReviewers int `json:"max_reviewers"`
+	// Если true - ревьюеры берутся только из команды автора
+	SelfTeamOnly bool `json:"self_team_only"`
+	// Если своей команды не хватает до MinReviewers - опросить родительскую команду, затем соседние (до FallbackTeamIDs)
+	EscalateUpTree bool `json:"escalate_up_tree"`
+	// Запасные команды, опрашиваемые по порядку, если своей не хватает до MinReviewers
+	FallbackTeamIDs []int `json:"fallback_team_ids" gorm:"serializer:json"`
+	// Минимальный уровень ревьюера (пусто - без ограничений)
+	RequiredSeniority string `json:"required_seniority"`
+	// Лимит открытых ревью для участников команды без персонального лимита (0 - без лимита)
+	DefaultMaxOpenReviews int `json:"default_max_open_reviews"`
+	// Через сколько часов ожидания напомнить ревьюеру и через сколько заменить его (0 - выключено)
+	RemindAfterHours   int `json:"remind_after_hours"`
+	EscalateAfterHours int `json:"escalate_after_hours"`
+}
+
+// DefaultTeamPolicy - политика для команд, у которых она не задана (исходное поведение: до 2 ревьюеров из своей команды)
+func DefaultTeamPolicy(teamID int) *TeamPolicy {
+	return &TeamPolicy{
+		TeamID:       teamID,
+		MinReviewers: 1,
+		MaxReviewers: 2,
+		SelfTeamOnly: true,
+	}
+}
+
+// AssignmentReport - как прошло назначение ревьюеров
+type AssignmentReport struct {
+	Requested int `json:"requested"`
+	Assigned  int `json:"assigned"`
+	// Назначено меньше, чем MinReviewers политики
+	UnderAssigned bool `json:"under_assigned"`
+	// Часть ревьюеров взята из запасных команд (включая родительскую и соседние)
+	FellBack        bool  `json:"fell_back"`
+	FallbackTeamIDs []int `json:"fallback_team_ids,omitempty"`
+}
+
+// UserUnavailability - период отсутствия пользователя (отпуск, болезнь и т.п.).
+// Пока окно активно, пользователь не назначается ревьюером, но остается активным.
+type UserUnavailability struct {
+	ID       int       `json:"id" gorm:"primaryKey"`
+	UserID   int       `json:"user_id" gorm:"index"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason"`
+	// Когда фоновая задача переназначила открытые ревью пользователя (nil - еще не обработано)
+	ReassignedAt *time.Time `json:"reassigned_at,omitempty"`
+}
+
+// CodeOwnerRule - правило владения путями команды в формате CODEOWNERS.
+// Правила применяются по порядку Position, побеждает последнее совпавшее.
+type CodeOwnerRule struct {
+	ID       int      `json:"id" gorm:"primaryKey"`
+	TeamID   int      `json:"team_id" gorm:"index"`
+	Position int      `json:"position"`
+	Pattern  string   `json:"pattern"`
+	Owners   []string `json:"owners" gorm:"serializer:json"` // @login или @org/team-name
+}
+
+// CodeOwnersReport - результат загрузки CODEOWNERS
+type CodeOwnersReport struct {
+	Rules []CodeOwnerRule `json:"rules"`
+	// Владельцы из файла, не сопоставленные ни с пользователем, ни с командой
+	UnresolvedOwners []string `json:"unresolved_owners"`
+}
+
+// PRReviewer - связь PR и ревьюера (join-таблица pr_reviewers) с временем назначения
+type PRReviewer struct {
+	PullRequestID int        `gorm:"primaryKey"`
+	UserID        int        `gorm:"primaryKey"`
+	AssignedAt    time.Time  `gorm:"autoCreateTime"`
+	RemindedAt    *time.Time // когда ревьюеру последний раз напомнили о PR
+}
+
+// ReviewAssignment - назначение ревьюера на открытый PR (результат выборки, в БД не хранится)
+type ReviewAssignment struct {
+	PRID         int
+	ReviewerID   int
+	AuthorTeamID int
+	OrgID        int
+	AssignedAt   time.Time
+	RemindedAt   *time.Time
+}
+
+// События истории PR
+const (
+	HistoryCreated         = "created"
+	HistoryMerged          = "merged"
+	HistoryClosed          = "closed"
+	HistoryReviewerAdded   = "reviewer_added"
+	HistoryReviewerRemoved = "reviewer_removed"
+	HistoryReviewerChanged = "reviewer_replaced"
+	HistoryReminder        = "reminder"
+	HistoryEscalated       = "escalated"
+)
+
+// PRHistoryEntry - запись в истории PR
+type PRHistoryEntry struct {
+	ID            int       `json:"id" gorm:"primaryKey"`
+	PullRequestID int       `json:"pr_id" gorm:"index"`
+	Event         string    `json:"event"`
+	UserID        *int      `json:"user_id,omitempty"` // пользователь, которого касается событие
+	Details       string    `json:"details,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Типы доменных событий
+const (
+	// События о конкретном ревьюере (Event.UserID)
+	EventReviewerAssigned   = "reviewer.assigned"
+	EventReviewerUnassigned = "reviewer.unassigned"
+	EventReviewReminder     = "review.reminder"
+	EventReviewEscalated    = "review.escalated"
+	// Ревьюер Event.UserID заменен другим (reroll, ручная замена, эскалация)
+	EventReviewerReplaced = "reviewer.replaced"
+
+	// События о PR в целом
+	EventPRAssigned = "pr.assigned" // назначены ревьюеры (при создании, из очереди, вручную)
+	EventPRMerged   = "pr.merged"
+	EventPRClosed   = "pr.closed"
+)
+
+// NotificationEvents - события, о которых уведомляются ревьюеры
+var NotificationEvents = []string{EventReviewerAssigned, EventReviewerUnassigned, EventReviewReminder}
+
+// IsNotificationEvent - можно ли настроить уведомление (шаблон, отписку) для события
+func IsNotificationEvent(eventType string) bool {
+	for _, e := range NotificationEvents {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationTemplate - шаблон уведомления команды для типа события (синтаксис text/template)
+type NotificationTemplate struct {
+	ID        int    `json:"id" gorm:"primaryKey"`
+	TeamID    int    `json:"team_id" gorm:"uniqueIndex:idx_team_event"`
+	EventType string `json:"event_type" gorm:"uniqueIndex:idx_team_event"`
+	Subject   string `json:"subject"`
+	Body      string `json:"body"`
+}
+
+// TeamChatWebhook - входящий вебхук чата команды (Slack или Mattermost)
+type TeamChatWebhook struct {
+	TeamID int    `json:"team_id" gorm:"primaryKey;autoIncrement:false"`
+	URL    string `json:"url"`
+}
+
+// Event - доменное событие для уведомлений и интеграций.
+// События сохраняются в таблицу events; ID растет монотонно и служит курсором потока событий.
+type Event struct {
+	ID     int64     `json:"id" gorm:"primaryKey"`
+	Type   string    `json:"type"`
+	PRID   int       `json:"pr_id" gorm:"index"`
+	UserID int       `json:"user_id,omitempty" gorm:"index"` // ревьюер, которого касается событие
+	TeamID int       `json:"team_id" gorm:"index"`           // команда автора PR
+	OrgID  int       `json:"-" gorm:"not null;default:1;index"`
+	At     time.Time `json:"at" gorm:"index"`
+}
+
+// IdempotencyRecord - ответ на POST-запрос с заголовком Idempotency-Key.
+// Повтор запроса с тем же ключом до ExpiresAt получает сохраненный ответ вместо повторного выполнения.
+type IdempotencyRecord struct {
+	// Ключи разных организаций не пересекаются
+	OrgID int    `gorm:"primaryKey;autoIncrement:false"`
+	Key   string `gorm:"primaryKey"`
+	// Хэш метода, пути, query и тела запроса: тот же ключ с другим запросом - ошибка клиента
+	RequestHash string
+	// 0 - запрос еще выполняется (ключ занят на время аренды, а не на весь срок)
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time `gorm:"index"`
+}
+
+// EventFilter - отбор событий потока (нулевые поля не ограничивают)
+type EventFilter struct {
+	TeamID int
+	// События о пользователе и события о PR, где он ревьюер
+	UserID int
+}
+
+// Результат импорта строки справочника
+const (
+	ImportCreated   = "created"
+	ImportUpdated   = "updated"
+	ImportUnchanged = "unchanged"
+	ImportFailed    = "error"
+)
+
+// DirectoryRecord - строка импорта/экспорта справочника: пользователь и его основная команда.
+// Команда ищется по названию, пользователь - по логину, иначе по email, иначе по имени в команде.
+type DirectoryRecord struct {
+	Team string `json:"team"`
+	// Пусто - строка только создает команду
+	User       string `json:"user,omitempty"`
+	Login      string `json:"login,omitempty"`
+	Email      string `json:"email,omitempty"`
+	ChatHandle string `json:"chat_handle,omitempty"`
+	// nil - новый пользователь активен, у существующего признак не меняется
+	IsActive *bool `json:"is_active,omitempty"`
+}
+
+// ImportRowResult - итог импорта одной строки (Row - номер строки данных, с 1)
+type ImportRowResult struct {
+	Row         int    `json:"row"`
+	Action      string `json:"action"`
+	TeamID      int    `json:"team_id,omitempty"`
+	TeamCreated bool   `json:"team_created,omitempty"`
+	UserID      int    `json:"user_id,omitempty"`
+	// Строка деактивировала пользователя или перевела его из команды PreviousTeamID
+	Deactivated    bool   `json:"deactivated,omitempty"`
+	PreviousTeamID int    `json:"previous_team_id,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// ImportReport - отчет об импорте. Изменения применяются, только если ни в одной строке нет ошибок.
+type ImportReport struct {
+	DryRun    bool              `json:"dry_run"`
+	Applied   bool              `json:"applied"`
+	Created   int               `json:"created"`
+	Updated   int               `json:"updated"`
+	Unchanged int               `json:"unchanged"`
+	Failed    int               `json:"failed"`
+	Rows      []ImportRowResult `json:"rows"`
+}
+
+// Snapshot - полное состояние сервиса (все организации) для резервной копии и переноса между окружениями.
+// Журнал событий, ключи идемпотентности и лимиты запросов не входят: это временные данные.
+type Snapshot struct {
+	Organizations  []Organization
+	Teams          []Team
+	Users          []User
+	Memberships    []TeamMembership
+	Policies       []TeamPolicy
+	CodeOwnerRules []CodeOwnerRule
+	Templates      []NotificationTemplate
+	ChatWebhooks   []TeamChatWebhook
+	Unavailability []UserUnavailability
+	PullRequests   []PullRequest
+	Reviewers      []PRReviewer
+	History        []PRHistoryEntry
 }
@@ -0,0 +1,29 @@
+package domain
+
+import "context"
+
+// DefaultOrgID - организация запросов без API-ключа (установка с одним арендатором)
+const DefaultOrgID = 1
+
+type orgKey struct{}
+
+// WithOrgID ограничивает операции с ctx организацией orgID
+func WithOrgID(ctx context.Context, orgID int) context.Context {
+	return context.WithValue(ctx, orgKey{}, orgID)
+}
+
+// WithAllOrgs снимает ограничение по организации. Только для фоновых задач, обходящих все
+// организации: обрабатывая конкретный PR или пользователя, они переходят в его организацию.
+func WithAllOrgs(ctx context.Context) context.Context {
+	return context.WithValue(ctx, orgKey{}, 0)
+}
+
+// OrgIDFromContext возвращает организацию контекста (DefaultOrgID, если она не задана).
+// ok == false - контекст не ограничен организацией (см. WithAllOrgs).
+func OrgIDFromContext(ctx context.Context) (orgID int, ok bool) {
+	orgID, set := ctx.Value(orgKey{}).(int)
+	if !set {
+		return DefaultOrgID, true
+	}
+	return orgID, orgID != 0
+}
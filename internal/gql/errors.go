@@ -0,0 +1,52 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// Error - ошибка резолвера с кодом в extensions (аналог HTTP-статуса в REST API)
+type Error struct {
+	Message string
+	Code    string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func (e *Error) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.Code}
+}
+
+// Коды ошибок в extensions.code
+const (
+	CodeNotFound        = "NOT_FOUND"
+	CodeConflict        = "CONFLICT"
+	CodeBadRequest      = "BAD_REQUEST"
+	CodeQueryTooComplex = "QUERY_TOO_COMPLEX"
+	CodeInternal        = "INTERNAL"
+)
+
+// publicError переводит доменные ошибки в коды; текст внутренних ошибок наружу не отдается
+func publicError(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return &Error{Message: err.Error(), Code: CodeInternal}
+	case errors.Is(err, domain.ErrUserNotFound), errors.Is(err, domain.ErrTeamNotFound),
+		errors.Is(err, domain.ErrPRNotFound):
+		return &Error{Message: err.Error(), Code: CodeNotFound}
+	case errors.Is(err, domain.ErrPRAlreadyMerged), errors.Is(err, domain.ErrNoReviewersFound),
+		errors.Is(err, domain.ErrTeamAlreadyExists), errors.Is(err, domain.ErrPRClosed),
+		errors.Is(err, domain.ErrTeamArchived),
+		errors.Is(err, domain.ErrReviewerNotActive), errors.Is(err, domain.ErrReviewerIsAuthor),
+		errors.Is(err, domain.ErrAlreadyReviewer), errors.Is(err, domain.ErrNotReviewer),
+		errors.Is(err, domain.ErrReviewerNotEligible), errors.Is(err, domain.ErrReviewerLimitReached),
+		errors.Is(err, domain.ErrAuthorNotAllowed):
+		return &Error{Message: err.Error(), Code: CodeConflict}
+	default:
+		log.Printf("GraphQL resolver error: %v", err)
+		return &Error{Message: "internal server error", Code: CodeInternal}
+	}
+}
@@ -0,0 +1,250 @@
+package gql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/api"
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/Shishlyannikovvv/project-avito/internal/ratelimit"
+)
+
+// fakeRepo реализует только пакетные методы и считает их вызовы
+type fakeRepo struct {
+	domain.Repository
+
+	mu    sync.Mutex
+	calls map[string]int
+
+	teams []domain.Team
+	users []domain.User
+	prs   []domain.PullRequest
+}
+
+func (r *fakeRepo) count(method string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls[method]++
+}
+
+func (r *fakeRepo) GetTeamsByIDs(ctx context.Context, ids []int) ([]domain.Team, error) {
+	r.count("GetTeamsByIDs")
+	var out []domain.Team
+	for _, t := range r.teams {
+		for _, id := range ids {
+			if t.ID == id {
+				out = append(out, t)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) GetUsersByIDs(ctx context.Context, ids []int) ([]domain.User, error) {
+	r.count("GetUsersByIDs")
+	var out []domain.User
+	for _, u := range r.users {
+		for _, id := range ids {
+			if u.ID == id {
+				out = append(out, u)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) GetMembersOfTeams(ctx context.Context, teamIDs []int) ([]domain.TeamMembership, error) {
+	r.count("GetMembersOfTeams")
+	var out []domain.TeamMembership
+	for _, u := range r.users {
+		for _, id := range teamIDs {
+			if u.TeamID == id {
+				out = append(out, domain.TeamMembership{TeamID: id, UserID: u.ID, CanAuthor: true, CanReview: true, User: &u})
+			}
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) GetOpenPRsByReviewers(ctx context.Context, reviewerIDs []int) ([]domain.PullRequest, error) {
+	r.count("GetOpenPRsByReviewers")
+	var out []domain.PullRequest
+	for _, pr := range r.prs {
+		for _, rev := range pr.Reviewers {
+			if containsID(reviewerIDs, rev.ID) {
+				out = append(out, pr)
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+func containsID(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+type fakeService struct {
+	domain.Service
+}
+
+func (s *fakeService) MergePR(ctx context.Context, prID int) (*domain.PullRequest, error) {
+	if prID == 404 {
+		return nil, domain.ErrPRNotFound
+	}
+	return &domain.PullRequest{ID: prID, Title: "Done", Status: domain.PRStatusMerged, AuthorID: 1}, nil
+}
+
+func (s *fakeService) GetPR(ctx context.Context, prID int) (*domain.PullRequest, error) {
+	return nil, errors.New("database is down")
+}
+
+func newTestRepo() *fakeRepo {
+	repo := &fakeRepo{calls: make(map[string]int), teams: []domain.Team{{ID: 1, Name: "backend"}}}
+	for i := 1; i <= 30; i++ {
+		repo.users = append(repo.users, domain.User{ID: i, Name: "user", IsActive: true, TeamID: 1})
+	}
+	// Каждый пользователь ревьюит PR следующего
+	for i := 1; i <= 30; i++ {
+		author := repo.users[i-1]
+		reviewer := repo.users[i%30]
+		repo.prs = append(repo.prs, domain.PullRequest{
+			ID: i, Title: "PR", Status: domain.PRStatusOpen, AuthorID: author.ID,
+			Author: &author, Reviewers: []domain.User{reviewer},
+		})
+	}
+	return repo
+}
+
+type response struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func execQuery(t *testing.T, repo *fakeRepo, cfg Config, query string) response {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	h, err := NewHandler(&fakeService{}, repo, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := gin.New()
+	router.POST("/graphql", h.Serve)
+
+	body, _ := json.Marshal(map[string]any{"query": query})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestBatchLoading(t *testing.T) {
+	repo := newTestRepo()
+	resp := execQuery(t, repo, DefaultConfig(), `{
+		team(id: 1) {
+			name
+			members { id team { name } openReviews { id author { id } } }
+		}
+	}`)
+	assert.Empty(t, resp.Errors)
+
+	var data struct {
+		Members []struct {
+			ID          int
+			OpenReviews []struct{ ID int }
+		}
+	}
+	assert.NoError(t, json.Unmarshal(resp.Data["team"], &data))
+	assert.Len(t, data.Members, 30)
+	assert.Len(t, data.Members[0].OpenReviews, 1)
+
+	// Одна загрузка на уровень вложенности, независимо от числа участников
+	assert.Equal(t, map[string]int{
+		"GetTeamsByIDs":         1,
+		"GetMembersOfTeams":     1,
+		"GetOpenPRsByReviewers": 1,
+	}, repo.calls)
+}
+
+func TestLimits(t *testing.T) {
+	cfg := Config{MaxDepth: 4, MaxComplexity: 50}
+
+	resp := execQuery(t, newTestRepo(), cfg, `{ team(id: 1) { members { openReviews { author { name } } } } }`)
+	if assert.NotEmpty(t, resp.Errors) {
+		assert.Contains(t, resp.Errors[0].Message, "depth")
+	}
+
+	// members (1) + members.openReviews (10) + members.openReviews.id (100)
+	repo := newTestRepo()
+	resp = execQuery(t, repo, cfg, `{ team(id: 1) { members { openReviews { id } } } }`)
+	if assert.Len(t, resp.Errors, 1) {
+		assert.Equal(t, CodeQueryTooComplex, resp.Errors[0].Extensions["code"])
+	}
+	assert.Empty(t, repo.calls)
+
+	resp = execQuery(t, newTestRepo(), cfg, `{ team(id: 1) { name members { id } } }`)
+	assert.Empty(t, resp.Errors)
+}
+
+func TestErrors(t *testing.T) {
+	resp := execQuery(t, newTestRepo(), DefaultConfig(), `mutation { mergePullRequest(id: 404) { id } }`)
+	if assert.Len(t, resp.Errors, 1) {
+		assert.Equal(t, CodeNotFound, resp.Errors[0].Extensions["code"])
+	}
+
+	resp = execQuery(t, newTestRepo(), DefaultConfig(), `mutation { mergePullRequest(id: 7) { id status } }`)
+	assert.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"id": 7, "status": "MERGED"}`, string(resp.Data["mergePullRequest"]))
+
+	// Внутренние ошибки наружу не раскрываются
+	resp = execQuery(t, newTestRepo(), DefaultConfig(), `{ pullRequest(id: 1) { id } }`)
+	if assert.Len(t, resp.Errors, 1) {
+		assert.Equal(t, CodeInternal, resp.Errors[0].Extensions["code"])
+		assert.NotContains(t, resp.Errors[0].Message, "database")
+	}
+}
+
+func TestMutationIsRateLimited(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h, err := NewHandler(&fakeService{}, newTestRepo(), DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := ratelimit.ParseConfig("", "POST /graphql=1/m")
+	assert.NoError(t, err)
+	router := gin.New()
+	api.SetupGraphQLRoute(router, h.Serve, api.RateLimit(ratelimit.NewLimiter(ratelimit.NewMemoryStore(), cfg)))
+
+	send := func() *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]any{"query": `mutation { mergePullRequest(id: 7) { id } }`})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)))
+		return w
+	}
+	assert.Equal(t, http.StatusOK, send().Code)
+	w := send()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+}
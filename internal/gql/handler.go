@@ -0,0 +1,114 @@
+package gql
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// Во сколько раз дороже поле, выбранное у каждого элемента списка
+const listCostFactor = 10
+
+// Поля-списки схемы (по ним растет стоимость вложенных полей)
+var listFields = map[string]bool{
+	"members":          true,
+	"openPullRequests": true,
+	"openReviews":      true,
+	"reviewers":        true,
+}
+
+// Handler - HTTP-обработчик POST /graphql
+type Handler struct {
+	schema        *graphql.Schema
+	repo          domain.Repository
+	maxComplexity int
+}
+
+func NewHandler(svc domain.Service, repo domain.Repository, cfg Config) (*Handler, error) {
+	schema, err := NewSchema(svc, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{schema: schema, repo: repo, maxComplexity: cfg.MaxComplexity}, nil
+}
+
+type request struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Serve выполняет запрос. Как принято в GraphQL, ошибки резолверов возвращаются в поле errors со статусом 200.
+func (h *Handler) Serve(c *gin.Context) {
+	var req request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request format", "details": err.Error()})
+		return
+	}
+
+	ctx := withLoaders(c.Request.Context(), newLoaders(h.repo))
+	ctx = withBudget(ctx, h.maxComplexity)
+	resp := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	c.JSON(http.StatusOK, resp)
+}
+
+// --- Сложность запроса ---
+
+// budget - оставшаяся сложность запроса; корневые поля резолвятся параллельно
+type budget struct {
+	mu   sync.Mutex
+	left int
+}
+
+type budgetKey struct{}
+
+// withBudget задает бюджет сложности запроса (0 - без ограничения)
+func withBudget(ctx context.Context, limit int) context.Context {
+	if limit <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, budgetKey{}, &budget{left: limit})
+}
+
+// spend списывает стоимость поля корневого резолвера вместе со всем его поддеревом
+func spend(ctx context.Context) error {
+	b, ok := ctx.Value(budgetKey{}).(*budget)
+	if !ok {
+		return nil
+	}
+	cost := fieldCost(graphql.SelectedFieldNames(ctx))
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if cost > b.left {
+		return &Error{
+			Message: fmt.Sprintf("query is too complex: field costs %d, budget left %d", cost, b.left),
+			Code:    CodeQueryTooComplex,
+		}
+	}
+	b.left -= cost
+	return nil
+}
+
+// fieldCost - 1 за само поле плюс стоимость каждого выбранного пути (вида "members.openReviews.title")
+func fieldCost(paths []string) int {
+	cost := 1
+	for _, path := range paths {
+		segments := strings.Split(path, ".")
+		mult := 1
+		for _, seg := range segments[:len(segments)-1] {
+			if listFields[seg] {
+				mult *= listCostFactor
+			}
+		}
+		cost += mult
+	}
+	return cost
+}
@@ -0,0 +1,207 @@
+package gql
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// Сколько ждать остальные ключи пакета после первого запроса и максимальный размер пакета
+const (
+	batchWait    = 2 * time.Millisecond
+	batchMaxSize = 500
+)
+
+// batchFunc загружает значения для набора ключей одним запросом; отсутствующий ключ - нулевое значение
+type batchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// loader собирает ключи, запрошенные параллельно работающими резолверами, в один пакетный запрос
+// и кэширует результат на время GraphQL-запроса. Резолвер списка дополнительно может заранее
+// загрузить ключи всех элементов через LoadMany - так вложенные списки не дают N+1 запросов в БД.
+type loader[K comparable, V any] struct {
+	fetch batchFunc[K, V]
+
+	mu      sync.Mutex
+	cache   map[K]*loadResult[V]
+	pending *loadBatch[K, V]
+}
+
+type loadResult[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type loadBatch[K comparable, V any] struct {
+	keys    []K
+	results map[K]*loadResult[V]
+}
+
+func newLoader[K comparable, V any](fetch batchFunc[K, V]) *loader[K, V] {
+	return &loader[K, V]{fetch: fetch, cache: make(map[K]*loadResult[V])}
+}
+
+func (l *loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	values, err := l.LoadMany(ctx, []K{key})
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return values[key], nil
+}
+
+// LoadMany загружает набор ключей; уже загруженные и загружаемые берутся из кэша
+func (l *loader[K, V]) LoadMany(ctx context.Context, keys []K) (map[K]V, error) {
+	results := make(map[K]*loadResult[V], len(keys))
+	l.mu.Lock()
+	for _, key := range keys {
+		if _, ok := results[key]; ok {
+			continue
+		}
+		res, ok := l.cache[key]
+		if !ok {
+			res = &loadResult[V]{done: make(chan struct{})}
+			l.cache[key] = res
+			l.enqueue(ctx, key, res)
+		}
+		results[key] = res
+	}
+	l.mu.Unlock()
+
+	out := make(map[K]V, len(results))
+	for key, res := range results {
+		select {
+		case <-res.done:
+			if res.err != nil {
+				return nil, res.err
+			}
+			out[key] = res.value
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return out, nil
+}
+
+// enqueue добавляет ключ в текущий пакет (вызывается под l.mu)
+func (l *loader[K, V]) enqueue(ctx context.Context, key K, res *loadResult[V]) {
+	if l.pending == nil {
+		b := &loadBatch[K, V]{results: make(map[K]*loadResult[V])}
+		l.pending = b
+		time.AfterFunc(batchWait, func() { l.dispatch(ctx, b) })
+	}
+	b := l.pending
+	b.keys = append(b.keys, key)
+	b.results[key] = res
+	if len(b.keys) >= batchMaxSize {
+		l.pending = nil
+		go l.run(ctx, b)
+	}
+}
+
+// dispatch запускает пакет по таймеру, если он еще не ушел из-за переполнения
+func (l *loader[K, V]) dispatch(ctx context.Context, b *loadBatch[K, V]) {
+	l.mu.Lock()
+	if l.pending != b {
+		l.mu.Unlock()
+		return
+	}
+	l.pending = nil
+	l.mu.Unlock()
+	l.run(ctx, b)
+}
+
+func (l *loader[K, V]) run(ctx context.Context, b *loadBatch[K, V]) {
+	values, err := l.fetch(ctx, b.keys)
+	for key, res := range b.results {
+		res.value, res.err = values[key], err
+		close(res.done)
+	}
+}
+
+// loaders - набор загрузчиков одного GraphQL-запроса
+type loaders struct {
+	teams       *loader[int, *domain.Team]
+	users       *loader[int, *domain.User]
+	members     *loader[int, []domain.User]        // по ID команды
+	teamPRs     *loader[int, []domain.PullRequest] // открытые PR авторов команды
+	openReviews *loader[int, []domain.PullRequest] // открытые PR на ревью у пользователя
+}
+
+func newLoaders(repo domain.Repository) *loaders {
+	return &loaders{
+		teams: newLoader(func(ctx context.Context, ids []int) (map[int]*domain.Team, error) {
+			teams, err := repo.GetTeamsByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			out := make(map[int]*domain.Team, len(teams))
+			for i := range teams {
+				out[teams[i].ID] = &teams[i]
+			}
+			return out, nil
+		}),
+		users: newLoader(func(ctx context.Context, ids []int) (map[int]*domain.User, error) {
+			users, err := repo.GetUsersByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			out := make(map[int]*domain.User, len(users))
+			for i := range users {
+				out[users[i].ID] = &users[i]
+			}
+			return out, nil
+		}),
+		members: newLoader(func(ctx context.Context, teamIDs []int) (map[int][]domain.User, error) {
+			memberships, err := repo.GetMembersOfTeams(ctx, teamIDs)
+			if err != nil {
+				return nil, err
+			}
+			out := make(map[int][]domain.User)
+			for _, m := range memberships {
+				if m.User != nil {
+					out[m.TeamID] = append(out[m.TeamID], *m.User)
+				}
+			}
+			return out, nil
+		}),
+		teamPRs: newLoader(func(ctx context.Context, teamIDs []int) (map[int][]domain.PullRequest, error) {
+			prs, err := repo.GetOpenPRsByTeams(ctx, teamIDs)
+			if err != nil {
+				return nil, err
+			}
+			out := make(map[int][]domain.PullRequest)
+			for _, pr := range prs {
+				if pr.Author != nil {
+					out[pr.Author.TeamID] = append(out[pr.Author.TeamID], pr)
+				}
+			}
+			return out, nil
+		}),
+		openReviews: newLoader(func(ctx context.Context, userIDs []int) (map[int][]domain.PullRequest, error) {
+			prs, err := repo.GetOpenPRsByReviewers(ctx, userIDs)
+			if err != nil {
+				return nil, err
+			}
+			out := make(map[int][]domain.PullRequest)
+			for _, pr := range prs {
+				for _, r := range pr.Reviewers {
+					out[r.ID] = append(out[r.ID], pr)
+				}
+			}
+			return out, nil
+		}),
+	}
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
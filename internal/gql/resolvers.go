@@ -0,0 +1,283 @@
+package gql
+
+import (
+	"context"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// rootResolver - корневые запросы и мутации. Чтение идет через пакетные загрузчики запроса,
+// изменения - через domain.Service, как в HTTP и gRPC API.
+type rootResolver struct {
+	svc domain.Service
+}
+
+// --- Query ---
+
+func (r *rootResolver) Team(ctx context.Context, args struct{ ID int32 }) (*teamResolver, error) {
+	if err := spend(ctx); err != nil {
+		return nil, err
+	}
+	team, err := loadersFrom(ctx).teams.Load(ctx, int(args.ID))
+	if err != nil {
+		return nil, publicError(err)
+	}
+	if team == nil {
+		return nil, publicError(domain.ErrTeamNotFound)
+	}
+	return &teamResolver{team: team}, nil
+}
+
+func (r *rootResolver) User(ctx context.Context, args struct{ ID int32 }) (*userResolver, error) {
+	if err := spend(ctx); err != nil {
+		return nil, err
+	}
+	user, err := loadersFrom(ctx).users.Load(ctx, int(args.ID))
+	if err != nil {
+		return nil, publicError(err)
+	}
+	if user == nil {
+		return nil, publicError(domain.ErrUserNotFound)
+	}
+	return &userResolver{user: user}, nil
+}
+
+func (r *rootResolver) PullRequest(ctx context.Context, args struct{ ID int32 }) (*prResolver, error) {
+	if err := spend(ctx); err != nil {
+		return nil, err
+	}
+	pr, err := r.svc.GetPR(ctx, int(args.ID))
+	if err != nil {
+		return nil, publicError(err)
+	}
+	return &prResolver{pr: pr}, nil
+}
+
+// --- Mutation ---
+
+type createPullRequestInput struct {
+	Title    string
+	AuthorID int32
+	Files    *[]string
+	Labels   *[]string
+}
+
+func (r *rootResolver) CreatePullRequest(ctx context.Context, args struct{ Input createPullRequestInput }) (*prResolver, error) {
+	if err := spend(ctx); err != nil {
+		return nil, err
+	}
+	in := args.Input
+	if in.Title == "" {
+		return nil, &Error{Message: "title is required", Code: CodeBadRequest}
+	}
+	var files, labels []string
+	if in.Files != nil {
+		files = *in.Files
+	}
+	if in.Labels != nil {
+		labels = *in.Labels
+	}
+	pr, err := r.svc.CreatePRWithChanges(ctx, in.Title, int(in.AuthorID), files, labels)
+	if err != nil {
+		return nil, publicError(err)
+	}
+	return &prResolver{pr: pr}, nil
+}
+
+func (r *rootResolver) MergePullRequest(ctx context.Context, args struct{ ID int32 }) (*prResolver, error) {
+	if err := spend(ctx); err != nil {
+		return nil, err
+	}
+	pr, err := r.svc.MergePR(ctx, int(args.ID))
+	if err != nil {
+		return nil, publicError(err)
+	}
+	return &prResolver{pr: pr}, nil
+}
+
+func (r *rootResolver) RerollReviewer(ctx context.Context, args struct {
+	PrID          int32
+	OldReviewerID int32
+	NewReviewerID *int32
+}) (*prResolver, error) {
+	if err := spend(ctx); err != nil {
+		return nil, err
+	}
+
+	var pr *domain.PullRequest
+	var err error
+	if args.NewReviewerID != nil {
+		pr, err = r.svc.ReplaceReviewer(ctx, int(args.PrID), int(args.OldReviewerID), int(*args.NewReviewerID))
+	} else {
+		pr, err = r.svc.RerollReviewer(ctx, int(args.PrID), int(args.OldReviewerID))
+	}
+	if err != nil {
+		return nil, publicError(err)
+	}
+	return &prResolver{pr: pr}, nil
+}
+
+// --- Team ---
+
+type teamResolver struct {
+	team *domain.Team
+}
+
+func (t *teamResolver) ID() int32    { return int32(t.team.ID) }
+func (t *teamResolver) Name() string { return t.team.Name }
+
+func (t *teamResolver) Members(ctx context.Context, args struct{ ActiveOnly bool }) ([]*userResolver, error) {
+	members, err := loadersFrom(ctx).members.Load(ctx, t.team.ID)
+	if err != nil {
+		return nil, publicError(err)
+	}
+	out := make([]*userResolver, 0, len(members))
+	for i := range members {
+		if args.ActiveOnly && !members[i].IsActive {
+			continue
+		}
+		out = append(out, &userResolver{user: &members[i]})
+	}
+	if err := prefetchUsers(ctx, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (t *teamResolver) OpenPullRequests(ctx context.Context) ([]*prResolver, error) {
+	prs, err := loadersFrom(ctx).teamPRs.Load(ctx, t.team.ID)
+	if err != nil {
+		return nil, publicError(err)
+	}
+	return toPRResolvers(prs), nil
+}
+
+// --- User ---
+
+type userResolver struct {
+	user *domain.User
+}
+
+func (u *userResolver) ID() int32               { return int32(u.user.ID) }
+func (u *userResolver) Name() string            { return u.user.Name }
+func (u *userResolver) IsActive() bool          { return u.user.IsActive }
+func (u *userResolver) Seniority() string       { return u.user.Seniority }
+func (u *userResolver) Login() string           { return u.user.Login }
+func (u *userResolver) ExpertiseTags() []string { return nonNil(u.user.ExpertiseTags) }
+
+func (u *userResolver) MaxOpenReviews() *int32 {
+	if u.user.MaxOpenReviews == nil {
+		return nil
+	}
+	limit := int32(*u.user.MaxOpenReviews)
+	return &limit
+}
+
+func (u *userResolver) Team(ctx context.Context) (*teamResolver, error) {
+	team, err := loadersFrom(ctx).teams.Load(ctx, u.user.TeamID)
+	if err != nil {
+		return nil, publicError(err)
+	}
+	if team == nil {
+		return nil, nil
+	}
+	return &teamResolver{team: team}, nil
+}
+
+func (u *userResolver) OpenReviews(ctx context.Context) ([]*prResolver, error) {
+	prs, err := loadersFrom(ctx).openReviews.Load(ctx, u.user.ID)
+	if err != nil {
+		return nil, publicError(err)
+	}
+	return toPRResolvers(prs), nil
+}
+
+// --- PullRequest ---
+
+type prResolver struct {
+	pr *domain.PullRequest
+}
+
+func (p *prResolver) ID() int32              { return int32(p.pr.ID) }
+func (p *prResolver) Title() string          { return p.pr.Title }
+func (p *prResolver) Status() string         { return p.pr.Status }
+func (p *prResolver) ChangedFiles() []string { return nonNil(p.pr.ChangedFiles) }
+func (p *prResolver) Labels() []string       { return nonNil(p.pr.Labels) }
+
+func (p *prResolver) WaitingSince() *graphql.Time {
+	if p.pr.WaitingSince == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *p.pr.WaitingSince}
+}
+
+// Author обычно уже подгружен вместе с PR; иначе берется через загрузчик
+func (p *prResolver) Author(ctx context.Context) (*userResolver, error) {
+	if p.pr.Author != nil {
+		return &userResolver{user: p.pr.Author}, nil
+	}
+	author, err := loadersFrom(ctx).users.Load(ctx, p.pr.AuthorID)
+	if err != nil {
+		return nil, publicError(err)
+	}
+	if author == nil {
+		return nil, nil
+	}
+	return &userResolver{user: author}, nil
+}
+
+func (p *prResolver) Reviewers(ctx context.Context) ([]*userResolver, error) {
+	out := make([]*userResolver, 0, len(p.pr.Reviewers))
+	for i := range p.pr.Reviewers {
+		out = append(out, &userResolver{user: &p.pr.Reviewers[i]})
+	}
+	if err := prefetchUsers(ctx, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// prefetchUsers заранее одним пакетом загружает то, что запрошено у элементов списка пользователей.
+// Иначе элементы резолвятся с ограниченным параллелизмом и пакеты загрузчиков дробятся.
+func prefetchUsers(ctx context.Context, users []*userResolver) error {
+	if len(users) == 0 {
+		return nil
+	}
+	l := loadersFrom(ctx)
+	if graphql.HasSelectedField(ctx, "openReviews") {
+		ids := make([]int, len(users))
+		for i, u := range users {
+			ids[i] = u.user.ID
+		}
+		if _, err := l.openReviews.LoadMany(ctx, ids); err != nil {
+			return publicError(err)
+		}
+	}
+	if graphql.HasSelectedField(ctx, "team") {
+		ids := make([]int, len(users))
+		for i, u := range users {
+			ids[i] = u.user.TeamID
+		}
+		if _, err := l.teams.LoadMany(ctx, ids); err != nil {
+			return publicError(err)
+		}
+	}
+	return nil
+}
+
+func toPRResolvers(prs []domain.PullRequest) []*prResolver {
+	out := make([]*prResolver, len(prs))
+	for i := range prs {
+		out[i] = &prResolver{pr: &prs[i]}
+	}
+	return out
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
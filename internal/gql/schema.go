@@ -0,0 +1,95 @@
+package gql
+
+import (
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+const schemaSDL = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+scalar Time
+
+enum PRStatus {
+	OPEN
+	MERGED
+}
+
+type Team {
+	id: Int!
+	name: String!
+	members(activeOnly: Boolean = false): [User!]!
+	# Открытые PR авторов команды
+	openPullRequests: [PullRequest!]!
+}
+
+type User {
+	id: Int!
+	name: String!
+	isActive: Boolean!
+	seniority: String!
+	login: String!
+	expertiseTags: [String!]!
+	maxOpenReviews: Int
+	team: Team
+	# Открытые PR, где пользователь - ревьюер
+	openReviews: [PullRequest!]!
+}
+
+type PullRequest {
+	id: Int!
+	title: String!
+	status: PRStatus!
+	author: User
+	reviewers: [User!]!
+	changedFiles: [String!]!
+	labels: [String!]!
+	waitingSince: Time
+}
+
+type Query {
+	team(id: Int!): Team
+	user(id: Int!): User
+	pullRequest(id: Int!): PullRequest
+}
+
+input CreatePullRequestInput {
+	title: String!
+	authorId: Int!
+	files: [String!]
+	labels: [String!]
+}
+
+type Mutation {
+	createPullRequest(input: CreatePullRequestInput!): PullRequest!
+	mergePullRequest(id: Int!): PullRequest!
+	# Без newReviewerId замена выбирается автоматически
+	rerollReviewer(prId: Int!, oldReviewerId: Int!, newReviewerId: Int): PullRequest!
+}
+`
+
+// Config - ограничения на запросы
+type Config struct {
+	// Максимальная вложенность полей
+	MaxDepth int
+	// Бюджет сложности запроса: поле стоит 1, поле внутри списка - в listCostFactor раз дороже
+	MaxComplexity int
+	// Максимальная длина текста запроса в байтах
+	MaxQueryLength int
+}
+
+func DefaultConfig() Config {
+	return Config{MaxDepth: 7, MaxComplexity: 5000, MaxQueryLength: 16 << 10}
+}
+
+// NewSchema разбирает схему и связывает ее с резолверами
+func NewSchema(svc domain.Service, cfg Config) (*graphql.Schema, error) {
+	return graphql.ParseSchema(schemaSDL, &rootResolver{svc: svc},
+		graphql.MaxDepth(cfg.MaxDepth),
+		graphql.MaxQueryLength(cfg.MaxQueryLength),
+	)
+}
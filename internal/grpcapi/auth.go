@@ -0,0 +1,48 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// OrgAuthenticator определяет организацию по API-ключу (реализуется service.Manager)
+type OrgAuthenticator interface {
+	AuthenticateOrg(ctx context.Context, apiKey string) (*domain.Organization, error)
+}
+
+// AuthInterceptor ограничивает вызов организацией API-ключа из метаданных authorization: Bearer <ключ>.
+// Правила те же, что у api.Authenticate: без ключа - организация по умолчанию (или Unauthenticated при required).
+func AuthInterceptor(auth OrgAuthenticator, required bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var apiKey string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("authorization"); len(values) > 0 {
+				apiKey, _ = strings.CutPrefix(values[0], "Bearer ")
+			}
+		}
+		if apiKey == "" {
+			if required {
+				return nil, status.Error(codes.Unauthenticated, "API key required")
+			}
+			return handler(ctx, req)
+		}
+
+		org, err := auth.AuthenticateOrg(ctx, apiKey)
+		if errors.Is(err, domain.ErrOrganizationNotFound) {
+			return nil, status.Error(codes.Unauthenticated, "invalid API key")
+		}
+		if err != nil {
+			log.Printf("Authentication error: %v", err)
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+		return handler(domain.WithOrgID(ctx, org.ID), req)
+	}
+}
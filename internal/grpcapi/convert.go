@@ -0,0 +1,141 @@
+package grpcapi
+
+import (
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	pb "github.com/Shishlyannikovvv/project-avito/pkg/reviewerpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// --- Преобразование domain <-> protobuf ---
+
+func toTeam(t *domain.Team) *pb.Team {
+	return &pb.Team{Id: int32(t.ID), Name: t.Name}
+}
+
+func toUser(u *domain.User) *pb.User {
+	if u == nil {
+		return nil
+	}
+	user := &pb.User{
+		Id:            int32(u.ID),
+		Name:          u.Name,
+		IsActive:      u.IsActive,
+		TeamId:        int32(u.TeamID),
+		Seniority:     u.Seniority,
+		Login:         u.Login,
+		ExpertiseTags: u.ExpertiseTags,
+	}
+	if u.MaxOpenReviews != nil {
+		limit := int32(*u.MaxOpenReviews)
+		user.MaxOpenReviews = &limit
+	}
+	return user
+}
+
+func toPRStatus(s string) pb.PRStatus {
+	switch s {
+	case domain.PRStatusOpen:
+		return pb.PRStatus_PR_STATUS_OPEN
+	case domain.PRStatusMerged:
+		return pb.PRStatus_PR_STATUS_MERGED
+	default:
+		return pb.PRStatus_PR_STATUS_UNSPECIFIED
+	}
+}
+
+func toPullRequest(pr *domain.PullRequest) *pb.PullRequest {
+	out := &pb.PullRequest{
+		Id:           int32(pr.ID),
+		Title:        pr.Title,
+		Status:       toPRStatus(pr.Status),
+		AuthorId:     int32(pr.AuthorID),
+		Author:       toUser(pr.Author),
+		Reviewers:    make([]*pb.User, 0, len(pr.Reviewers)),
+		ChangedFiles: pr.ChangedFiles,
+		Labels:       pr.Labels,
+	}
+	for i := range pr.Reviewers {
+		out.Reviewers = append(out.Reviewers, toUser(&pr.Reviewers[i]))
+	}
+	if pr.WaitingSince != nil {
+		out.WaitingSince = timestamppb.New(*pr.WaitingSince)
+	}
+	if a := pr.Assignment; a != nil {
+		out.Assignment = &pb.AssignmentReport{
+			Requested:       int32(a.Requested),
+			Assigned:        int32(a.Assigned),
+			UnderAssigned:   a.UnderAssigned,
+			FellBack:        a.FellBack,
+			FallbackTeamIds: toInt32s(a.FallbackTeamIDs),
+		}
+	}
+	if q := pr.Queue; q != nil {
+		out.Queue = &pb.QueueInfo{Position: int32(q.Position), AgeSeconds: q.AgeSeconds}
+	}
+	return out
+}
+
+func toTeamPolicy(p *domain.TeamPolicy) *pb.TeamPolicy {
+	return &pb.TeamPolicy{
+		TeamId:                int32(p.TeamID),
+		MinReviewers:          int32(p.MinReviewers),
+		MaxReviewers:          int32(p.MaxReviewers),
+		SelfTeamOnly:          p.SelfTeamOnly,
+		FallbackTeamIds:       toInt32s(p.FallbackTeamIDs),
+		RequiredSeniority:     p.RequiredSeniority,
+		DefaultMaxOpenReviews: int32(p.DefaultMaxOpenReviews),
+		RemindAfterHours:      int32(p.RemindAfterHours),
+		EscalateAfterHours:    int32(p.EscalateAfterHours),
+	}
+}
+
+func fromTeamPolicy(p *pb.TeamPolicy) *domain.TeamPolicy {
+	return &domain.TeamPolicy{
+		TeamID:                int(p.GetTeamId()),
+		MinReviewers:          int(p.GetMinReviewers()),
+		MaxReviewers:          int(p.GetMaxReviewers()),
+		SelfTeamOnly:          p.GetSelfTeamOnly(),
+		FallbackTeamIDs:       toInts(p.GetFallbackTeamIds()),
+		RequiredSeniority:     p.GetRequiredSeniority(),
+		DefaultMaxOpenReviews: int(p.GetDefaultMaxOpenReviews()),
+		RemindAfterHours:      int(p.GetRemindAfterHours()),
+		EscalateAfterHours:    int(p.GetEscalateAfterHours()),
+	}
+}
+
+func toHistoryEntry(h *domain.PRHistoryEntry) *pb.HistoryEntry {
+	entry := &pb.HistoryEntry{
+		Id:        int32(h.ID),
+		PrId:      int32(h.PullRequestID),
+		Event:     h.Event,
+		Details:   h.Details,
+		CreatedAt: timestamppb.New(h.CreatedAt),
+	}
+	if h.UserID != nil {
+		userID := int32(*h.UserID)
+		entry.UserId = &userID
+	}
+	return entry
+}
+
+func toInt32s(values []int) []int32 {
+	if values == nil {
+		return nil
+	}
+	out := make([]int32, len(values))
+	for i, v := range values {
+		out[i] = int32(v)
+	}
+	return out
+}
+
+func toInts(values []int32) []int {
+	if values == nil {
+		return nil
+	}
+	out := make([]int, len(values))
+	for i, v := range values {
+		out[i] = int(v)
+	}
+	return out
+}
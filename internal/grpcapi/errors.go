@@ -0,0 +1,65 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorInterceptor переводит доменные ошибки в gRPC-статусы (аналог handleServiceError в HTTP API)
+func errorInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return resp, nil
+}
+
+func toStatus(err error) error {
+	// Уже статус (например, ошибка валидации запроса)
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	log.Printf("Service error: %v", err)
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, domain.ErrUserNotFound), errors.Is(err, domain.ErrTeamNotFound),
+		errors.Is(err, domain.ErrPRNotFound), errors.Is(err, domain.ErrUnavailabilityNotFound),
+		errors.Is(err, domain.ErrTemplateNotFound), errors.Is(err, domain.ErrChatWebhookNotFound),
+		errors.Is(err, domain.ErrOrganizationNotFound), errors.Is(err, domain.ErrMembershipNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrTeamAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, domain.ErrPRAlreadyMerged), errors.Is(err, domain.ErrNoReviewersFound),
+		errors.Is(err, domain.ErrPRClosed), errors.Is(err, domain.ErrTeamArchived),
+		errors.Is(err, domain.ErrReviewerNotActive), errors.Is(err, domain.ErrReviewerIsAuthor),
+		errors.Is(err, domain.ErrAlreadyReviewer), errors.Is(err, domain.ErrNotReviewer),
+		errors.Is(err, domain.ErrReviewerNotEligible), errors.Is(err, domain.ErrReviewerLimitReached),
+		errors.Is(err, domain.ErrPrimaryTeamMembership), errors.Is(err, domain.ErrAuthorNotAllowed),
+		errors.Is(err, domain.ErrTeamCycle), errors.Is(err, domain.ErrUserDeleted):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrInvalidPolicy), errors.Is(err, domain.ErrInvalidSeniority),
+		errors.Is(err, domain.ErrInvalidPeriod), errors.Is(err, domain.ErrInvalidCapacity),
+		errors.Is(err, domain.ErrInvalidCodeOwners), errors.Is(err, domain.ErrInvalidEmail),
+		errors.Is(err, domain.ErrInvalidEventType), errors.Is(err, domain.ErrInvalidTemplate),
+		errors.Is(err, domain.ErrInvalidWebhookURL), errors.Is(err, domain.ErrInvalidOrgName),
+		errors.Is(err, domain.ErrInvalidUserName), errors.Is(err, domain.ErrInvalidTeamName),
+		errors.Is(err, domain.ErrInvalidStrategy):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
+
+func invalidArgument(msg string) error {
+	return status.Error(codes.InvalidArgument, msg)
+}
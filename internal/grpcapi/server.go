@@ -0,0 +1,189 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	pb "github.com/Shishlyannikovvv/project-avito/pkg/reviewerpb"
+	"google.golang.org/grpc"
+)
+
+// Server - gRPC-обертка над domain.Service, аналог api.Handler для HTTP
+type Server struct {
+	pb.UnimplementedReviewerServiceServer
+	service domain.Service
+}
+
+func NewServer(s domain.Service) *Server {
+	return &Server{service: s}
+}
+
+// NewGRPCServer создает grpc.Server с зарегистрированным сервисом и переводом доменных ошибок в статусы
+func NewGRPCServer(s domain.Service, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.ChainUnaryInterceptor(errorInterceptor))
+	srv := grpc.NewServer(opts...)
+	pb.RegisterReviewerServiceServer(srv, NewServer(s))
+	return srv
+}
+
+// --- Teams ---
+
+func (s *Server) CreateTeam(ctx context.Context, req *pb.CreateTeamRequest) (*pb.Team, error) {
+	if req.GetName() == "" {
+		return nil, invalidArgument("name is required")
+	}
+	team, err := s.service.CreateTeam(ctx, req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	return toTeam(team), nil
+}
+
+func (s *Server) GetTeamPolicy(ctx context.Context, req *pb.GetTeamPolicyRequest) (*pb.TeamPolicy, error) {
+	policy, err := s.service.GetTeamPolicy(ctx, int(req.GetTeamId()))
+	if err != nil {
+		return nil, err
+	}
+	return toTeamPolicy(policy), nil
+}
+
+func (s *Server) UpdateTeamPolicy(ctx context.Context, req *pb.UpdateTeamPolicyRequest) (*pb.TeamPolicy, error) {
+	if req.GetPolicy() == nil {
+		return nil, invalidArgument("policy is required")
+	}
+	policy, err := s.service.UpdateTeamPolicy(ctx, fromTeamPolicy(req.GetPolicy()))
+	if err != nil {
+		return nil, err
+	}
+	return toTeamPolicy(policy), nil
+}
+
+// --- Users ---
+
+func (s *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.User, error) {
+	if req.GetName() == "" || req.GetTeamId() == 0 {
+		return nil, invalidArgument("name and team_id are required")
+	}
+	user, err := s.service.CreateUser(ctx, req.GetName(), int(req.GetTeamId()))
+	if err != nil {
+		return nil, err
+	}
+	return toUser(user), nil
+}
+
+func (s *Server) DeactivateUser(ctx context.Context, req *pb.DeactivateUserRequest) (*pb.DeactivateUserResponse, error) {
+	if err := s.service.DeleteUser(ctx, int(req.GetUserId())); err != nil {
+		return nil, err
+	}
+	return &pb.DeactivateUserResponse{}, nil
+}
+
+func (s *Server) MassDeactivateTeamUsers(ctx context.Context, req *pb.MassDeactivateTeamUsersRequest) (*pb.MassDeactivateTeamUsersResponse, error) {
+	if err := s.service.MassDeactivateTeamUsers(ctx, int(req.GetTeamId()), toInts(req.GetUserIds())...); err != nil {
+		return nil, err
+	}
+	return &pb.MassDeactivateTeamUsersResponse{}, nil
+}
+
+// --- Pull Requests ---
+
+func (s *Server) CreatePR(ctx context.Context, req *pb.CreatePRRequest) (*pb.PullRequest, error) {
+	if req.GetTitle() == "" || req.GetAuthorId() == 0 {
+		return nil, invalidArgument("title and author_id are required")
+	}
+	pr, err := s.service.CreatePRWithChanges(ctx, req.GetTitle(), int(req.GetAuthorId()), req.GetFiles(), req.GetLabels())
+	if err != nil {
+		return nil, err
+	}
+	return toPullRequest(pr), nil
+}
+
+func (s *Server) GetPR(ctx context.Context, req *pb.GetPRRequest) (*pb.PullRequest, error) {
+	pr, err := s.service.GetPR(ctx, int(req.GetPrId()))
+	if err != nil {
+		return nil, err
+	}
+	return toPullRequest(pr), nil
+}
+
+func (s *Server) MergePR(ctx context.Context, req *pb.MergePRRequest) (*pb.PullRequest, error) {
+	pr, err := s.service.MergePR(ctx, int(req.GetPrId()))
+	if err != nil {
+		return nil, err
+	}
+	return toPullRequest(pr), nil
+}
+
+func (s *Server) GetPRHistory(ctx context.Context, req *pb.GetPRHistoryRequest) (*pb.GetPRHistoryResponse, error) {
+	history, err := s.service.GetPRHistory(ctx, int(req.GetPrId()))
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.GetPRHistoryResponse{Entries: make([]*pb.HistoryEntry, 0, len(history))}
+	for i := range history {
+		resp.Entries = append(resp.Entries, toHistoryEntry(&history[i]))
+	}
+	return resp, nil
+}
+
+func (s *Server) ListReviewerPRs(ctx context.Context, req *pb.ListReviewerPRsRequest) (*pb.ListReviewerPRsResponse, error) {
+	prs, err := s.service.GetReviewerPRs(ctx, int(req.GetReviewerId()))
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.ListReviewerPRsResponse{PullRequests: make([]*pb.PullRequest, 0, len(prs))}
+	for i := range prs {
+		resp.PullRequests = append(resp.PullRequests, toPullRequest(&prs[i]))
+	}
+	return resp, nil
+}
+
+// --- Reviewers ---
+
+func (s *Server) RerollReviewer(ctx context.Context, req *pb.RerollReviewerRequest) (*pb.PullRequest, error) {
+	if req.GetOldReviewerId() == 0 {
+		return nil, invalidArgument("old_reviewer_id is required")
+	}
+
+	var pr *domain.PullRequest
+	var err error
+	if req.GetNewReviewerId() != 0 {
+		pr, err = s.service.ReplaceReviewer(ctx, int(req.GetPrId()), int(req.GetOldReviewerId()), int(req.GetNewReviewerId()))
+	} else {
+		pr, err = s.service.RerollReviewer(ctx, int(req.GetPrId()), int(req.GetOldReviewerId()))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toPullRequest(pr), nil
+}
+
+func (s *Server) AddReviewer(ctx context.Context, req *pb.AddReviewerRequest) (*pb.PullRequest, error) {
+	pr, err := s.service.AddReviewer(ctx, int(req.GetPrId()), int(req.GetUserId()))
+	if err != nil {
+		return nil, err
+	}
+	return toPullRequest(pr), nil
+}
+
+func (s *Server) RemoveReviewer(ctx context.Context, req *pb.RemoveReviewerRequest) (*pb.PullRequest, error) {
+	pr, err := s.service.RemoveReviewer(ctx, int(req.GetPrId()), int(req.GetUserId()))
+	if err != nil {
+		return nil, err
+	}
+	return toPullRequest(pr), nil
+}
+
+// --- Stats ---
+
+func (s *Server) GetReviewerStats(ctx context.Context, req *pb.GetReviewerStatsRequest) (*pb.GetReviewerStatsResponse, error) {
+	stats, err := s.service.GetReviewerStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.GetReviewerStatsResponse{ReviewerAssignmentsCount: make(map[int32]int32, len(stats))}
+	for userID, count := range stats {
+		resp.ReviewerAssignmentsCount[int32(userID)] = int32(count)
+	}
+	return resp, nil
+}
@@ -0,0 +1,137 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	pb "github.com/Shishlyannikovvv/project-avito/pkg/reviewerpb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeService реализует только нужные тестам методы; вызов остальных - паника встроенного nil-интерфейса
+type fakeService struct {
+	domain.Service
+	prs map[int]*domain.PullRequest
+}
+
+func (s *fakeService) CreatePRWithChanges(ctx context.Context, title string, authorID int, files []string, labels []string) (*domain.PullRequest, error) {
+	if authorID == 404 {
+		return nil, domain.ErrUserNotFound
+	}
+	limit := 3
+	pr := &domain.PullRequest{
+		ID:       len(s.prs) + 1,
+		Title:    title,
+		Status:   domain.PRStatusOpen,
+		AuthorID: authorID,
+		Reviewers: []domain.User{
+			{ID: 2, Name: "Bob", IsActive: true, TeamID: 1, MaxOpenReviews: &limit},
+		},
+		Labels:     labels,
+		Assignment: &domain.AssignmentReport{Requested: 2, Assigned: 1, UnderAssigned: true},
+	}
+	s.prs[pr.ID] = pr
+	return pr, nil
+}
+
+func (s *fakeService) MergePR(ctx context.Context, prID int) (*domain.PullRequest, error) {
+	pr, ok := s.prs[prID]
+	if !ok {
+		return nil, domain.ErrPRNotFound
+	}
+	pr.Status = domain.PRStatusMerged
+	return pr, nil
+}
+
+func (s *fakeService) RerollReviewer(ctx context.Context, prID int, oldReviewerID int) (*domain.PullRequest, error) {
+	if s.prs[prID].Status == domain.PRStatusMerged {
+		return nil, domain.ErrPRAlreadyMerged
+	}
+	return nil, domain.ErrNoReviewersFound
+}
+
+func (s *fakeService) UpdateTeamPolicy(ctx context.Context, policy *domain.TeamPolicy) (*domain.TeamPolicy, error) {
+	return nil, fmt.Errorf("%w: min > max", domain.ErrInvalidPolicy)
+}
+
+func (s *fakeService) GetReviewerStats(ctx context.Context) (map[int]int, error) {
+	return map[int]int{2: 5, 3: 1}, nil
+}
+
+func (s *fakeService) GetPR(ctx context.Context, prID int) (*domain.PullRequest, error) {
+	return nil, fmt.Errorf("database is down")
+}
+
+func newTestClient(t *testing.T) pb.ReviewerServiceClient {
+	lis := bufconn.Listen(1 << 20)
+	srv := NewGRPCServer(&fakeService{prs: make(map[int]*domain.PullRequest)})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewReviewerServiceClient(conn)
+}
+
+func TestPRLifecycle(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	pr, err := client.CreatePR(ctx, &pb.CreatePRRequest{Title: "Add gRPC", AuthorId: 1, Labels: []string{"api"}})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.PRStatus_PR_STATUS_OPEN, pr.GetStatus())
+	assert.Equal(t, []string{"api"}, pr.GetLabels())
+	if assert.Len(t, pr.GetReviewers(), 1) {
+		assert.Equal(t, "Bob", pr.GetReviewers()[0].GetName())
+		assert.Equal(t, int32(3), pr.GetReviewers()[0].GetMaxOpenReviews())
+	}
+	assert.True(t, pr.GetAssignment().GetUnderAssigned())
+
+	merged, err := client.MergePR(ctx, &pb.MergePRRequest{PrId: pr.GetId()})
+	assert.NoError(t, err)
+	assert.Equal(t, pb.PRStatus_PR_STATUS_MERGED, merged.GetStatus())
+
+	stats, err := client.GetReviewerStats(ctx, &pb.GetReviewerStatsRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int32]int32{2: 5, 3: 1}, stats.GetReviewerAssignmentsCount())
+}
+
+func TestErrorMapping(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	pr, err := client.CreatePR(ctx, &pb.CreatePRRequest{Title: "Open", AuthorId: 1})
+	assert.NoError(t, err)
+
+	_, err = client.CreatePR(ctx, &pb.CreatePRRequest{Title: "Ghost", AuthorId: 404})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.CreatePR(ctx, &pb.CreatePRRequest{AuthorId: 1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.RerollReviewer(ctx, &pb.RerollReviewerRequest{PrId: pr.GetId(), OldReviewerId: 2})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Equal(t, domain.ErrNoReviewersFound.Error(), status.Convert(err).Message())
+
+	_, err = client.UpdateTeamPolicy(ctx, &pb.UpdateTeamPolicyRequest{Policy: &pb.TeamPolicy{TeamId: 1}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Внутренние ошибки наружу не раскрываются
+	_, err = client.GetPR(ctx, &pb.GetPRRequest{PrId: pr.GetId()})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.NotContains(t, status.Convert(err).Message(), "database")
+}
@@ -0,0 +1,256 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// Сколько PR показывать в дайджесте: у текстового блока Slack ограничение в 3000 символов
+const digestMaxPRs = 20
+
+// Slack member ID (U0123ABC); такие ники упоминаются как <@ID>, остальные - как @ник (Mattermost)
+var slackMemberID = regexp.MustCompile(`^[UW][A-Z0-9]{6,}$`)
+
+// ChatStore - данные для уведомлений в чат и дайджеста
+type ChatStore interface {
+	GetTeamByID(ctx context.Context, id int) (*domain.Team, error)
+	GetTeamChatWebhook(ctx context.Context, teamID int) (*domain.TeamChatWebhook, error)
+	GetTeamChatWebhooks(ctx context.Context) ([]domain.TeamChatWebhook, error)
+	GetOpenPRsByTeam(ctx context.Context, teamID int) ([]domain.PullRequest, error)
+	GetUsersByTeam(ctx context.Context, teamID int) ([]domain.User, error)
+	GetOpenReviewCounts(ctx context.Context, userIDs []int) (map[int]int, error)
+}
+
+// ChatNotifier пишет во входящий вебхук команды о назначении ревьюеров, их замене и мердже PR.
+// Сообщения в формате Slack Block Kit, который принимает и Mattermost.
+type ChatNotifier struct {
+	store  ChatStore
+	client *http.Client
+}
+
+func NewChatNotifier(store ChatStore, client *http.Client) *ChatNotifier {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &ChatNotifier{store: store, client: client}
+}
+
+// chatPayload - тело запроса во входящий вебхук
+type chatPayload struct {
+	// Текст для уведомлений и клиентов без поддержки блоков
+	Text   string      `json:"text"`
+	Blocks []chatBlock `json:"blocks"`
+}
+
+type chatBlock struct {
+	Type     string     `json:"type"`
+	Text     *chatText  `json:"text,omitempty"`
+	Fields   []chatText `json:"fields,omitempty"`
+	Elements []chatText `json:"elements,omitempty"`
+}
+
+type chatText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func markdown(text string) chatText {
+	return chatText{Type: "mrkdwn", Text: text}
+}
+
+func (n *ChatNotifier) Notify(ctx context.Context, msg Message) error {
+	var headline string
+	switch msg.Event.Type {
+	case domain.EventPRAssigned:
+		headline = "Reviewers assigned"
+	case domain.EventReviewerReplaced:
+		headline = "Reviewer replaced"
+	case domain.EventPRMerged:
+		headline = "Merged"
+	case domain.EventPRClosed:
+		headline = "Closed"
+	default:
+		return nil
+	}
+
+	webhook, err := n.store.GetTeamChatWebhook(ctx, msg.Event.TeamID)
+	if errors.Is(err, domain.ErrChatWebhookNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	title := fmt.Sprintf("PR #%d %s", msg.PR.ID, escape(msg.PR.Title))
+	fields := []chatText{
+		markdown("*Author*\n" + escape(msg.AuthorName())),
+		markdown("*Reviewers*\n" + mentionList(msg.PR.Reviewers)),
+	}
+	if msg.Event.Type == domain.EventReviewerReplaced && msg.Recipient != nil {
+		fields = append(fields, markdown("*Replaced*\n"+escape(msg.Recipient.Name)))
+	}
+
+	payload := chatPayload{
+		Text: fmt.Sprintf("%s: %s", headline, title),
+		Blocks: []chatBlock{
+			{Type: "section", Text: &chatText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*: %s", headline, title)}},
+			{Type: "section", Fields: fields},
+		},
+	}
+	return n.post(ctx, webhook.URL, payload)
+}
+
+// SendDigest отправляет в чат каждой команды с вебхуком сводку открытых PR и нагрузки ревьюеров.
+// Подходит как задача планировщика.
+func (n *ChatNotifier) SendDigest(ctx context.Context) error {
+	webhooks, err := n.store.GetTeamChatWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, w := range webhooks {
+		payload, err := n.digest(ctx, w.TeamID)
+		if err != nil {
+			return err
+		}
+		// Недоступный чат одной команды не мешает остальным
+		if err := n.post(ctx, w.URL, payload); err != nil {
+			failed = append(failed, fmt.Sprintf("team %d: %v", w.TeamID, err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("digest delivery failed: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+func (n *ChatNotifier) digest(ctx context.Context, teamID int) (chatPayload, error) {
+	team, err := n.store.GetTeamByID(ctx, teamID)
+	if err != nil {
+		return chatPayload{}, err
+	}
+	prs, err := n.store.GetOpenPRsByTeam(ctx, teamID)
+	if err != nil {
+		return chatPayload{}, err
+	}
+	members, err := n.store.GetUsersByTeam(ctx, teamID)
+	if err != nil {
+		return chatPayload{}, err
+	}
+
+	ids := make([]int, 0, len(members))
+	for _, m := range members {
+		ids = append(ids, m.ID)
+	}
+	loads, err := n.store.GetOpenReviewCounts(ctx, ids)
+	if err != nil {
+		return chatPayload{}, err
+	}
+
+	var prLines strings.Builder
+	for i, pr := range prs {
+		if i == digestMaxPRs {
+			fmt.Fprintf(&prLines, "…and %d more\n", len(prs)-digestMaxPRs)
+			break
+		}
+		reviewers := mentionList(pr.Reviewers)
+		if pr.WaitingSince != nil {
+			reviewers = "_waiting for a reviewer_"
+		}
+		author := ""
+		if pr.Author != nil {
+			author = escape(pr.Author.Name)
+		}
+		fmt.Fprintf(&prLines, "• #%d %s by %s: %s\n", pr.ID, escape(pr.Title), author, reviewers)
+	}
+	if len(prs) == 0 {
+		prLines.WriteString("No open pull requests")
+	}
+
+	// Сначала самые загруженные
+	sort.SliceStable(members, func(i, j int) bool { return loads[members[i].ID] > loads[members[j].ID] })
+	var loadLines strings.Builder
+	for _, m := range members {
+		if !m.IsActive {
+			continue
+		}
+		fmt.Fprintf(&loadLines, "• %s: %d\n", escape(m.Name), loads[m.ID])
+	}
+	if loadLines.Len() == 0 {
+		loadLines.WriteString("No active members")
+	}
+
+	headline := fmt.Sprintf("Daily review digest for %s: %d open PR(s)", escape(team.Name), len(prs))
+	return chatPayload{
+		Text: headline,
+		Blocks: []chatBlock{
+			{Type: "header", Text: &chatText{Type: "plain_text", Text: "Daily review digest: " + team.Name}},
+			{Type: "section", Text: &chatText{Type: "mrkdwn", Text: fmt.Sprintf("*Open pull requests (%d)*\n%s", len(prs), prLines.String())}},
+			{Type: "section", Text: &chatText{Type: "mrkdwn", Text: "*Open reviews per reviewer*\n" + loadLines.String()}},
+		},
+	}, nil
+}
+
+func (n *ChatNotifier) post(ctx context.Context, url string, payload chatPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("chat webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// mention - упоминание пользователя в чате (имя, если ник не задан)
+func mention(u domain.User) string {
+	switch {
+	case u.ChatHandle == "":
+		return escape(u.Name)
+	case slackMemberID.MatchString(u.ChatHandle):
+		return "<@" + u.ChatHandle + ">"
+	default:
+		return "@" + u.ChatHandle
+	}
+}
+
+func mentionList(users []domain.User) string {
+	if len(users) == 0 {
+		return "none"
+	}
+	mentions := make([]string, 0, len(users))
+	for _, u := range users {
+		mentions = append(mentions, mention(u))
+	}
+	return strings.Join(mentions, ", ")
+}
+
+// escape экранирует управляющие символы разметки Slack
+func escape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
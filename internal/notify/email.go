@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"text/template"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// Mailer отправляет одно письмо
+type Mailer interface {
+	Send(ctx context.Context, to string, subject string, body string) error
+}
+
+// Стандартные тексты писем; команда может переопределить их своими шаблонами
+var defaultTemplates = map[string]domain.NotificationTemplate{
+	domain.EventReviewerAssigned: {
+		Subject: `Review requested: {{.PR.Title}}`,
+		Body: `Hi {{.Recipient.Name}},
+
+you have been assigned to review PR #{{.PR.ID}} "{{.PR.Title}}" by {{.AuthorName}}.
+`,
+	},
+	domain.EventReviewerUnassigned: {
+		Subject: `Review no longer needed: {{.PR.Title}}`,
+		Body: `Hi {{.Recipient.Name}},
+
+you are no longer a reviewer of PR #{{.PR.ID}} "{{.PR.Title}}" by {{.AuthorName}}.
+`,
+	},
+	domain.EventReviewReminder: {
+		Subject: `Reminder: review of {{.PR.Title}} is waiting`,
+		Body: `Hi {{.Recipient.Name}},
+
+PR #{{.PR.ID}} "{{.PR.Title}}" by {{.AuthorName}} is still waiting for your review.
+`,
+	},
+}
+
+// EmailNotifier пишет ревьюеру о назначении, снятии с PR и напоминаниях
+type EmailNotifier struct {
+	store  Store
+	mailer Mailer
+}
+
+func NewEmailNotifier(store Store, mailer Mailer) *EmailNotifier {
+	return &EmailNotifier{store: store, mailer: mailer}
+}
+
+func (n *EmailNotifier) Notify(ctx context.Context, msg Message) error {
+	if !domain.IsNotificationEvent(msg.Event.Type) || msg.Recipient == nil || msg.Recipient.Email == "" {
+		return nil
+	}
+	for _, e := range msg.Recipient.NotifyOptOut {
+		if e == msg.Event.Type {
+			return nil
+		}
+	}
+
+	tpl, err := n.store.GetNotificationTemplate(ctx, msg.Event.TeamID, msg.Event.Type)
+	if errors.Is(err, domain.ErrTemplateNotFound) {
+		def := defaultTemplates[msg.Event.Type]
+		tpl, err = &def, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	subject, body, err := render(tpl.Subject, tpl.Body, msg)
+	if err != nil {
+		return err
+	}
+	return n.mailer.Send(ctx, msg.Recipient.Email, subject, body)
+}
+
+// ValidateTemplate проверяет, что шаблон разбирается и применим к уведомлению
+func ValidateTemplate(subject string, body string) error {
+	author := &domain.User{ID: 1, Name: "Author"}
+	sample := Message{
+		Event:     domain.Event{Type: domain.EventReviewerAssigned, PRID: 1, UserID: 2, TeamID: 1},
+		PR:        &domain.PullRequest{ID: 1, Title: "Title", Status: domain.PRStatusOpen, AuthorID: 1, Author: author},
+		Recipient: &domain.User{ID: 2, Name: "Reviewer"},
+	}
+	_, _, err := render(subject, body, sample)
+	return err
+}
+
+func render(subject string, body string, msg Message) (string, string, error) {
+	renderedSubject, err := execute("subject", subject, msg)
+	if err != nil {
+		return "", "", err
+	}
+	renderedBody, err := execute("body", body, msg)
+	if err != nil {
+		return "", "", err
+	}
+	// Перевод строки в теме сломал бы заголовки письма
+	renderedSubject = strings.Join(strings.Fields(renderedSubject), " ")
+	return renderedSubject, renderedBody, nil
+}
+
+func execute(name string, text string, msg Message) (string, error) {
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, msg); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
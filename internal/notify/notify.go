@@ -0,0 +1,116 @@
+package notify
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// Message - доменное событие вместе с данными, нужными для текста уведомления
+type Message struct {
+	Event domain.Event
+	// PR с автором и ревьюерами
+	PR *domain.PullRequest
+	// Пользователь, которого касается событие (nil - событие не про конкретного ревьюера)
+	Recipient *domain.User
+}
+
+// AuthorName - имя автора PR (для шаблонов)
+func (m Message) AuthorName() string {
+	if m.PR == nil || m.PR.Author == nil {
+		return ""
+	}
+	return m.PR.Author.Name
+}
+
+// Notifier доставляет уведомление в конкретный канал (почта, чат и т.п.).
+// Notifier сам решает, интересно ли ему событие.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// Store - данные, которые нужны для уведомлений
+type Store interface {
+	GetPRByID(ctx context.Context, id int) (*domain.PullRequest, error)
+	GetUserByID(ctx context.Context, id int) (*domain.User, error)
+	GetNotificationTemplate(ctx context.Context, teamID int, eventType string) (*domain.NotificationTemplate, error)
+}
+
+// Dispatcher принимает доменные события от сервиса и асинхронно раздает их получателям.
+// Publish никогда не блокирует вызывающего: если очередь переполнена, событие отбрасывается,
+// поэтому задержка CreatePR и других операций не зависит от SMTP и прочих внешних систем.
+type Dispatcher struct {
+	store     Store
+	notifiers []Notifier
+	queue     chan domain.Event
+}
+
+func NewDispatcher(store Store, buffer int, notifiers ...Notifier) *Dispatcher {
+	return &Dispatcher{
+		store:     store,
+		notifiers: notifiers,
+		queue:     make(chan domain.Event, buffer),
+	}
+}
+
+// Publish реализует domain.EventPublisher
+func (d *Dispatcher) Publish(ctx context.Context, event domain.Event) {
+	select {
+	case d.queue <- event:
+	default:
+		log.Printf("Notification queue is full, dropping event %s for PR %d", event.Type, event.PRID)
+	}
+}
+
+// Run обрабатывает очередь событий в workers горутинах до отмены ctx
+func (d *Dispatcher) Run(ctx context.Context, workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case event := <-d.queue:
+					d.deliver(ctx, event)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// deliver собирает сообщение и передает его всем получателям. Ошибки доставки только логируются.
+func (d *Dispatcher) deliver(ctx context.Context, event domain.Event) {
+	msg := Message{Event: event}
+
+	pr, err := d.store.GetPRByID(ctx, event.PRID)
+	if err != nil {
+		log.Printf("Notification for event %s skipped: load PR %d: %v", event.Type, event.PRID, err)
+		return
+	}
+	msg.PR = pr
+
+	if event.UserID != 0 {
+		user, err := d.store.GetUserByID(ctx, event.UserID)
+		if err != nil {
+			log.Printf("Notification for event %s skipped: load user %d: %v", event.Type, event.UserID, err)
+			return
+		}
+		msg.Recipient = user
+	}
+
+	for _, n := range d.notifiers {
+		if err := n.Notify(ctx, msg); err != nil {
+			log.Printf("Failed to deliver %s notification for PR %d: %v", event.Type, event.PRID, err)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	prs       map[int]*domain.PullRequest
+	users     map[int]*domain.User
+	templates map[string]*domain.NotificationTemplate // ключ - тип события
+	webhooks  map[int]string                          // ключ - ID команды
+}
+
+func (s *fakeStore) GetPRByID(ctx context.Context, id int) (*domain.PullRequest, error) {
+	if pr, ok := s.prs[id]; ok {
+		return pr, nil
+	}
+	return nil, domain.ErrPRNotFound
+}
+
+func (s *fakeStore) GetUserByID(ctx context.Context, id int) (*domain.User, error) {
+	if u, ok := s.users[id]; ok {
+		return u, nil
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func (s *fakeStore) GetNotificationTemplate(ctx context.Context, teamID int, eventType string) (*domain.NotificationTemplate, error) {
+	if tpl, ok := s.templates[eventType]; ok {
+		return tpl, nil
+	}
+	return nil, domain.ErrTemplateNotFound
+}
+
+func (s *fakeStore) GetTeamByID(ctx context.Context, id int) (*domain.Team, error) {
+	return &domain.Team{ID: id, Name: "Core"}, nil
+}
+
+func (s *fakeStore) GetTeamChatWebhook(ctx context.Context, teamID int) (*domain.TeamChatWebhook, error) {
+	if url, ok := s.webhooks[teamID]; ok {
+		return &domain.TeamChatWebhook{TeamID: teamID, URL: url}, nil
+	}
+	return nil, domain.ErrChatWebhookNotFound
+}
+
+func (s *fakeStore) GetTeamChatWebhooks(ctx context.Context) ([]domain.TeamChatWebhook, error) {
+	var webhooks []domain.TeamChatWebhook
+	for teamID, url := range s.webhooks {
+		webhooks = append(webhooks, domain.TeamChatWebhook{TeamID: teamID, URL: url})
+	}
+	return webhooks, nil
+}
+
+func (s *fakeStore) GetOpenPRsByTeam(ctx context.Context, teamID int) ([]domain.PullRequest, error) {
+	var prs []domain.PullRequest
+	for _, pr := range s.prs {
+		if pr.Author.TeamID == teamID && pr.Status == domain.PRStatusOpen {
+			prs = append(prs, *pr)
+		}
+	}
+	return prs, nil
+}
+
+func (s *fakeStore) GetUsersByTeam(ctx context.Context, teamID int) ([]domain.User, error) {
+	var users []domain.User
+	for id := 1; id <= len(s.users); id++ {
+		if u := s.users[id]; u.TeamID == teamID {
+			users = append(users, *u)
+		}
+	}
+	return users, nil
+}
+
+func (s *fakeStore) GetOpenReviewCounts(ctx context.Context, userIDs []int) (map[int]int, error) {
+	counts := make(map[int]int)
+	for _, pr := range s.prs {
+		for _, r := range pr.Reviewers {
+			counts[r.ID]++
+		}
+	}
+	return counts, nil
+}
+
+type sentMail struct {
+	to, subject, body string
+}
+
+type fakeMailer struct {
+	mu   sync.Mutex
+	sent []sentMail
+}
+
+func (m *fakeMailer) Send(ctx context.Context, to string, subject string, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, sentMail{to, subject, body})
+	return nil
+}
+
+func newFakeStore() *fakeStore {
+	author := &domain.User{ID: 1, Name: "Alice", IsActive: true, TeamID: 1}
+	users := map[int]*domain.User{
+		1: author,
+		2: {ID: 2, Name: "Bob", IsActive: true, Email: "bob@example.com", ChatHandle: "U0BOB1234", TeamID: 1},
+		3: {ID: 3, Name: "Carol", IsActive: true, Email: "carol@example.com", ChatHandle: "carol", TeamID: 1,
+			NotifyOptOut: []string{domain.EventReviewReminder}},
+		4: {ID: 4, Name: "Dave", TeamID: 1},
+	}
+	return &fakeStore{
+		prs: map[int]*domain.PullRequest{
+			10: {ID: 10, Title: "Fix <login>", Status: domain.PRStatusOpen, AuthorID: 1, Author: author,
+				Reviewers: []domain.User{*users[2], *users[3]}},
+		},
+		users:     users,
+		templates: map[string]*domain.NotificationTemplate{},
+		webhooks:  map[int]string{},
+	}
+}
+
+func TestEmailNotifier(t *testing.T) {
+	store := newFakeStore()
+	store.templates[domain.EventReviewerAssigned] = &domain.NotificationTemplate{
+		TeamID:    1,
+		EventType: domain.EventReviewerAssigned,
+		Subject:   "[team] {{.PR.Title}}\nignored",
+		Body:      "{{.Recipient.Name}}, please review #{{.PR.ID}} from {{.AuthorName}}",
+	}
+	mailer := &fakeMailer{}
+	n := NewEmailNotifier(store, mailer)
+	ctx := context.Background()
+
+	msg := func(eventType string, userID int) Message {
+		return Message{
+			Event:     domain.Event{Type: eventType, PRID: 10, UserID: userID, TeamID: 1},
+			PR:        store.prs[10],
+			Recipient: store.users[userID],
+		}
+	}
+
+	// Шаблон команды
+	assert.NoError(t, n.Notify(ctx, msg(domain.EventReviewerAssigned, 2)))
+	// Стандартный шаблон
+	assert.NoError(t, n.Notify(ctx, msg(domain.EventReviewReminder, 2)))
+	// Отписка, нет адреса и событие без уведомления - писем нет
+	assert.NoError(t, n.Notify(ctx, msg(domain.EventReviewReminder, 3)))
+	assert.NoError(t, n.Notify(ctx, msg(domain.EventReviewerAssigned, 4)))
+	assert.NoError(t, n.Notify(ctx, msg(domain.EventReviewEscalated, 2)))
+
+	if assert.Len(t, mailer.sent, 2) {
+		assert.Equal(t, sentMail{"bob@example.com", "[team] Fix <login> ignored", "Bob, please review #10 from Alice"}, mailer.sent[0])
+		assert.Equal(t, "Reminder: review of Fix <login> is waiting", mailer.sent[1].subject)
+		assert.Contains(t, mailer.sent[1].body, `PR #10 "Fix <login>" by Alice`)
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	for eventType, tpl := range defaultTemplates {
+		assert.NoError(t, ValidateTemplate(tpl.Subject, tpl.Body), eventType)
+	}
+	assert.Error(t, ValidateTemplate("{{.PR.Title", "body"))
+	assert.Error(t, ValidateTemplate("subject", "{{.PR.Unknown}}"))
+}
+
+type chanNotifier chan Message
+
+func (c chanNotifier) Notify(ctx context.Context, msg Message) error {
+	c <- msg
+	return nil
+}
+
+func TestDispatcher(t *testing.T) {
+	store := newFakeStore()
+	out := make(chanNotifier, 1)
+	d := NewDispatcher(store, 1, out)
+
+	// Очередь на одно событие: второе отбрасывается, Publish не блокируется
+	d.Publish(context.Background(), domain.Event{Type: domain.EventReviewerAssigned, PRID: 10, UserID: 2, TeamID: 1})
+	d.Publish(context.Background(), domain.Event{Type: domain.EventReviewerAssigned, PRID: 10, UserID: 3, TeamID: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx, 2)
+		close(done)
+	}()
+
+	select {
+	case msg := <-out:
+		assert.Equal(t, "Fix <login>", msg.PR.Title)
+		assert.Equal(t, "Bob", msg.Recipient.Name)
+	case <-time.After(time.Second):
+		t.Fatal("event was not delivered")
+	}
+	select {
+	case msg := <-out:
+		t.Fatalf("unexpected delivery to %s", msg.Recipient.Name)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	<-done
+}
+
+// smtpSink - минимальный SMTP-сервер без расширений; расшифровку каждой сессии отдает в канал
+func smtpSink(t *testing.T) (addr string, received <-chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	out := make(chan string, 1)
+	handle := func(conn net.Conn) {
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		var transcript strings.Builder
+
+		reply("220 sink ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			transcript.WriteString(line)
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 sink")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				for {
+					data, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if data == ".\r\n" {
+						break
+					}
+					transcript.WriteString(data)
+				}
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				out <- transcript.String()
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go handle(conn)
+		}
+	}()
+	return ln.Addr().String(), out
+}
+
+func TestSMTPMailer(t *testing.T) {
+	addr, received := smtpSink(t)
+	host, portStr, _ := net.SplitHostPort(addr)
+	port, _ := net.LookupPort("tcp", portStr)
+
+	m := NewSMTPMailer(SMTPConfig{Host: host, Port: port, From: "reviews@example.com"})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, m.Send(ctx, "bob@example.com", "Привет", "line 1\nline 2\n"))
+
+	select {
+	case transcript := <-received:
+		assert.Contains(t, transcript, "MAIL FROM:<reviews@example.com>")
+		assert.Contains(t, transcript, "RCPT TO:<bob@example.com>")
+		assert.Contains(t, transcript, "To: bob@example.com\r\n")
+		assert.Contains(t, transcript, "Subject: =?utf-8?q?")
+		assert.Contains(t, transcript, "line 1\r\nline 2\r\n")
+	case <-time.After(5 * time.Second):
+		t.Fatal("mail was not received")
+	}
+
+	// STARTTLS обязателен, а сервер его не умеет - письмо не уходит
+	m = NewSMTPMailer(SMTPConfig{Host: host, Port: port, From: "reviews@example.com", StartTLS: true})
+	assert.Error(t, m.Send(ctx, "bob@example.com", "subject", "body"))
+}
+
+// chatSink принимает сообщения вебхука; сообщения, отправленные на /fail, отклоняет
+func chatSink(t *testing.T) (*httptest.Server, <-chan chatPayload) {
+	out := make(chan chatPayload, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var payload chatPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		out <- payload
+	}))
+	t.Cleanup(srv.Close)
+	return srv, out
+}
+
+func TestChatNotifier(t *testing.T) {
+	store := newFakeStore()
+	srv, received := chatSink(t)
+	store.webhooks[1] = srv.URL + "/hooks/core"
+	n := NewChatNotifier(store, srv.Client())
+	ctx := context.Background()
+
+	msg := Message{Event: domain.Event{Type: domain.EventPRAssigned, PRID: 10, TeamID: 1}, PR: store.prs[10]}
+	assert.NoError(t, n.Notify(ctx, msg))
+	payload := <-received
+	assert.Equal(t, "Reviewers assigned: PR #10 Fix &lt;login&gt;", payload.Text)
+	if assert.Len(t, payload.Blocks, 2) {
+		assert.Equal(t, "*Reviewers*\n<@U0BOB1234>, @carol", payload.Blocks[1].Fields[1].Text)
+	}
+
+	msg.Event.Type = domain.EventReviewerReplaced
+	msg.Recipient = store.users[4]
+	assert.NoError(t, n.Notify(ctx, msg))
+	payload = <-received
+	assert.Equal(t, "*Replaced*\nDave", payload.Blocks[1].Fields[2].Text)
+
+	// Персональные события и команды без вебхука в чат не идут
+	msg.Event.Type = domain.EventReviewerAssigned
+	assert.NoError(t, n.Notify(ctx, msg))
+	msg.Event = domain.Event{Type: domain.EventPRMerged, PRID: 10, TeamID: 2}
+	assert.NoError(t, n.Notify(ctx, msg))
+	assert.Empty(t, received)
+
+	store.webhooks[1] = srv.URL + "/fail"
+	msg.Event.TeamID = 1
+	assert.Error(t, n.Notify(ctx, msg))
+}
+
+func TestChatDigest(t *testing.T) {
+	store := newFakeStore()
+	srv, received := chatSink(t)
+	store.webhooks[1] = srv.URL + "/hooks/core"
+	n := NewChatNotifier(store, srv.Client())
+
+	assert.NoError(t, n.SendDigest(context.Background()))
+	payload := <-received
+	assert.Equal(t, "Daily review digest for Core: 1 open PR(s)", payload.Text)
+	if assert.Len(t, payload.Blocks, 3) {
+		assert.Contains(t, payload.Blocks[1].Text.Text, "• #10 Fix &lt;login&gt; by Alice: <@U0BOB1234>, @carol")
+		// Неактивные не показываются, нагрузка по убыванию
+		assert.Equal(t, "*Open reviews per reviewer*\n• Bob: 1\n• Carol: 1\n• Alice: 0\n", payload.Blocks[2].Text.Text)
+	}
+
+	store.webhooks[2] = srv.URL + "/fail"
+	assert.Error(t, n.SendDigest(context.Background()))
+}
@@ -0,0 +1,172 @@
+// Package ratelimit ограничивает частоту запросов алгоритмом token bucket.
+// Корзины хранятся в Store: в памяти процесса (MemoryStore) или в общей БД для нескольких реплик.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit - скорость пополнения корзины и ее емкость (сколько запросов можно сделать подряд)
+type Limit struct {
+	Rate  float64 // токенов в секунду
+	Burst int
+}
+
+// IsZero - лимит не задан (запросы не ограничиваются)
+func (l Limit) IsZero() bool {
+	return l.Rate <= 0 || l.Burst <= 0
+}
+
+// Window - за сколько пустая корзина наполняется целиком
+func (l Limit) Window() time.Duration {
+	return time.Duration(float64(l.Burst) / l.Rate * float64(time.Second))
+}
+
+// Result - итог попытки взять токен
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Через сколько появится токен (для Retry-After при отказе)
+	RetryAfter time.Duration
+	// Через сколько корзина наполнится целиком
+	Reset time.Duration
+}
+
+// ResultFor вычисляет Result по числу токенов, оставшихся в корзине после попытки
+func ResultFor(limit Limit, tokens float64, allowed bool) Result {
+	res := Result{
+		Allowed:   allowed,
+		Limit:     limit.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     secondsToDuration((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !allowed {
+		res.RetryAfter = secondsToDuration((1 - tokens) / limit.Rate)
+	}
+	return res
+}
+
+func secondsToDuration(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
+
+// Store - хранилище корзин
+type Store interface {
+	// Take пополняет корзину key по прошедшему времени и пытается взять из нее один токен
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Config - лимиты по маршрутам. Ключ Routes - метод и шаблон пути маршрута: "POST /api/v1/prs".
+type Config struct {
+	// Лимит для маршрутов без собственного (нулевой - без ограничения)
+	Default Limit
+	Routes  map[string]Limit
+}
+
+// LimitFor возвращает лимит маршрута
+func (c Config) LimitFor(route string) Limit {
+	if limit, ok := c.Routes[route]; ok {
+		return limit
+	}
+	return c.Default
+}
+
+// ParseLimit разбирает лимит вида "10/s", "100/m", "1000/h" или "5/30s".
+// Емкость корзины равна числу запросов за период.
+func ParseLimit(s string) (Limit, error) {
+	count, period, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: expected <count>/<period>", s)
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: count must be a positive integer", s)
+	}
+
+	var d time.Duration
+	switch period {
+	case "s":
+		d = time.Second
+	case "m":
+		d = time.Minute
+	case "h":
+		d = time.Hour
+	default:
+		d, err = time.ParseDuration(period)
+		if err != nil || d <= 0 {
+			return Limit{}, fmt.Errorf("invalid rate limit %q: unknown period %q", s, period)
+		}
+	}
+	return Limit{Rate: float64(n) / d.Seconds(), Burst: n}, nil
+}
+
+// ParseConfig собирает Config из лимита по умолчанию и списка лимитов маршрутов вида
+// "POST /api/v1/prs=10/m; POST /api/v1/teams=5/m". Пустые строки - без ограничений.
+func ParseConfig(defaultLimit, routes string) (Config, error) {
+	cfg := Config{Routes: make(map[string]Limit)}
+	if strings.TrimSpace(defaultLimit) != "" {
+		limit, err := ParseLimit(defaultLimit)
+		if err != nil {
+			return Config{}, err
+		}
+		cfg.Default = limit
+	}
+
+	for _, entry := range strings.Split(routes, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return Config{}, fmt.Errorf("invalid route rate limit %q: expected <METHOD path>=<limit>", entry)
+		}
+		limit, err := ParseLimit(value)
+		if err != nil {
+			return Config{}, err
+		}
+		cfg.Routes[strings.Join(strings.Fields(route), " ")] = limit
+	}
+	return cfg, nil
+}
+
+// Limiter применяет лимиты маршрутов; у каждого клиента своя корзина на каждый маршрут
+type Limiter struct {
+	store Store
+	cfg   Config
+}
+
+func NewLimiter(store Store, cfg Config) *Limiter {
+	return &Limiter{store: store, cfg: cfg}
+}
+
+// Allow учитывает запрос клиента к маршруту. ok == false - маршрут не ограничен.
+func (l *Limiter) Allow(ctx context.Context, route, client string) (res Result, ok bool, err error) {
+	limit := l.cfg.LimitFor(route)
+	if limit.IsZero() {
+		return Result{Allowed: true}, false, nil
+	}
+	res, err = l.store.Take(ctx, route+"|"+client, limit)
+	if err != nil {
+		return Result{}, true, err
+	}
+	return res, true, nil
+}
+
+// Policy - значение заголовка RateLimit-Policy для маршрута ("10;w=60"), пусто - маршрут не ограничен
+func (l *Limiter) Policy(route string) string {
+	limit := l.cfg.LimitFor(route)
+	if limit.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%d;w=%d", limit.Burst, int(math.Ceil(limit.Window().Seconds())))
+}
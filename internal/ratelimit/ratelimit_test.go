@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig("100/m", "POST  /api/v1/prs=10/s; POST /api/v1/teams=5/30s")
+	assert.NoError(t, err)
+	assert.Equal(t, Limit{Rate: 100.0 / 60, Burst: 100}, cfg.Default)
+	assert.Equal(t, Limit{Rate: 10, Burst: 10}, cfg.LimitFor("POST /api/v1/prs"))
+	assert.Equal(t, Limit{Rate: 5.0 / 30, Burst: 5}, cfg.LimitFor("POST /api/v1/teams"))
+	assert.Equal(t, cfg.Default, cfg.LimitFor("GET /api/v1/prs/:id"))
+
+	cfg, err = ParseConfig("", "")
+	assert.NoError(t, err)
+	assert.True(t, cfg.Default.IsZero())
+
+	for _, bad := range []string{"10", "0/s", "x/m", "10/week"} {
+		_, err := ParseLimit(bad)
+		assert.Error(t, err, bad)
+	}
+	_, err = ParseConfig("", "POST /prs")
+	assert.Error(t, err)
+}
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Rate: 2, Burst: 3}
+	ctx := context.Background()
+
+	// Корзина полная: можно сделать Burst запросов подряд
+	for i := 2; i >= 0; i-- {
+		res, err := store.Take(ctx, "a", limit)
+		assert.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, i, res.Remaining)
+	}
+
+	res, _ := store.Take(ctx, "a", limit)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+	assert.Equal(t, 1500*time.Millisecond, res.Reset)
+
+	// У другого клиента своя корзина
+	res, _ = store.Take(ctx, "b", limit)
+	assert.True(t, res.Allowed)
+
+	// За полсекунды появляется один токен
+	now = now.Add(500 * time.Millisecond)
+	res, _ = store.Take(ctx, "a", limit)
+	assert.True(t, res.Allowed)
+	res, _ = store.Take(ctx, "a", limit)
+	assert.False(t, res.Allowed)
+
+	// Наполнившиеся корзины удаляются
+	now = now.Add(time.Hour)
+	store.Take(ctx, "c", limit)
+	assert.Len(t, store.buckets, 1)
+}
+
+func TestLimiterUnlimitedRoute(t *testing.T) {
+	limiter := NewLimiter(NewMemoryStore(), Config{Routes: map[string]Limit{"POST /prs": {Rate: 1, Burst: 1}}})
+
+	res, limited, err := limiter.Allow(context.Background(), "GET /prs/:id", "ip:1.2.3.4")
+	assert.NoError(t, err)
+	assert.False(t, limited)
+	assert.True(t, res.Allowed)
+	assert.Empty(t, limiter.Policy("GET /prs/:id"))
+	assert.Equal(t, "1;w=1", limiter.Policy("POST /prs"))
+}
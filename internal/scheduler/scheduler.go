@@ -0,0 +1,127 @@
+// Package scheduler запускает периодические задачи по cron-расписанию.
+// При нескольких репликах задачи выполняет только ведущая (см. Leader).
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Leader - выбор ведущей реплики
+type Leader interface {
+	// TryLead возвращает true, если эта реплика ведущая (при необходимости пытается ею стать)
+	TryLead(ctx context.Context) (bool, error)
+	// Resign снимает лидерство, чтобы другая реплика могла его забрать
+	Resign(ctx context.Context) error
+}
+
+// JobFunc - тело задачи
+type JobFunc func(ctx context.Context) error
+
+type job struct {
+	name     string
+	schedule *Schedule
+	run      JobFunc
+	next     time.Time
+}
+
+// Scheduler хранит задачи и запускает их, когда подходит время
+type Scheduler struct {
+	leader Leader
+	tick   time.Duration
+	now    func() time.Time
+
+	mu   sync.Mutex
+	jobs []*job
+}
+
+// New создает планировщик. leader == nil - задачи выполняются всегда (одна реплика).
+func New(leader Leader) *Scheduler {
+	return &Scheduler{
+		leader: leader,
+		tick:   15 * time.Second,
+		now:    time.Now,
+	}
+}
+
+// Add регистрирует задачу с cron-расписанием spec
+func (s *Scheduler) Add(name string, spec string, run JobFunc) error {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, &job{
+		name:     name,
+		schedule: schedule,
+		run:      run,
+		next:     schedule.Next(s.now()),
+	})
+	return nil
+}
+
+// Run проверяет расписание до отмены ctx. Задачи выполняются последовательно в этой горутине.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if s.leader != nil {
+				// ctx уже отменен, поэтому снимаем лидерство с отдельным таймаутом
+				resignCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if err := s.leader.Resign(resignCtx); err != nil {
+					log.Printf("Scheduler: failed to resign leadership: %v", err)
+				}
+				cancel()
+			}
+			return
+		case <-ticker.C:
+			s.runDue(ctx)
+		}
+	}
+}
+
+// runDue выполняет задачи, время которых подошло. Неведущая реплика только сдвигает расписание.
+func (s *Scheduler) runDue(ctx context.Context) {
+	now := s.now()
+
+	s.mu.Lock()
+	var due []*job
+	for _, j := range s.jobs {
+		if !j.next.After(now) {
+			due = append(due, j)
+			j.next = j.schedule.Next(now)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(due) == 0 {
+		return
+	}
+
+	if s.leader != nil {
+		leading, err := s.leader.TryLead(ctx)
+		if err != nil {
+			log.Printf("Scheduler: leader election failed: %v", err)
+			return
+		}
+		if !leading {
+			return
+		}
+	}
+
+	for _, j := range due {
+		started := time.Now()
+		if err := j.run(ctx); err != nil {
+			log.Printf("Scheduler: job %s failed: %v", j.name, err)
+			continue
+		}
+		log.Printf("Scheduler: job %s done in %s", j.name, time.Since(started).Round(time.Millisecond))
+	}
+}
@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleNext(t *testing.T) {
+	base := time.Date(2026, time.March, 2, 10, 7, 30, 0, time.UTC) // понедельник
+
+	cases := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, time.March, 2, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, time.March, 2, 10, 15, 0, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2026, time.March, 3, 9, 0, 0, 0, time.UTC)},
+		{"30 9-18/3 * * *", time.Date(2026, time.March, 2, 12, 30, 0, 0, time.UTC)},
+		{"0 10 * * 0", time.Date(2026, time.March, 8, 10, 0, 0, 0, time.UTC)},
+		{"0 0 1 1,6 *", time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		s, err := ParseSchedule(c.spec)
+		assert.NoError(t, err, c.spec)
+		assert.Equal(t, c.want, s.Next(base), c.spec)
+	}
+
+	for _, bad := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "a * * * *", "5-1 * * * *"} {
+		_, err := ParseSchedule(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+type fakeLeader struct {
+	leading bool
+}
+
+func (l *fakeLeader) TryLead(ctx context.Context) (bool, error) { return l.leading, nil }
+func (l *fakeLeader) Resign(ctx context.Context) error          { l.leading = false; return nil }
+
+func TestOnlyLeaderRunsJobs(t *testing.T) {
+	now := time.Date(2026, time.March, 2, 10, 0, 30, 0, time.UTC)
+	leader := &fakeLeader{}
+	s := New(leader)
+	s.now = func() time.Time { return now }
+
+	runs := 0
+	assert.NoError(t, s.Add("count", "* * * * *", func(ctx context.Context) error {
+		runs++
+		return nil
+	}))
+
+	// Ведомая реплика пропускает срабатывание
+	now = now.Add(time.Minute)
+	s.runDue(context.Background())
+	assert.Equal(t, 0, runs)
+
+	// Ведущая выполняет следующее
+	leader.leading = true
+	now = now.Add(time.Minute)
+	s.runDue(context.Background())
+	assert.Equal(t, 1, runs)
+
+	// Повторная проверка в ту же минуту задачу не запускает
+	s.runDue(context.Background())
+	assert.Equal(t, 1, runs)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net/mail"
+	"strings"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// --- Directory Import/Export ---
+
+// ImportDirectory проверяет строки и выполняет upsert одной транзакцией. Если хотя бы одна строка
+// с ошибкой, ничего не применяется: отчет показывает, что произошло бы с остальными строками.
+// После применения открытые ревью деактивированных и переведенных пользователей переназначаются.
+func (s *Manager) ImportDirectory(ctx context.Context, records []domain.DirectoryRecord, dryRun bool) (*domain.ImportReport, error) {
+	report := &domain.ImportReport{DryRun: dryRun, Rows: make([]domain.ImportRowResult, len(records))}
+
+	valid := make([]domain.DirectoryRecord, 0, len(records))
+	validRows := make([]int, 0, len(records))
+	for i, rec := range records {
+		report.Rows[i].Row = i + 1
+		rec, err := normalizeRecord(rec)
+		if err != nil {
+			report.Rows[i].Action, report.Rows[i].Error = domain.ImportFailed, err.Error()
+			report.Failed++
+			continue
+		}
+		valid = append(valid, rec)
+		validRows = append(validRows, i)
+	}
+
+	apply := !dryRun && report.Failed == 0
+	results, err := s.repo.ImportDirectory(ctx, valid, !apply)
+	if err != nil {
+		return nil, err
+	}
+	for j, res := range results {
+		i := validRows[j]
+		res.Row = i + 1
+		report.Rows[i] = res
+		switch res.Action {
+		case domain.ImportCreated:
+			report.Created++
+		case domain.ImportUpdated:
+			report.Updated++
+		case domain.ImportUnchanged:
+			report.Unchanged++
+		default:
+			report.Failed++
+		}
+	}
+
+	// Хранилище откатывает транзакцию при ошибке в любой из строк
+	report.Applied = apply && report.Failed == 0
+	if report.Applied {
+		s.reassignImported(ctx, report.Rows)
+		// Могли появиться активные кандидаты для PR из очереди
+		s.signalQueue()
+	}
+	return report, nil
+}
+
+// reassignImported переназначает открытые ревью пользователей, которых примененный импорт деактивировал
+// (как MassDeactivateTeamUsers) или перевел в другую команду (как UpdateUser: PR старой команды, замена
+// из нее же). Ошибки переназначения не отменяют импорт и только пишутся в лог.
+func (s *Manager) reassignImported(ctx context.Context, rows []domain.ImportRowResult) {
+	for _, row := range rows {
+		var err error
+		switch {
+		case row.Deactivated:
+			err = s.reassignOpenReviews(ctx, row.UserID, true)
+		case row.PreviousTeamID != 0:
+			oldTeamID := row.PreviousTeamID
+			fromOldTeam := func(pr *domain.PullRequest) bool { return prTeamID(pr) == oldTeamID }
+			err = s.reassignReviews(ctx, row.UserID, false, fromOldTeam, oldTeamID)
+		}
+		if err != nil {
+			log.Printf("Failed to reassign reviews of user %d on directory import: %v", row.UserID, err)
+		}
+	}
+}
+
+// ExportDirectory выгружает команды и их участников (по основной команде) в формате импорта.
+// Архивные команды и удаленные пользователи не выгружаются; команда без участников - строка без пользователя.
+func (s *Manager) ExportDirectory(ctx context.Context) ([]domain.DirectoryRecord, error) {
+	teams, err := s.repo.GetTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.repo.GetUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byTeam := make(map[int][]domain.User)
+	for _, u := range users {
+		if u.DeletedAt == nil {
+			byTeam[u.TeamID] = append(byTeam[u.TeamID], u)
+		}
+	}
+
+	records := make([]domain.DirectoryRecord, 0, len(teams)+len(users))
+	for _, team := range teams {
+		if team.ArchivedAt != nil {
+			continue
+		}
+		members := byTeam[team.ID]
+		if len(members) == 0 {
+			records = append(records, domain.DirectoryRecord{Team: team.Name})
+			continue
+		}
+		for _, u := range members {
+			active := u.IsActive
+			records = append(records, domain.DirectoryRecord{
+				Team:       team.Name,
+				User:       u.Name,
+				Login:      u.Login,
+				Email:      u.Email,
+				ChatHandle: u.ChatHandle,
+				IsActive:   &active,
+			})
+		}
+	}
+	return records, nil
+}
+
+// normalizeRecord обрезает пробелы и проверяет строку импорта
+func normalizeRecord(rec domain.DirectoryRecord) (domain.DirectoryRecord, error) {
+	rec.Team = strings.TrimSpace(rec.Team)
+	rec.User = strings.TrimSpace(rec.User)
+	rec.Login = strings.TrimPrefix(strings.TrimSpace(rec.Login), "@")
+	rec.Email = strings.TrimSpace(rec.Email)
+	rec.ChatHandle = strings.TrimSpace(rec.ChatHandle)
+
+	if rec.Team == "" {
+		return rec, domain.ErrInvalidTeamName
+	}
+	if rec.User == "" {
+		if rec.Login != "" || rec.Email != "" || rec.ChatHandle != "" || rec.IsActive != nil {
+			return rec, domain.ErrInvalidUserName
+		}
+		return rec, nil
+	}
+	if rec.Email != "" {
+		addr, err := mail.ParseAddress(rec.Email)
+		if err != nil || addr.Address != rec.Email {
+			return rec, domain.ErrInvalidEmail
+		}
+	}
+	return rec, nil
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// --- PR History & Events ---
+
+func (s *Manager) GetPRHistory(ctx context.Context, prID int) ([]domain.PRHistoryEntry, error) {
+	if _, err := s.repo.GetPRByID(ctx, prID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetPRHistory(ctx, prID)
+}
+
+// GetEventsAfter читает журнал событий после курсора afterID
+func (s *Manager) GetEventsAfter(ctx context.Context, afterID int64, filter domain.EventFilter, limit int) ([]domain.Event, error) {
+	return s.repo.GetEventsAfter(ctx, afterID, filter, limit)
+}
+
+func (s *Manager) GetLatestEventID(ctx context.Context) (int64, error) {
+	return s.repo.GetLatestEventID(ctx)
+}
+
+// EventsChanged возвращает канал, который закроется при записи следующего события этим процессом.
+// События других реплик так не видны - подписчики дополнительно периодически перечитывают журнал.
+func (s *Manager) EventsChanged() <-chan struct{} {
+	s.eventsMu.Lock()
+	defer s.eventsMu.Unlock()
+	return s.eventsChanged
+}
+
+// PurgeEvents удаляет из журнала события старше retention
+func (s *Manager) PurgeEvents(ctx context.Context, retention time.Duration) error {
+	deleted, err := s.repo.DeleteEventsBefore(ctx, s.now().Add(-retention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		log.Printf("Purged %d old events", deleted)
+	}
+	return nil
+}
+
+// SetEventPublisher подключает получателя доменных событий (по умолчанию события только логируются)
+func (s *Manager) SetEventPublisher(publisher domain.EventPublisher) {
+	s.events = publisher
+}
+
+// --- Helpers ---
+
+// recordHistory пишет событие в историю PR. История вспомогательная, поэтому ошибка
+// записи только логируется и не отменяет уже выполненное действие.
+func (s *Manager) recordHistory(ctx context.Context, prID int, event string, userID int, details string) {
+	entry := &domain.PRHistoryEntry{
+		PullRequestID: prID,
+		Event:         event,
+		Details:       details,
+		CreatedAt:     s.now(),
+	}
+	if userID != 0 {
+		entry.UserID = &userID
+	}
+	if err := s.repo.AddPRHistory(ctx, entry); err != nil {
+		log.Printf("Failed to record PR %d history (%s): %v", prID, event, err)
+	}
+}
+
+// publishEvent публикует событие PR prID (teamID - команда автора).
+// userID - пользователь, которого касается событие (0 - событие о PR в целом).
+// Событие сначала пишется в журнал (для потока событий), затем передается получателю.
+func (s *Manager) publishEvent(ctx context.Context, eventType string, prID int, teamID int, userID int) {
+	event := domain.Event{
+		Type:   eventType,
+		PRID:   prID,
+		UserID: userID,
+		TeamID: teamID,
+		At:     s.now(),
+	}
+	// Как и история, журнал вспомогательный: ошибка записи не отменяет действие
+	if err := s.repo.AddEvent(ctx, &event); err != nil {
+		log.Printf("Failed to store event %s for PR %d: %v", eventType, prID, err)
+	} else {
+		s.broadcastEventsChanged()
+	}
+	s.events.Publish(ctx, event)
+}
+
+func (s *Manager) broadcastEventsChanged() {
+	s.eventsMu.Lock()
+	defer s.eventsMu.Unlock()
+	close(s.eventsChanged)
+	s.eventsChanged = make(chan struct{})
+}
+
+// prTeamID возвращает команду автора PR (0, если автор не загружен)
+func prTeamID(pr *domain.PullRequest) int {
+	if pr.Author == nil {
+		return 0
+	}
+	return pr.Author.TeamID
+}
+
+// logPublisher - получатель событий по умолчанию: просто пишет их в лог
+type logPublisher struct{}
+
+func (logPublisher) Publish(ctx context.Context, event domain.Event) {
+	log.Printf("Event %s: pr=%d user=%d team=%d", event.Type, event.PRID, event.UserID, event.TeamID)
+}
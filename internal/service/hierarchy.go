@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// --- Team Hierarchy Logic ---
+
+// MoveTeam переносит команду (вместе с поддеревом) под parentID; nil - на верхний уровень
+func (s *Manager) MoveTeam(ctx context.Context, teamID int, parentID *int) (*domain.Team, error) {
+	if _, err := s.repo.GetTeamByID(ctx, teamID); err != nil {
+		return nil, err
+	}
+	if parentID != nil {
+		if _, err := s.repo.GetTeamByID(ctx, *parentID); err != nil {
+			return nil, err
+		}
+		// Новый родитель не может лежать в поддереве переносимой команды
+		subtree, err := s.repo.GetSubtreeTeamIDs(ctx, teamID)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range subtree {
+			if id == *parentID {
+				return nil, domain.ErrTeamCycle
+			}
+		}
+	}
+
+	if err := s.repo.SetTeamParent(ctx, teamID, parentID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetTeamByID(ctx, teamID)
+}
+
+// GetSubtreeMembers возвращает участников команды и всех ее потомков (каждого один раз, включая неактивных)
+func (s *Manager) GetSubtreeMembers(ctx context.Context, teamID int) ([]domain.User, error) {
+	subtree, err := s.repo.GetSubtreeTeamIDs(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	memberships, err := s.repo.GetMembersOfTeams(ctx, subtree)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]bool)
+	users := make([]domain.User, 0, len(memberships))
+	for _, m := range memberships {
+		if m.User == nil || seen[m.UserID] {
+			continue
+		}
+		seen[m.UserID] = true
+		users = append(users, *m.User)
+	}
+	return users, nil
+}
+
+// GetTeamReviewerStats - статистика назначений по ревьюерам из поддерева команды
+func (s *Manager) GetTeamReviewerStats(ctx context.Context, teamID int) (map[int]int, error) {
+	subtree, err := s.repo.GetSubtreeTeamIDs(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetReviewerStatsForTeams(ctx, subtree)
+}
+
+// --- Helpers ---
+
+// treeFallbackTeams - родительская команда, затем соседние (дети того же родителя) по возрастанию ID
+func (s *Manager) treeFallbackTeams(ctx context.Context, teamID int) ([]int, error) {
+	team, err := s.repo.GetTeamByID(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if team.ParentID == nil {
+		return nil, nil
+	}
+
+	teams := []int{*team.ParentID}
+	siblings, err := s.repo.GetChildTeams(ctx, team.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, sibling := range siblings {
+		if sibling.ID != teamID {
+			teams = append(teams, sibling.ID)
+		}
+	}
+	return teams, nil
+}
@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/Shishlyannikovvv/project-avito/internal/domain"
@@ -10,18 +13,45 @@ import (
 
 type Manager struct {
 	repo domain.Repository
+	// Источник текущего времени (подменяется в тестах)
+	now func() time.Time
+	// Сигнал фоновому обработчику очереди: у кого-то освободилось место под ревью
+	queueSignal chan struct{}
+	// Получатель доменных событий
+	events domain.EventPublisher
+	// Закрывается при записи следующего события (будит подписчиков потока событий)
+	eventsMu      sync.Mutex
+	eventsChanged chan struct{}
+	// Секрет псевдонимов для обезличенных снимков (пусто - обезличивание недоступно)
+	anonymizationKey []byte
 }
 
 func NewManager(repo domain.Repository) *Manager {
 	// Инициализируем рандом сидом времени, чтобы при каждом запуске был разный выбор
 	rand.Seed(time.Now().UnixNano())
-	return &Manager{repo: repo}
+	return &Manager{
+		repo:        repo,
+		now:         time.Now,
+		queueSignal: make(chan struct{}, 1),
+		events:      logPublisher{},
+
+		eventsChanged: make(chan struct{}),
+	}
 }
 
 // --- Team & User Logic ---
 
 func (s *Manager) CreateTeam(ctx context.Context, name string) (*domain.Team, error) {
-	team := &domain.Team{Name: name}
+	return s.CreateTeamWithParent(ctx, name, nil)
+}
+
+func (s *Manager) CreateTeamWithParent(ctx context.Context, name string, parentID *int) (*domain.Team, error) {
+	if parentID != nil {
+		if _, err := s.repo.GetTeamByID(ctx, *parentID); err != nil {
+			return nil, err
+		}
+	}
+	team := &domain.Team{Name: name, ParentID: parentID}
 	if err := s.repo.CreateTeam(ctx, team); err != nil {
 		return nil, err
 	}
@@ -30,13 +60,15 @@ func (s *Manager) CreateTeam(ctx context.Context, name string) (*domain.Team, er
 
 func (s *Manager) CreateUser(ctx context.Context, name string, teamID int) (*domain.User, error) {
 	// Проверяем существование команды
-	_, err := s.repo.GetTeamByName(ctx, "") // Можно оптимизировать, проверив ID, но пока так
-	// В GORM проще просто попытаться создать, если FK constraint упадет - обработаем
+	if _, err := s.repo.GetTeamByID(ctx, teamID); err != nil {
+		return nil, err
+	}
 
 	user := &domain.User{
-		Name:     name,
-		TeamID:   teamID,
-		IsActive: true, // По умолчанию активен
+		Name:      name,
+		TeamID:    teamID,
+		IsActive:  true, // По умолчанию активен
+		Seniority: domain.SeniorityJunior,
 	}
 	if err := s.repo.CreateUser(ctx, user); err != nil {
 		return nil, err
@@ -48,9 +80,71 @@ func (s *Manager) DeleteUser(ctx context.Context, userID int) error {
 	return s.repo.DeactivateUser(ctx, userID)
 }
 
+func (s *Manager) SetUserSeniority(ctx context.Context, userID int, seniority string) (*domain.User, error) {
+	if domain.SeniorityRank(seniority) == 0 {
+		return nil, domain.ErrInvalidSeniority
+	}
+	if err := s.repo.SetUserSeniority(ctx, userID, seniority); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserByID(ctx, userID)
+}
+
+func (s *Manager) SetUserMaxOpenReviews(ctx context.Context, userID int, limit *int) (*domain.User, error) {
+	// nil сбрасывает персональный лимит к лимиту команды
+	if limit != nil && *limit < 1 {
+		return nil, domain.ErrInvalidCapacity
+	}
+	if err := s.repo.SetUserMaxOpenReviews(ctx, userID, limit); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserByID(ctx, userID)
+}
+
+func (s *Manager) MassDeactivateTeamUsers(ctx context.Context, teamID int, userIDs ...int) error {
+	if _, err := s.repo.GetTeamByID(ctx, teamID); err != nil {
+		return err
+	}
+
+	members, err := s.repo.GetUsersByTeam(ctx, teamID)
+	if err != nil {
+		return err
+	}
+
+	// Деактивируем только участников этой команды; без списка - всю команду
+	requested := make(map[int]bool, len(userIDs))
+	for _, id := range userIDs {
+		requested[id] = true
+	}
+	targets := make([]int, 0, len(members))
+	for _, m := range members {
+		if len(userIDs) == 0 || requested[m.ID] {
+			targets = append(targets, m.ID)
+		}
+	}
+
+	// Сначала деактивируем всех, чтобы они не выбирались друг другу на замену
+	for _, id := range targets {
+		if err := s.repo.DeactivateUser(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range targets {
+		if err := s.reassignOpenReviews(ctx, id, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // --- PR Logic ---
 
 func (s *Manager) CreatePR(ctx context.Context, title string, authorID int) (*domain.PullRequest, error) {
+	return s.CreatePRWithChanges(ctx, title, authorID, nil, nil)
+}
+
+func (s *Manager) CreatePRWithChanges(ctx context.Context, title string, authorID int, files []string, labels []string) (*domain.PullRequest, error) {
 	// 1. Получаем автора, чтобы узнать его команду
 	author, err := s.repo.GetUserByID(ctx, authorID)
 	if err != nil {
@@ -61,144 +155,192 @@ func (s *Manager) CreatePR(ctx context.Context, title string, authorID int) (*do
 		// Опционально: запрещаем неактивным создавать PR, но в ТЗ этого нет, так что оставим.
 	}
 
-	// 2. Ищем кандидатов в ревьюеры (все активные из той же команды)
-	candidates, err := s.repo.GetUsersByTeam(ctx, author.TeamID)
+	// PR создается от имени основной команды автора
+	memberships, err := s.repo.GetUserMemberships(ctx, authorID)
 	if err != nil {
 		return nil, err
 	}
+	// Без участия в основной команде права создавать PR тоже нет
+	if m := membershipIn(memberships, author.TeamID); m == nil || !m.CanAuthor {
+		return nil, domain.ErrAuthorNotAllowed
+	}
 
-	// 3. Фильтруем: ревьюер != автор
-	validCandidates := make([]domain.User, 0)
-	for _, c := range candidates {
-		if c.ID != author.ID {
-			validCandidates = append(validCandidates, c)
-		}
+	// 2. Политика назначения команды автора
+	policy, err := s.teamPolicy(ctx, author.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Предпочтения: владельцы измененных путей и эксперты по меткам
+	labels = normalizeTags(labels)
+	pref, err := s.buildPreference(ctx, author.TeamID, files, labels)
+	if err != nil {
+		return nil, err
 	}
 
-	// 4. Выбираем до 2 случайных ревьюеров
-	reviewers := selectRandomReviewers(validCandidates, 2)
+	// 4. Подбираем ревьюеров: активные, не автор, по правилам политики (с запасными командами).
+	// Подбор и создание PR - под блокировкой кандидатов, чтобы параллельные PR не превысили их лимит ревью.
+	unlock, err := s.lockCandidates(ctx, author.TeamID, policy)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	exclude := map[int]bool{author.ID: true}
+	reviewers, report, err := s.pickReviewers(ctx, author.TeamID, policy, pref, exclude, policy.MinReviewers, policy.MaxReviewers)
+	if err != nil {
+		return nil, err
+	}
 
 	// 5. Создаем PR
 	pr := &domain.PullRequest{
-		Title:     title,
-		Status:    domain.PRStatusOpen,
-		AuthorID:  authorID,
-		Reviewers: reviewers,
+		Title:        title,
+		Status:       domain.PRStatusOpen,
+		AuthorID:     authorID,
+		Reviewers:    reviewers,
+		ChangedFiles: files,
+		Labels:       labels,
+	}
+
+	// Все кандидаты заняты - PR ждет освободившегося ревьюера в очереди
+	if len(reviewers) == 0 {
+		now := s.now()
+		pr.WaitingSince = &now
 	}
 
 	if err := s.repo.CreatePR(ctx, pr); err != nil {
 		return nil, err
 	}
 
+	s.recordHistory(ctx, pr.ID, domain.HistoryCreated, authorID, "")
+	for _, r := range reviewers {
+		s.recordHistory(ctx, pr.ID, domain.HistoryReviewerAdded, r.ID, "auto-assigned")
+		s.publishEvent(ctx, domain.EventReviewerAssigned, pr.ID, author.TeamID, r.ID)
+	}
+	if len(reviewers) > 0 {
+		s.publishEvent(ctx, domain.EventPRAssigned, pr.ID, author.TeamID, 0)
+	}
+
+	pr.Assignment = report
+	if err := s.fillQueueInfo(ctx, pr); err != nil {
+		return nil, err
+	}
 	return pr, nil
 }
 
 func (s *Manager) MergePR(ctx context.Context, prID int) (*domain.PullRequest, error) {
-	pr, err := s.repo.GetPRByID(ctx, prID)
+	pr, err := s.repo.UpdateOpenPR(ctx, prID, func(pr *domain.PullRequest) error {
+		pr.Status = domain.PRStatusMerged
+		pr.WaitingSince = nil
+		return nil
+	})
+	if errors.Is(err, domain.ErrPRAlreadyMerged) {
+		// Идемпотентность: если уже смержен, просто возвращаем его
+		return s.repo.GetPRByID(ctx, prID)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	// Идемпотентность: если уже смержен, просто возвращаем его
-	if pr.Status == domain.PRStatusMerged {
-		return pr, nil
-	}
+	s.recordHistory(ctx, pr.ID, domain.HistoryMerged, 0, "")
+	s.publishEvent(ctx, domain.EventPRMerged, pr.ID, prTeamID(pr), 0)
 
-	pr.Status = domain.PRStatusMerged
-	if err := s.repo.UpdatePR(ctx, pr); err != nil {
-		return nil, err
-	}
+	// Ревьюеры этого PR освободились - можно разбирать очередь
+	s.signalQueue()
 	return pr, nil
 }
 
-func (s *Manager) RerollReviewer(ctx context.Context, prID int, oldReviewerID int) (*domain.PullRequest, error) {
+func (s *Manager) GetPR(ctx context.Context, prID int) (*domain.PullRequest, error) {
 	pr, err := s.repo.GetPRByID(ctx, prID)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.fillQueueInfo(ctx, pr); err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
+func (s *Manager) RerollReviewer(ctx context.Context, prID int, oldReviewerID int) (*domain.PullRequest, error) {
+	return s.rerollReviewer(ctx, prID, oldReviewerID, 0)
+}
 
-	// Проверка: нельзя менять после мерджа
-	if pr.Status == domain.PRStatusMerged {
-		return nil, domain.ErrPRAlreadyMerged
+// rerollReviewer - RerollReviewer с заменой из команды fromTeamID (0 - из команды заменяемого ревьюера)
+func (s *Manager) rerollReviewer(ctx context.Context, prID int, oldReviewerID int, fromTeamID int) (*domain.PullRequest, error) {
+	// PR должен существовать и быть открытым
+	pr, err := s.loadOpenPR(ctx, prID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Проверка: был ли такой ревьюер вообще назначен?
-	isReviewerFound := false
-	for _, r := range pr.Reviewers {
-		if r.ID == oldReviewerID {
-			isReviewerFound = true
-			break
-		}
-	}
-	if !isReviewerFound {
+	if !hasReviewer(pr, oldReviewerID) {
 		return nil, domain.ErrUserNotFound // Или специфичную ошибку "User is not a reviewer on this PR"
 	}
 
-	// Получаем кандидатов (команда автора)
-	// Важный момент: ревьюер должен быть из команды АВТОРА или ЗАМЕНЯЕМОГО?
-	// В ТЗ: "из команды заменяемого ревьюера". Обычно это одна и та же команда,
-	// но будем брать команду автора для надежности, так как PR внутри команды.
-	if pr.Author == nil {
-		// Подгрузим автора если вдруг его нет (хотя Preload в repo должен был сработать)
-		author, err := s.repo.GetUserByID(ctx, pr.AuthorID)
-		if err != nil {
+	// Правила назначения - политика команды автора
+	policy, err := s.teamPolicy(ctx, pr.Author.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	// По ТЗ замена берется из команды заменяемого ревьюера
+	teamID := fromTeamID
+	if teamID == 0 {
+		if teamID, err = s.reviewerTeam(ctx, pr, policy, oldReviewerID); err != nil {
 			return nil, err
 		}
-		pr.Author = author
 	}
 
-	candidates, err := s.repo.GetUsersByTeam(ctx, pr.Author.TeamID)
+	pref, err := s.buildPreference(ctx, pr.Author.TeamID, pr.ChangedFiles, pr.Labels)
 	if err != nil {
 		return nil, err
 	}
 
-	// Фильтруем кандидатов:
-	// 1. Не автор
-	// 2. Не тот, кого убираем (oldReviewerID)
-	// 3. Не те, кто УЖЕ назначен ревьюером (кроме убираемого)
-	availableCandidates := make([]domain.User, 0)
-
-	// Создадим карту текущих ID ревьюеров для быстрой проверки
-	currentReviewerIDs := make(map[int]bool)
-	for _, r := range pr.Reviewers {
-		currentReviewerIDs[r.ID] = true
+	// Ищем одного с учетом политики (команда ревьюера, затем запасные) и предпочтений по файлам/меткам
+	unlock, err := s.lockCandidates(ctx, teamID, policy)
+	if err != nil {
+		return nil, err
 	}
-
-	for _, c := range candidates {
-		if c.ID == pr.AuthorID {
-			continue
+	defer unlock()
+
+	// Выбор и запись - по PR, перечитанному под блокировкой: параллельный мердж или реролл уже виден
+	var picked []domain.User
+	var report *domain.AssignmentReport
+	pr, err = s.repo.UpdateOpenPR(ctx, prID, func(pr *domain.PullRequest) error {
+		if !hasReviewer(pr, oldReviewerID) {
+			return domain.ErrUserNotFound
 		}
-		if c.ID == oldReviewerID {
-			continue
+
+		// Исключаем из кандидатов:
+		// 1. Автора
+		// 2. Того, кого убираем (oldReviewerID)
+		// 3. Тех, кто УЖЕ назначен ревьюером
+		exclude := map[int]bool{pr.AuthorID: true, oldReviewerID: true}
+		for _, r := range pr.Reviewers {
+			exclude[r.ID] = true
 		}
-		if currentReviewerIDs[c.ID] {
-			continue // Он уже ревьюер (второй ревьюер)
+		var err error
+		picked, report, err = s.pickReviewers(ctx, teamID, policy, pref, exclude, 1, 1)
+		if err != nil {
+			return err
 		}
-		availableCandidates = append(availableCandidates, c)
-	}
-
-	if len(availableCandidates) == 0 {
-		return nil, domain.ErrNoReviewersFound
-	}
-
-	// Выбираем одного случайного
-	newReviewer := availableCandidates[rand.Intn(len(availableCandidates))]
-
-	// Обновляем список ревьюеров в PR
-	newReviewersList := make([]domain.User, 0)
-	for _, r := range pr.Reviewers {
-		if r.ID == oldReviewerID {
-			newReviewersList = append(newReviewersList, newReviewer)
-		} else {
-			newReviewersList = append(newReviewersList, r)
+		if len(picked) == 0 {
+			return domain.ErrNoReviewersFound
 		}
-	}
-	pr.Reviewers = newReviewersList
-
-	if err := s.repo.UpdatePR(ctx, pr); err != nil {
+		swapReviewer(pr, oldReviewerID, picked[0])
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
-
+	s.recordHistory(ctx, pr.ID, domain.HistoryReviewerChanged, oldReviewerID,
+		fmt.Sprintf("rerolled, replaced by user %d", picked[0].ID))
+	s.publishEvent(ctx, domain.EventReviewerUnassigned, pr.ID, pr.Author.TeamID, oldReviewerID)
+	s.publishEvent(ctx, domain.EventReviewerAssigned, pr.ID, pr.Author.TeamID, picked[0].ID)
+	s.publishEvent(ctx, domain.EventReviewerReplaced, pr.ID, pr.Author.TeamID, oldReviewerID)
+	s.signalQueue()
+
+	pr.Assignment = report
 	return pr, nil
 }
 
@@ -206,8 +348,75 @@ func (s *Manager) GetReviewerPRs(ctx context.Context, reviewerID int) ([]domain.
 	return s.repo.GetPRsByReviewer(ctx, reviewerID)
 }
 
+// GetReviewerStats - сколько PR назначено каждому пользователю
+func (s *Manager) GetReviewerStats(ctx context.Context) (map[int]int, error) {
+	return s.repo.GetReviewerStats(ctx)
+}
+
 // --- Helpers ---
 
+// reassignOpenReviews переназначает все открытые PR, где userID - ревьюер.
+// Если замены нет и dropIfNoReplacement=true, ревьюер просто снимается с PR.
+func (s *Manager) reassignOpenReviews(ctx context.Context, userID int, dropIfNoReplacement bool) error {
+	return s.reassignReviews(ctx, userID, dropIfNoReplacement, nil, 0)
+}
+
+// reassignReviews - reassignOpenReviews только для PR, подходящих под match (nil - для всех),
+// с заменой из команды fromTeamID (0 - из команды ревьюера).
+// Ошибка на одном PR не прерывает обработку остальных; ошибки возвращаются вместе.
+func (s *Manager) reassignReviews(ctx context.Context, userID int, dropIfNoReplacement bool, match func(pr *domain.PullRequest) bool, fromTeamID int) error {
+	prs, err := s.repo.GetPRsByReviewer(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, pr := range prs {
+		if pr.Status != domain.PRStatusOpen {
+			continue
+		}
+		if match != nil && !match(&pr) {
+			continue
+		}
+
+		_, err := s.rerollReviewer(ctx, pr.ID, userID, fromTeamID)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, domain.ErrNoReviewersFound) {
+			errs = append(errs, fmt.Errorf("PR %d: %w", pr.ID, err))
+			continue
+		}
+		if !dropIfNoReplacement {
+			continue
+		}
+
+		updated, err := s.repo.UpdateOpenPR(ctx, pr.ID, func(pr *domain.PullRequest) error {
+			pr.Reviewers = withoutReviewer(pr.Reviewers, userID)
+			if len(pr.Reviewers) == 0 {
+				// Без ревьюеров PR встает в очередь ожидания, как при создании
+				now := time.Now()
+				pr.WaitingSince = &now
+			}
+			return nil
+		})
+		if errors.Is(err, domain.ErrPRAlreadyMerged) || errors.Is(err, domain.ErrPRClosed) {
+			// PR успели смержить или закрыть - снимать уже нечего
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("PR %d: %w", pr.ID, err))
+			continue
+		}
+		s.recordHistory(ctx, pr.ID, domain.HistoryReviewerRemoved, userID, "no replacement available")
+		s.publishEvent(ctx, domain.EventReviewerUnassigned, pr.ID, prTeamID(updated), userID)
+		if updated.WaitingSince != nil {
+			s.signalQueue()
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // selectRandomReviewers выбирает n случайных уникальных пользователей из слайса
 func selectRandomReviewers(users []domain.User, n int) []domain.User {
 	if len(users) <= n {
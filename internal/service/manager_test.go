@@ -4,12 +4,16 @@ import (
 	"context"
 	"log"
 	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/Shishlyannikovvv/project-avito/internal/domain"
 	"github.com/Shishlyannikovvv/project-avito/internal/service"
 	"github.com/Shishlyannikovvv/project-avito/internal/storage"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 )
 
 // Тестовые константы (должны совпадать с docker-compose)
@@ -22,6 +26,7 @@ const (
 )
 
 var (
+	testDB      *gorm.DB
 	testRepo    domain.Repository
 	testService domain.Service
 )
@@ -33,7 +38,8 @@ func TestMain(m *testing.M) {
 		log.Fatalf("Could not connect to test DB: %v", err)
 	}
 
-	// Используем gorm.DB для очистки таблиц перед каждым тестом
+	// gorm.DB нужен для очистки таблиц перед каждым тестом
+	testDB = db
 	testRepo = storage.NewRepository(db)
 	testService = service.NewManager(testRepo)
 
@@ -44,9 +50,9 @@ func TestMain(m *testing.M) {
 // setupTest очищает таблицы перед каждым тестом
 func setupTest(t *testing.T) {
 	// GORM не предоставляет простой способ очистки Many-to-Many таблиц, поэтому используем raw SQL
-	gdb := testRepo.(*storage.Repository).DB() // Получаем доступ к gorm.DB
-
-	gdb.Exec("TRUNCATE pr_reviewers, pull_requests, users, teams RESTART IDENTITY;")
+	testDB.Exec("TRUNCATE pr_history_entries, pr_reviewers, pull_requests, team_policies, user_unavailabilities, code_owner_rules, notification_templates, team_chat_webhooks, events, team_memberships, users, teams RESTART IDENTITY;")
+	// Организация по умолчанию создается миграцией и нужна запросам без API-ключа
+	testDB.Exec("DELETE FROM organizations WHERE id <> ?", domain.DefaultOrgID)
 }
 
 func TestPRAssignmentAndMerge(t *testing.T) {
@@ -72,10 +78,13 @@ func TestPRAssignmentAndMerge(t *testing.T) {
 	assert.Len(t, pr.Reviewers, 2, "Should assign exactly 2 active reviewers")
 
 	// Проверяем, что автор не назначен
+	var reviewerIDs []int
 	for _, r := range pr.Reviewers {
 		assert.NotEqual(t, userAuthor.ID, r.ID, "Author should not be a reviewer")
 		assert.True(t, r.IsActive, "Reviewer must be active")
+		reviewerIDs = append(reviewerIDs, r.ID)
 	}
+	assert.ElementsMatch(t, []int{userReviewer1.ID, userReviewer2.ID}, reviewerIDs)
 
 	// 4. Мердж PR
 	mergedPR, err := testService.MergePR(ctx, pr.ID)
@@ -83,8 +92,7 @@ func TestPRAssignmentAndMerge(t *testing.T) {
 	assert.Equal(t, domain.PRStatusMerged, mergedPR.Status)
 
 	// 5. Попытка изменить ревьюера после мерджа (должно вернуть ошибку)
-	err = pr.Reviewers[0].ID
-	_, errReroll := testService.RerollReviewer(ctx, mergedPR.ID, err)
+	_, errReroll := testService.RerollReviewer(ctx, mergedPR.ID, pr.Reviewers[0].ID)
 	assert.ErrorIs(t, errReroll, domain.ErrPRAlreadyMerged, "Cannot reroll merged PR")
 
 	// 6. Проверка идемпотентности (повторный мердж не должен вызывать ошибку)
@@ -139,7 +147,465 @@ func TestRerollReviewer(t *testing.T) {
 	assert.Len(t, rerolledPR.Reviewers, 2)
 }
 
+func TestTeamPolicyFallback(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	small, _ := testService.CreateTeam(ctx, "Small Team")
+	backup, _ := testService.CreateTeam(ctx, "Backup Team")
+
+	author, _ := testService.CreateUser(ctx, "Author", small.ID)
+	teammate, _ := testService.CreateUser(ctx, "Teammate", small.ID)
+	backupUser, _ := testService.CreateUser(ctx, "Backup Senior", backup.ID)
+	_, _ = testService.CreateUser(ctx, "Backup Junior", backup.ID)
+	_, err := testService.SetUserSeniority(ctx, backupUser.ID, domain.SenioritySenior)
+	assert.NoError(t, err)
+
+	// Без политики: один ревьюер из своей команды, без фолбэка
+	pr, err := testService.CreatePR(ctx, "Default policy", author.ID)
+	assert.NoError(t, err)
+	assert.Len(t, pr.Reviewers, 1)
+	assert.False(t, pr.Assignment.FellBack)
+
+	// Требуем двух ревьюеров, второго - из запасной команды
+	_, err = testService.UpdateTeamPolicy(ctx, &domain.TeamPolicy{
+		TeamID: small.ID, MinReviewers: 2, MaxReviewers: 2, FallbackTeamIDs: []int{backup.ID},
+	})
+	assert.NoError(t, err)
+
+	pr, err = testService.CreatePR(ctx, "With fallback", author.ID)
+	assert.NoError(t, err)
+	assert.Len(t, pr.Reviewers, 2)
+	assert.True(t, pr.Assignment.FellBack)
+	assert.Equal(t, []int{backup.ID}, pr.Assignment.FallbackTeamIDs)
+	assert.False(t, pr.Assignment.UnderAssigned)
+
+	// Требуем senior: подходит только backupUser, teammate отсеивается
+	_, err = testService.UpdateTeamPolicy(ctx, &domain.TeamPolicy{
+		TeamID: small.ID, MinReviewers: 2, MaxReviewers: 2, FallbackTeamIDs: []int{backup.ID},
+		RequiredSeniority: domain.SenioritySenior,
+	})
+	assert.NoError(t, err)
+
+	pr, err = testService.CreatePR(ctx, "Seniors only", author.ID)
+	assert.NoError(t, err)
+	assert.Len(t, pr.Reviewers, 1)
+	assert.Equal(t, backupUser.ID, pr.Reviewers[0].ID)
+	assert.NotEqual(t, teammate.ID, pr.Reviewers[0].ID)
+	assert.True(t, pr.Assignment.UnderAssigned)
+
+	// Некорректная политика отклоняется
+	_, err = testService.UpdateTeamPolicy(ctx, &domain.TeamPolicy{TeamID: small.ID, MinReviewers: 3, MaxReviewers: 2})
+	assert.ErrorIs(t, err, domain.ErrInvalidPolicy)
+}
+
+func TestUnavailableUserIsSkipped(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	team, _ := testService.CreateTeam(ctx, "Vacationers")
+	author, _ := testService.CreateUser(ctx, "Author", team.ID)
+	onVacation, _ := testService.CreateUser(ctx, "On Vacation", team.ID)
+	present, _ := testService.CreateUser(ctx, "Present", team.ID)
+
+	_, err := testService.AddUnavailability(ctx, &domain.UserUnavailability{
+		UserID:   onVacation.ID,
+		StartsAt: time.Now().Add(-time.Hour),
+		EndsAt:   time.Now().Add(24 * time.Hour),
+		Reason:   "vacation",
+	})
+	assert.NoError(t, err)
+
+	pr, err := testService.CreatePR(ctx, "While someone is away", author.ID)
+	assert.NoError(t, err)
+	assert.Len(t, pr.Reviewers, 1)
+	assert.Equal(t, present.ID, pr.Reviewers[0].ID)
+
+	// Окно с концом раньше начала отклоняется
+	_, err = testService.AddUnavailability(ctx, &domain.UserUnavailability{
+		UserID:   present.ID,
+		StartsAt: time.Now(),
+		EndsAt:   time.Now().Add(-time.Hour),
+	})
+	assert.ErrorIs(t, err, domain.ErrInvalidPeriod)
+}
+
+func TestCapacityQueue(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	team, _ := testService.CreateTeam(ctx, "Busy Team")
+	author, _ := testService.CreateUser(ctx, "Author", team.ID)
+	reviewer, _ := testService.CreateUser(ctx, "Only Reviewer", team.ID)
+
+	_, err := testService.UpdateTeamPolicy(ctx, &domain.TeamPolicy{
+		TeamID: team.ID, MinReviewers: 1, MaxReviewers: 1, SelfTeamOnly: true, DefaultMaxOpenReviews: 1,
+	})
+	assert.NoError(t, err)
+
+	first, err := testService.CreatePR(ctx, "First", author.ID)
+	assert.NoError(t, err)
+	assert.Len(t, first.Reviewers, 1)
+	assert.Nil(t, first.Queue)
+
+	// Единственный ревьюер занят - второй PR встает в очередь
+	second, err := testService.CreatePR(ctx, "Second", author.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, second.Reviewers)
+	assert.NotNil(t, second.Queue)
+	assert.Equal(t, 1, second.Queue.Position)
+
+	// После мерджа первого PR ревьюер освобождается и достается второму
+	_, err = testService.MergePR(ctx, first.ID)
+	assert.NoError(t, err)
+	assigned, err := testService.(*service.Manager).ProcessReviewerQueue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, assigned)
+
+	second, err = testService.GetPR(ctx, second.ID)
+	assert.NoError(t, err)
+	assert.Len(t, second.Reviewers, 1)
+	assert.Equal(t, reviewer.ID, second.Reviewers[0].ID)
+	assert.Nil(t, second.Queue)
+}
+
+func TestCapacityUnderConcurrentPRs(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	policy := func(teamID int) *domain.TeamPolicy {
+		return &domain.TeamPolicy{TeamID: teamID, MinReviewers: 1, MaxReviewers: 1, SelfTeamOnly: true, DefaultMaxOpenReviews: 1}
+	}
+	team, _ := testService.CreateTeam(ctx, "Race Team")
+	other, _ := testService.CreateTeam(ctx, "Other Team")
+	author, _ := testService.CreateUser(ctx, "Author", team.ID)
+	otherAuthor, _ := testService.CreateUser(ctx, "Other Author", other.ID)
+	var ids []int
+	for _, name := range []string{"First", "Second", "Third"} {
+		u, _ := testService.CreateUser(ctx, name, team.ID)
+		ids = append(ids, u.ID)
+	}
+	_, err := testService.UpdateTeamPolicy(ctx, policy(team.ID))
+	assert.NoError(t, err)
+	_, err = testService.UpdateTeamPolicy(ctx, policy(other.ID))
+	assert.NoError(t, err)
+
+	// Очередь другой команды (без ревьюеров) не влияет на положение PR этой команды
+	otherPR, err := testService.CreatePR(ctx, "Other waits", otherAuthor.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, otherPR.Queue.Position)
+
+	// Параллельные PR не могут занять ревьюера сверх лимита
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := testService.CreatePR(ctx, "Concurrent", author.ID)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	counts, err := testRepo.GetOpenReviewCounts(ctx, ids)
+	assert.NoError(t, err)
+	for _, id := range ids {
+		assert.Equal(t, 1, counts[id], "reviewer %d", id)
+	}
+
+	last, err := testService.CreatePR(ctx, "Last", author.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, last.Queue.Position)
+}
+
+func TestRerollRacingMerge(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	team, _ := testService.CreateTeam(ctx, "Merge Race")
+	author, _ := testService.CreateUser(ctx, "Author", team.ID)
+	for _, name := range []string{"First", "Second", "Third", "Fourth", "Fifth"} {
+		testService.CreateUser(ctx, name, team.ID)
+	}
+	pr, err := testService.CreatePR(ctx, "Race", author.ID)
+	assert.NoError(t, err)
+	assert.Len(t, pr.Reviewers, 2)
+
+	// Реролл, начавшийся до мерджа, не может вернуть PR в OPEN или сменить ревьюеров после мерджа
+	var wg sync.WaitGroup
+	var merged *domain.PullRequest
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		merged, err = testService.MergePR(ctx, pr.ID)
+	}()
+	for _, r := range pr.Reviewers {
+		go func(reviewerID int) {
+			defer wg.Done()
+			testService.RerollReviewer(ctx, pr.ID, reviewerID)
+		}(r.ID)
+	}
+	wg.Wait()
+	assert.NoError(t, err)
+
+	stored, err := testRepo.GetPRByID(ctx, pr.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PRStatusMerged, stored.Status)
+	assert.ElementsMatch(t, reviewerIDsOf(merged), reviewerIDsOf(stored))
+	assert.Len(t, stored.Reviewers, 2)
+}
+
+func reviewerIDsOf(pr *domain.PullRequest) []int {
+	ids := make([]int, 0, len(pr.Reviewers))
+	for _, r := range pr.Reviewers {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestCodeOwnersArePreferred(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	team, _ := testService.CreateTeam(ctx, "Owners")
+	author, _ := testService.CreateUser(ctx, "Author", team.ID)
+	owner, _ := testService.CreateUser(ctx, "Storage Owner", team.ID)
+	expert, _ := testService.CreateUser(ctx, "Postgres Expert", team.ID)
+	for i := 0; i < 3; i++ {
+		testService.CreateUser(ctx, "Random", team.ID)
+	}
+
+	_, err := testService.SetUserExpertise(ctx, owner.ID, "@storage-owner", nil)
+	assert.NoError(t, err)
+	_, err = testService.SetUserExpertise(ctx, expert.ID, "", []string{"Postgres"})
+	assert.NoError(t, err)
+
+	report, err := testService.UploadCodeOwners(ctx, team.ID, strings.NewReader("/internal/storage/ @storage-owner @nobody\n"))
+	assert.NoError(t, err)
+	assert.Len(t, report.Rules, 1)
+	assert.Equal(t, []string{"@nobody"}, report.UnresolvedOwners)
+
+	_, err = testService.UpdateTeamPolicy(ctx, &domain.TeamPolicy{TeamID: team.ID, MinReviewers: 1, MaxReviewers: 1, SelfTeamOnly: true})
+	assert.NoError(t, err)
+
+	// Единственное место достается владельцу измененного пути
+	pr, err := testService.CreatePRWithChanges(ctx, "Touch storage", author.ID, []string{"internal/storage/repository.go"}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, pr.Reviewers, 1)
+	assert.Equal(t, owner.ID, pr.Reviewers[0].ID)
+
+	// Без владельцев - эксперт по метке
+	pr, err = testService.CreatePRWithChanges(ctx, "Tune queries", author.ID, []string{"README.md"}, []string{"postgres"})
+	assert.NoError(t, err)
+	assert.Len(t, pr.Reviewers, 1)
+	assert.Equal(t, expert.ID, pr.Reviewers[0].ID)
+}
+
+func TestManualReviewerManagement(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	team, _ := testService.CreateTeam(ctx, "Manual")
+	other, _ := testService.CreateTeam(ctx, "Other")
+	author, _ := testService.CreateUser(ctx, "Author", team.ID)
+	first, _ := testService.CreateUser(ctx, "First", team.ID)
+	second, _ := testService.CreateUser(ctx, "Second", team.ID)
+	outsider, _ := testService.CreateUser(ctx, "Outsider", other.ID)
+
+	pr := &domain.PullRequest{Title: "Manual", Status: domain.PRStatusOpen, AuthorID: author.ID, Reviewers: []domain.User{*first}}
+	testRepo.CreatePR(ctx, pr)
+
+	_, err := testService.AddReviewer(ctx, pr.ID, author.ID)
+	assert.ErrorIs(t, err, domain.ErrReviewerIsAuthor)
+	_, err = testService.AddReviewer(ctx, pr.ID, first.ID)
+	assert.ErrorIs(t, err, domain.ErrAlreadyReviewer)
+	_, err = testService.AddReviewer(ctx, pr.ID, outsider.ID)
+	assert.ErrorIs(t, err, domain.ErrReviewerNotEligible)
+
+	updated, err := testService.AddReviewer(ctx, pr.ID, second.ID)
+	assert.NoError(t, err)
+	assert.Len(t, updated.Reviewers, 2)
+
+	// По умолчанию минимум один ревьюер: первого снять можно, последнего - нет
+	updated, err = testService.RemoveReviewer(ctx, pr.ID, first.ID)
+	assert.NoError(t, err)
+	assert.Len(t, updated.Reviewers, 1)
+	_, err = testService.RemoveReviewer(ctx, pr.ID, second.ID)
+	assert.ErrorIs(t, err, domain.ErrReviewerLimitReached)
+
+	// Явная замена при переназначении
+	updated, err = testService.ReplaceReviewer(ctx, pr.ID, second.ID, first.ID)
+	assert.NoError(t, err)
+	assert.Len(t, updated.Reviewers, 1)
+	assert.Equal(t, first.ID, updated.Reviewers[0].ID)
+}
+
+func TestConcurrentAddReviewerRespectsLimit(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	team, _ := testService.CreateTeam(ctx, "Manual Race")
+	author, _ := testService.CreateUser(ctx, "Author", team.ID)
+	first, _ := testService.CreateUser(ctx, "First", team.ID)
+	var candidates []int
+	for _, name := range []string{"Second", "Third", "Fourth"} {
+		u, _ := testService.CreateUser(ctx, name, team.ID)
+		candidates = append(candidates, u.ID)
+	}
+	pr := &domain.PullRequest{Title: "Manual", Status: domain.PRStatusOpen, AuthorID: author.ID, Reviewers: []domain.User{*first}}
+	testRepo.CreatePR(ctx, pr)
+
+	// Лимит по умолчанию - 2 ревьюера: из параллельных добавлений проходит только одно
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	added := 0
+	for _, id := range candidates {
+		wg.Add(1)
+		go func(userID int) {
+			defer wg.Done()
+			_, err := testService.AddReviewer(ctx, pr.ID, userID)
+			if err == nil {
+				mu.Lock()
+				added++
+				mu.Unlock()
+				return
+			}
+			assert.ErrorIs(t, err, domain.ErrReviewerLimitReached)
+		}(id)
+	}
+	wg.Wait()
+
+	stored, err := testRepo.GetPRByID(ctx, pr.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, added)
+	assert.Len(t, stored.Reviewers, 2)
+}
+
+func TestPRHistory(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	team, _ := testService.CreateTeam(ctx, "Historians")
+	author, _ := testService.CreateUser(ctx, "Author", team.ID)
+	testService.CreateUser(ctx, "Reviewer", team.ID)
+
+	pr, err := testService.CreatePR(ctx, "Tracked", author.ID)
+	assert.NoError(t, err)
+	_, err = testService.MergePR(ctx, pr.ID)
+	assert.NoError(t, err)
+
+	history, err := testService.GetPRHistory(ctx, pr.ID)
+	assert.NoError(t, err)
+	events := make([]string, 0, len(history))
+	for _, h := range history {
+		events = append(events, h.Event)
+	}
+	assert.Equal(t, []string{domain.HistoryCreated, domain.HistoryReviewerAdded, domain.HistoryMerged}, events)
+}
+
 // Тест для проверки массовой деактивации и переназначения
+func TestNotificationSettings(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	team, _ := testService.CreateTeam(ctx, "Notified")
+	user, _ := testService.CreateUser(ctx, "Reader", team.ID)
+
+	_, err := testService.SetUserNotifications(ctx, user.ID, "not an email", nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidEmail)
+	_, err = testService.SetUserNotifications(ctx, user.ID, "reader@example.com", []string{"pr.unknown"})
+	assert.ErrorIs(t, err, domain.ErrInvalidEventType)
+
+	updated, err := testService.SetUserNotifications(ctx, user.ID, "reader@example.com",
+		[]string{domain.EventReviewReminder, domain.EventReviewReminder})
+	assert.NoError(t, err)
+	assert.Equal(t, "reader@example.com", updated.Email)
+	assert.Equal(t, []string{domain.EventReviewReminder}, updated.NotifyOptOut)
+
+	// Шаблон проверяется на синтаксис и на известные поля
+	_, err = testService.SaveNotificationTemplate(ctx, &domain.NotificationTemplate{
+		TeamID: team.ID, EventType: domain.EventReviewerAssigned, Subject: "{{.PR.Nope}}", Body: "body",
+	})
+	assert.ErrorIs(t, err, domain.ErrInvalidTemplate)
+
+	for _, subject := range []string{"First {{.PR.Title}}", "Second {{.PR.Title}}"} {
+		_, err = testService.SaveNotificationTemplate(ctx, &domain.NotificationTemplate{
+			TeamID: team.ID, EventType: domain.EventReviewerAssigned, Subject: subject, Body: "Hi {{.Recipient.Name}}",
+		})
+		assert.NoError(t, err)
+	}
+	templates, err := testService.GetNotificationTemplates(ctx, team.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, templates, 1) {
+		assert.Equal(t, "Second {{.PR.Title}}", templates[0].Subject)
+	}
+
+	assert.NoError(t, testService.DeleteNotificationTemplate(ctx, team.ID, domain.EventReviewerAssigned))
+	assert.ErrorIs(t, testService.DeleteNotificationTemplate(ctx, team.ID, domain.EventReviewerAssigned), domain.ErrTemplateNotFound)
+
+	// Чат: ник без @ и вебхук только http(s)
+	updated, err = testService.SetUserChatHandle(ctx, user.ID, "@reader")
+	assert.NoError(t, err)
+	assert.Equal(t, "reader", updated.ChatHandle)
+
+	_, err = testService.SetTeamChatWebhook(ctx, team.ID, "ftp://chat.example.com/hook")
+	assert.ErrorIs(t, err, domain.ErrInvalidWebhookURL)
+	// Внутренняя сеть недоступна для вебхуков
+	for _, internal := range []string{
+		"http://localhost:8080/hook", "http://127.0.0.1/hook", "http://10.1.2.3/hook", "http://192.168.0.10/hook",
+		"http://169.254.169.254/latest/meta-data", "http://[::1]/hook", "http://[fd00::1]/hook", "http://0.0.0.0/hook",
+	} {
+		_, err = testService.SetTeamChatWebhook(ctx, team.ID, internal)
+		assert.ErrorIs(t, err, domain.ErrInvalidWebhookURL, internal)
+	}
+	_, err = testService.SetTeamChatWebhook(ctx, team.ID, "https://203.0.113.10/hooks/abc")
+	assert.NoError(t, err)
+	assert.NoError(t, testService.DeleteTeamChatWebhook(ctx, team.ID))
+	assert.ErrorIs(t, testService.DeleteTeamChatWebhook(ctx, team.ID), domain.ErrChatWebhookNotFound)
+}
+
+func TestEventLog(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	team, _ := testService.CreateTeam(ctx, "Streamers")
+	other, _ := testService.CreateTeam(ctx, "Others")
+	author, _ := testService.CreateUser(ctx, "Author", team.ID)
+	reviewer, _ := testService.CreateUser(ctx, "Reviewer", team.ID)
+
+	start, err := testService.GetLatestEventID(ctx)
+	assert.NoError(t, err)
+	changed := testService.EventsChanged()
+
+	pr, err := testService.CreatePR(ctx, "Streamed", author.ID)
+	assert.NoError(t, err)
+	select {
+	case <-changed:
+	default:
+		t.Fatal("subscribers were not woken up")
+	}
+	_, err = testService.MergePR(ctx, pr.ID)
+	assert.NoError(t, err)
+
+	// Ревьюер видит и свои события, и события PR в целом
+	events, err := testService.GetEventsAfter(ctx, start, domain.EventFilter{UserID: reviewer.ID}, 100)
+	assert.NoError(t, err)
+	types := make([]string, 0, len(events))
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	assert.Equal(t, []string{domain.EventReviewerAssigned, domain.EventPRAssigned, domain.EventPRMerged}, types)
+
+	// Курсор: после первого события остаются два
+	rest, err := testService.GetEventsAfter(ctx, events[0].ID, domain.EventFilter{TeamID: team.ID}, 100)
+	assert.NoError(t, err)
+	assert.Len(t, rest, 2)
+
+	none, err := testService.GetEventsAfter(ctx, start, domain.EventFilter{TeamID: other.ID}, 100)
+	assert.NoError(t, err)
+	assert.Empty(t, none)
+}
+
 func TestMassDeactivate(t *testing.T) {
 	setupTest(t)
 	ctx := context.Background()
@@ -202,3 +668,430 @@ func TestMassDeactivate(t *testing.T) {
 	assert.Len(t, updatedPR1.Reviewers, 1, "Should result in 1 reviewer (A4 is the only active candidate)")
 	assert.Equal(t, 1, newReviewerActiveCount, "The assigned reviewer must be active")
 }
+
+func TestTeamMemberships(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	teamA, _ := testService.CreateTeam(ctx, "Squad A")
+	teamB, _ := testService.CreateTeam(ctx, "Squad B")
+	author, _ := testService.CreateUser(ctx, "Author", teamA.ID)
+	lead, _ := testService.CreateUser(ctx, "Lead", teamA.ID)
+	shared, _ := testService.CreateUser(ctx, "Shared", teamB.ID)
+	otherB, _ := testService.CreateUser(ctx, "Other B", teamB.ID)
+	_, err := testService.UpdateTeamPolicy(ctx, &domain.TeamPolicy{TeamID: teamA.ID, MinReviewers: 1, MaxReviewers: 1, FallbackTeamIDs: []int{teamB.ID}})
+	assert.NoError(t, err)
+
+	// Lead состоит в команде, но не ревьюит; Shared ревьюит в обеих командах
+	_, err = testService.SetTeamMembership(ctx, &domain.TeamMembership{TeamID: teamA.ID, UserID: lead.ID, CanAuthor: true, CanReview: false})
+	assert.NoError(t, err)
+	_, err = testService.SetTeamMembership(ctx, &domain.TeamMembership{TeamID: teamA.ID, UserID: shared.ID, CanAuthor: false, CanReview: true})
+	assert.NoError(t, err)
+
+	pr, err := testService.CreatePR(ctx, "Multi-team", author.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, pr.Reviewers, 1) {
+		assert.Equal(t, shared.ID, pr.Reviewers[0].ID, "member of the author's team via secondary membership")
+	}
+	assert.False(t, pr.Assignment.FellBack)
+
+	teams, err := testService.GetUserTeams(ctx, shared.ID)
+	assert.NoError(t, err)
+	assert.Len(t, teams, 2)
+
+	// Из основной команды выйти нельзя, основной может стать только своя команда
+	assert.ErrorIs(t, testService.RemoveTeamMember(ctx, teamB.ID, shared.ID), domain.ErrPrimaryTeamMembership)
+	_, err = testService.SetPrimaryTeam(ctx, otherB.ID, teamA.ID)
+	assert.ErrorIs(t, err, domain.ErrMembershipNotFound)
+
+	// Без права создавать PR в основной команде
+	_, err = testService.SetTeamMembership(ctx, &domain.TeamMembership{TeamID: teamA.ID, UserID: author.ID, CanAuthor: false, CanReview: true})
+	assert.NoError(t, err)
+	_, err = testService.CreatePR(ctx, "Not allowed", author.ID)
+	assert.ErrorIs(t, err, domain.ErrAuthorNotAllowed)
+}
+
+func TestRerollUsesReplacedReviewerTeam(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	teamA, _ := testService.CreateTeam(ctx, "Home")
+	teamB, _ := testService.CreateTeam(ctx, "Fallback")
+	author, _ := testService.CreateUser(ctx, "Author", teamA.ID)
+	homeReviewer, _ := testService.CreateUser(ctx, "Home Reviewer", teamA.ID)
+	fallbackReviewer, _ := testService.CreateUser(ctx, "Fallback Reviewer", teamB.ID)
+	fallbackSpare, _ := testService.CreateUser(ctx, "Fallback Spare", teamB.ID)
+	_, err := testService.UpdateTeamPolicy(ctx, &domain.TeamPolicy{TeamID: teamA.ID, MinReviewers: 1, MaxReviewers: 2, FallbackTeamIDs: []int{teamB.ID}})
+	assert.NoError(t, err)
+
+	pr, err := testService.CreatePR(ctx, "Cross-team", author.ID)
+	assert.NoError(t, err)
+	assert.Len(t, pr.Reviewers, 1)
+	assert.Equal(t, homeReviewer.ID, pr.Reviewers[0].ID)
+	_, err = testService.AddReviewer(ctx, pr.ID, fallbackReviewer.ID)
+	assert.NoError(t, err)
+
+	// В команде автора появился свободный кандидат, но замена берется из команды заменяемого
+	testService.CreateUser(ctx, "New Home Member", teamA.ID)
+	rerolled, err := testService.RerollReviewer(ctx, pr.ID, fallbackReviewer.ID)
+	assert.NoError(t, err)
+	ids := []int{rerolled.Reviewers[0].ID, rerolled.Reviewers[1].ID}
+	assert.ElementsMatch(t, []int{homeReviewer.ID, fallbackSpare.ID}, ids)
+
+	// Политика "только своя команда" не подменяет команду заменяемого командой автора
+	_, err = testService.UpdateTeamPolicy(ctx, &domain.TeamPolicy{TeamID: teamA.ID, MinReviewers: 1, MaxReviewers: 2, SelfTeamOnly: true})
+	assert.NoError(t, err)
+	rerolled, err = testService.RerollReviewer(ctx, pr.ID, fallbackSpare.ID)
+	assert.NoError(t, err)
+	ids = []int{rerolled.Reviewers[0].ID, rerolled.Reviewers[1].ID}
+	assert.ElementsMatch(t, []int{homeReviewer.ID, fallbackReviewer.ID}, ids)
+}
+
+func TestTeamHierarchy(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	dept, _ := testService.CreateTeam(ctx, "Department")
+	teamA, err := testService.CreateTeamWithParent(ctx, "Team A", &dept.ID)
+	assert.NoError(t, err)
+	teamB, _ := testService.CreateTeamWithParent(ctx, "Team B", &dept.ID)
+	author, _ := testService.CreateUser(ctx, "Author", teamA.ID)
+	head, _ := testService.CreateUser(ctx, "Head", dept.ID)
+	sibling, _ := testService.CreateUser(ctx, "Sibling", teamB.ID)
+
+	// Отдел нельзя перенести внутрь собственной команды
+	_, err = testService.MoveTeam(ctx, dept.ID, &teamA.ID)
+	assert.ErrorIs(t, err, domain.ErrTeamCycle)
+
+	_, err = testService.UpdateTeamPolicy(ctx, &domain.TeamPolicy{TeamID: teamA.ID, MinReviewers: 1, MaxReviewers: 1, EscalateUpTree: true})
+	assert.NoError(t, err)
+
+	// В своей команде ревьюеров нет - сначала родительская команда
+	pr, err := testService.CreatePR(ctx, "Escalate to parent", author.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, pr.Reviewers, 1) {
+		assert.Equal(t, head.ID, pr.Reviewers[0].ID)
+	}
+	assert.Equal(t, []int{dept.ID}, pr.Assignment.FallbackTeamIDs)
+
+	// Затем соседние команды
+	assert.NoError(t, testService.DeleteUser(ctx, head.ID))
+	pr, err = testService.CreatePR(ctx, "Escalate to sibling", author.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, pr.Reviewers, 1) {
+		assert.Equal(t, sibling.ID, pr.Reviewers[0].ID)
+	}
+	assert.Equal(t, []int{teamB.ID}, pr.Assignment.FallbackTeamIDs)
+
+	members, err := testService.GetSubtreeMembers(ctx, dept.ID)
+	assert.NoError(t, err)
+	assert.Len(t, members, 3)
+
+	stats, err := testService.GetTeamReviewerStats(ctx, teamB.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int{sibling.ID: 1}, stats)
+	stats, err = testService.GetTeamReviewerStats(ctx, dept.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int{head.ID: 1, sibling.ID: 1}, stats)
+
+	// Перенос на верхний уровень
+	moved, err := testService.MoveTeam(ctx, teamB.ID, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, moved.ParentID)
+}
+
+func TestUserLifecycle(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	teamA, _ := testService.CreateTeam(ctx, "Old Team")
+	teamB, _ := testService.CreateTeam(ctx, "New Team")
+	author, _ := testService.CreateUser(ctx, "Author", teamA.ID)
+	mover, _ := testService.CreateUser(ctx, "Mover", teamA.ID)
+	leaver, _ := testService.CreateUser(ctx, "Leaver", teamA.ID)
+
+	pr, err := testService.CreatePR(ctx, "Lifecycle", author.ID)
+	assert.NoError(t, err)
+	assert.Len(t, pr.Reviewers, 2)
+
+	// Реактивация
+	assert.NoError(t, testService.DeleteUser(ctx, mover.ID))
+	activated, err := testService.ActivateUser(ctx, mover.ID)
+	assert.NoError(t, err)
+	assert.True(t, activated.IsActive)
+
+	// Переименование
+	name := "  Mover Renamed "
+	updated, err := testService.UpdateUser(ctx, mover.ID, domain.UserUpdate{Name: &name})
+	assert.NoError(t, err)
+	assert.Equal(t, "Mover Renamed", updated.Name)
+	empty := " "
+	_, err = testService.UpdateUser(ctx, mover.ID, domain.UserUpdate{Name: &empty})
+	assert.ErrorIs(t, err, domain.ErrInvalidUserName)
+
+	// Несуществующая команда - имя тоже не меняется
+	other, missingTeam := "Other Name", 999999
+	_, err = testService.UpdateUser(ctx, mover.ID, domain.UserUpdate{Name: &other, TeamID: &missingTeam})
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+	unchanged, _ := testRepo.GetUserByID(ctx, mover.ID)
+	assert.Equal(t, "Mover Renamed", unchanged.Name)
+
+	// Перевод: ревью для старой команды уходит новому участнику старой команды
+	newcomer, _ := testService.CreateUser(ctx, "Newcomer", teamA.ID)
+	updated, err = testService.UpdateUser(ctx, mover.ID, domain.UserUpdate{TeamID: &teamB.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, teamB.ID, updated.TeamID)
+	teams, _ := testService.GetUserTeams(ctx, mover.ID)
+	if assert.Len(t, teams, 1) {
+		assert.Equal(t, teamB.ID, teams[0].TeamID)
+	}
+	pr, _ = testService.GetPR(ctx, pr.ID)
+	ids := make([]int, 0, len(pr.Reviewers))
+	for _, r := range pr.Reviewers {
+		ids = append(ids, r.ID)
+	}
+	assert.ElementsMatch(t, []int{leaver.ID, newcomer.ID}, ids)
+
+	// Удаление: замены нет, ревьюер снимается, PR ссылается на обезличенную запись
+	assert.NoError(t, testService.HardDeleteUser(ctx, leaver.ID))
+	pr, _ = testService.GetPR(ctx, pr.ID)
+	if assert.Len(t, pr.Reviewers, 1) {
+		assert.Equal(t, newcomer.ID, pr.Reviewers[0].ID)
+	}
+	deleted, err := testRepo.GetUserByID(ctx, leaver.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, deleted.DeletedAt)
+	assert.False(t, deleted.IsActive)
+	assert.NotContains(t, deleted.Name, "Leaver")
+
+	_, err = testService.ActivateUser(ctx, leaver.ID)
+	assert.ErrorIs(t, err, domain.ErrUserDeleted)
+}
+
+func TestArchiveTeam(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	teamA, _ := testService.CreateTeam(ctx, "Sunset")
+	teamB, _ := testService.CreateTeam(ctx, "Keeper")
+	authorA, _ := testService.CreateUser(ctx, "Author A", teamA.ID)
+	testService.CreateUser(ctx, "Reviewer A", teamA.ID)
+	authorB, _ := testService.CreateUser(ctx, "Author B", teamB.ID)
+	reviewerB, _ := testService.CreateUser(ctx, "Reviewer B", teamB.ID)
+	_, err := testService.UpdateTeamPolicy(ctx, &domain.TeamPolicy{TeamID: teamB.ID, MinReviewers: 2, MaxReviewers: 2, FallbackTeamIDs: []int{teamA.ID}})
+	assert.NoError(t, err)
+
+	prA, _ := testService.CreatePR(ctx, "Team A work", authorA.ID)
+	prB, _ := testService.CreatePR(ctx, "Team B work", authorB.ID)
+	assert.Len(t, prB.Reviewers, 2, "one reviewer from the fallback team")
+	teamD, _ := testService.CreateTeam(ctx, "Solo")
+	authorD, _ := testService.CreateUser(ctx, "Author D", teamD.ID)
+	_, err = testService.UpdateTeamPolicy(ctx, &domain.TeamPolicy{TeamID: teamD.ID, MinReviewers: 1, MaxReviewers: 1, FallbackTeamIDs: []int{teamA.ID}})
+	assert.NoError(t, err)
+	prD, _ := testService.CreatePR(ctx, "Team D work", authorD.ID)
+	assert.Len(t, prD.Reviewers, 1, "the only reviewer is from the fallback team")
+
+	_, err = testService.ArchiveTeam(ctx, teamA.ID, "drop")
+	assert.ErrorIs(t, err, domain.ErrInvalidStrategy)
+	archived, err := testService.ArchiveTeam(ctx, teamA.ID, domain.ArchiveClose)
+	assert.NoError(t, err)
+	assert.NotNil(t, archived.ArchivedAt)
+
+	// PR команды закрыт, ревью ее участников в чужих PR сняты (замены в архивной команде нет)
+	_, err = testService.MergePR(ctx, prA.ID)
+	assert.ErrorIs(t, err, domain.ErrPRClosed)
+	prB, _ = testService.GetPR(ctx, prB.ID)
+	if assert.Len(t, prB.Reviewers, 1) {
+		assert.Equal(t, reviewerB.ID, prB.Reviewers[0].ID)
+	}
+	author, _ := testRepo.GetUserByID(ctx, authorA.ID)
+	assert.False(t, author.IsActive)
+	// PR, оставшийся без ревьюеров, снова ждет в очереди
+	prD, _ = testService.GetPR(ctx, prD.ID)
+	assert.Empty(t, prD.Reviewers)
+	assert.NotNil(t, prD.Queue)
+
+	// Архивная команда видна, но в нее нельзя добавлять и из нее не назначают
+	team, err := testService.GetTeam(ctx, teamA.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Sunset", team.Name)
+	_, err = testService.SetTeamMembership(ctx, &domain.TeamMembership{TeamID: teamA.ID, UserID: reviewerB.ID, CanReview: true})
+	assert.ErrorIs(t, err, domain.ErrTeamArchived)
+	_, err = testService.ArchiveTeam(ctx, teamA.ID, domain.ArchiveReassign)
+	assert.ErrorIs(t, err, domain.ErrTeamArchived)
+
+	// Переименование и перевод участников
+	teamC, _ := testService.CreateTeam(ctx, "Newcomers")
+	_, err = testService.RenameTeam(ctx, teamB.ID, "Newcomers")
+	assert.ErrorIs(t, err, domain.ErrTeamAlreadyExists)
+	renamed, err := testService.RenameTeam(ctx, teamB.ID, "Keepers")
+	assert.NoError(t, err)
+	assert.Equal(t, "Keepers", renamed.Name)
+
+	moved, err := testService.MoveTeamMembers(ctx, teamB.ID, teamC.ID, reviewerB.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, moved, 1) {
+		assert.Equal(t, teamC.ID, moved[0].TeamID)
+	}
+	_, err = testService.MoveTeamMembers(ctx, teamB.ID, teamA.ID)
+	assert.ErrorIs(t, err, domain.ErrTeamArchived)
+}
+
+func TestImportDirectory(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	existing, _ := testService.CreateTeam(ctx, "Backend")
+	bob, _ := testService.CreateUser(ctx, "Bob", existing.ID)
+	inactive := false
+	records := []domain.DirectoryRecord{
+		{Team: "Backend", User: "Alice", Login: "@alice", Email: "alice@example.com"},
+		{Team: "Frontend"},
+		{Team: " Frontend ", User: "Bob", IsActive: &inactive},
+		{Team: "Backend", User: "Carol", Email: "not-an-email"},
+	}
+
+	// Ошибка в строке: отчет по всем строкам, но ничего не применяется
+	report, err := testService.ImportDirectory(ctx, records, false)
+	assert.NoError(t, err)
+	assert.False(t, report.Applied)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, domain.ImportFailed, report.Rows[3].Action)
+	assert.Equal(t, 4, report.Rows[3].Row)
+	_, err = testRepo.GetTeamByName(ctx, "Frontend")
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+
+	// Пробный запуск: тот же отчет без изменений
+	records = records[:3]
+	report, err = testService.ImportDirectory(ctx, records, true)
+	assert.NoError(t, err)
+	assert.False(t, report.Applied)
+	assert.Equal(t, 3, report.Created)
+	_, err = testRepo.GetTeamByName(ctx, "Frontend")
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+
+	// Bob ищется по имени в команде: строка с другой командой создает нового пользователя
+	report, err = testService.ImportDirectory(ctx, records, false)
+	assert.NoError(t, err)
+	assert.True(t, report.Applied)
+	assert.Equal(t, 3, report.Created)
+	assert.True(t, report.Rows[1].TeamCreated)
+
+	// Повторный импорт по логину обновляет пользователя и переводит его в другую команду
+	records = []domain.DirectoryRecord{
+		{Team: "Frontend", User: "Alice Smith", Login: "alice"},
+		{Team: "Backend", User: "Bob", IsActive: &inactive},
+	}
+	report, err = testService.ImportDirectory(ctx, records, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Updated)
+	alice, _ := testRepo.GetUserByID(ctx, report.Rows[0].UserID)
+	assert.Equal(t, "Alice Smith", alice.Name)
+	assert.Equal(t, "alice@example.com", alice.Email)
+	assert.Equal(t, report.Rows[0].TeamID, alice.TeamID)
+	assert.Equal(t, bob.ID, report.Rows[1].UserID)
+
+	report, _ = testService.ImportDirectory(ctx, records, false)
+	assert.Equal(t, 2, report.Unchanged)
+
+	// Выгрузка в том же формате
+	exported, err := testService.ExportDirectory(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, exported, 3) {
+		assert.Equal(t, "Backend", exported[0].Team)
+		assert.Equal(t, "Bob", exported[0].User)
+		assert.False(t, *exported[0].IsActive)
+		assert.Equal(t, "alice", exported[1].Login)
+	}
+}
+
+func TestImportReassignsReviews(t *testing.T) {
+	setupTest(t)
+	ctx := context.Background()
+
+	team, _ := testService.CreateTeam(ctx, "Backend")
+	author, _ := testService.CreateUser(ctx, "Author", team.ID)
+	testService.CreateUser(ctx, "R1", team.ID)
+	testService.CreateUser(ctx, "R2", team.ID)
+	testService.CreateUser(ctx, "R3", team.ID)
+	_, err := testService.UpdateTeamPolicy(ctx, &domain.TeamPolicy{TeamID: team.ID, MinReviewers: 1, MaxReviewers: 1, SelfTeamOnly: true})
+	assert.NoError(t, err)
+	pr, err := testService.CreatePR(ctx, "Imported team", author.ID)
+	assert.NoError(t, err)
+	assert.Len(t, pr.Reviewers, 1)
+	first := pr.Reviewers[0]
+
+	// Деактивация импортом снимает ревью, как DeleteUser у команды
+	inactive := false
+	report, err := testService.ImportDirectory(ctx, []domain.DirectoryRecord{{Team: "Backend", User: first.Name, IsActive: &inactive}}, false)
+	assert.NoError(t, err)
+	assert.True(t, report.Applied)
+	assert.True(t, report.Rows[0].Deactivated)
+	pr, _ = testService.GetPR(ctx, pr.ID)
+	if assert.Len(t, pr.Reviewers, 1) {
+		assert.NotEqual(t, first.ID, pr.Reviewers[0].ID)
+	}
+	second := pr.Reviewers[0]
+
+	// Перевод импортом: замена из старой команды
+	_, err = testService.SetUserExpertise(ctx, second.ID, "second", nil)
+	assert.NoError(t, err)
+	report, err = testService.ImportDirectory(ctx, []domain.DirectoryRecord{{Team: "Frontend", User: second.Name, Login: "second"}}, false)
+	assert.NoError(t, err)
+	assert.True(t, report.Applied)
+	assert.Equal(t, team.ID, report.Rows[0].PreviousTeamID)
+	pr, _ = testService.GetPR(ctx, pr.ID)
+	if assert.Len(t, pr.Reviewers, 1) {
+		assert.NotEqual(t, first.ID, pr.Reviewers[0].ID)
+		assert.NotEqual(t, second.ID, pr.Reviewers[0].ID)
+		assert.Equal(t, team.ID, pr.Reviewers[0].TeamID)
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	setupTest(t)
+	ctx := domain.WithAllOrgs(context.Background())
+
+	acme, apiKey, err := testService.CreateOrganization(ctx, "Acme")
+	assert.NoError(t, err)
+	acmeCtx := domain.WithOrgID(context.Background(), acme.ID)
+	team, _ := testService.CreateTeam(acmeCtx, "Rockets")
+	author, _ := testService.CreateUser(acmeCtx, "Wile", team.ID)
+	testService.CreateUser(acmeCtx, "Coyote", team.ID)
+	pr, err := testService.CreatePR(acmeCtx, "Faster rockets", author.ID)
+	assert.NoError(t, err)
+
+	snap, err := testService.ExportSnapshot(ctx, false)
+	assert.NoError(t, err)
+	assert.Len(t, snap.Organizations, 2)
+	assert.Len(t, snap.Reviewers, 1)
+	_, err = testService.ExportSnapshot(ctx, true)
+	assert.ErrorIs(t, err, domain.ErrAnonymizationKeyMissing)
+
+	// В непустую базу снимок не восстанавливается
+	assert.ErrorIs(t, testService.ImportSnapshot(ctx, snap), domain.ErrSnapshotNotEmpty)
+
+	setupTest(t)
+	assert.NoError(t, testService.ImportSnapshot(ctx, snap))
+
+	// ID и принадлежность организации сохранены, API-ключ продолжает работать
+	restored, err := testService.GetPR(acmeCtx, pr.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Faster rockets", restored.Title)
+	assert.Len(t, restored.Reviewers, 1)
+	history, _ := testService.GetPRHistory(acmeCtx, pr.ID)
+	assert.NotEmpty(t, history)
+	_, err = testService.GetPR(context.Background(), pr.ID)
+	assert.ErrorIs(t, err, domain.ErrPRNotFound)
+	org, err := testService.AuthenticateOrg(ctx, apiKey)
+	assert.NoError(t, err)
+	assert.Equal(t, acme.ID, org.ID)
+
+	// Последовательности сдвинуты: новые записи не конфликтуют с восстановленными
+	newTeam, err := testService.CreateTeam(acmeCtx, "Anvils")
+	assert.NoError(t, err)
+	assert.Greater(t, newTeam.ID, team.ID)
+	org, _, err = testService.CreateOrganization(ctx, "Road Runner Inc")
+	assert.NoError(t, err)
+	assert.Greater(t, org.ID, acme.ID)
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/Shishlyannikovvv/project-avito/pkg/codeowners"
+)
+
+// --- Expertise & Code Owners Logic ---
+
+func (s *Manager) SetUserExpertise(ctx context.Context, userID int, login string, tags []string) (*domain.User, error) {
+	login = strings.TrimPrefix(strings.TrimSpace(login), "@")
+	if err := s.repo.SetUserExpertise(ctx, userID, login, normalizeTags(tags)); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserByID(ctx, userID)
+}
+
+func (s *Manager) GetCodeOwners(ctx context.Context, teamID int) ([]domain.CodeOwnerRule, error) {
+	if _, err := s.repo.GetTeamByID(ctx, teamID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetCodeOwnerRules(ctx, teamID)
+}
+
+// UploadCodeOwners заменяет правила команды содержимым файла CODEOWNERS
+func (s *Manager) UploadCodeOwners(ctx context.Context, teamID int, content io.Reader) (*domain.CodeOwnersReport, error) {
+	if _, err := s.repo.GetTeamByID(ctx, teamID); err != nil {
+		return nil, err
+	}
+
+	parsed, err := codeowners.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidCodeOwners, err)
+	}
+
+	rules := make([]domain.CodeOwnerRule, 0, len(parsed))
+	owners := make(map[string]bool)
+	for i, p := range parsed {
+		rules = append(rules, domain.CodeOwnerRule{
+			TeamID:   teamID,
+			Position: i,
+			Pattern:  p.Pattern,
+			Owners:   p.Owners,
+		})
+		for _, o := range p.Owners {
+			owners[o] = true
+		}
+	}
+
+	if err := s.repo.ReplaceCodeOwnerRules(ctx, teamID, rules); err != nil {
+		return nil, err
+	}
+
+	report := &domain.CodeOwnersReport{Rules: rules, UnresolvedOwners: []string{}}
+	for o := range owners {
+		ids, err := s.resolveOwner(ctx, o)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			report.UnresolvedOwners = append(report.UnresolvedOwners, o)
+		}
+	}
+	return report, nil
+}
+
+// --- Helpers ---
+
+// reviewPreference - кого предпочесть при выборе ревьюеров: владельцев измененных путей и экспертов по меткам
+type reviewPreference struct {
+	owners map[int]bool
+	labels map[string]bool
+}
+
+// buildPreference вычисляет предпочтения для PR команды teamID. Без файлов и меток возвращает nil.
+func (s *Manager) buildPreference(ctx context.Context, teamID int, files []string, labels []string) (*reviewPreference, error) {
+	if len(files) == 0 && len(labels) == 0 {
+		return nil, nil
+	}
+
+	pref := &reviewPreference{owners: make(map[int]bool), labels: make(map[string]bool)}
+	for _, l := range normalizeTags(labels) {
+		pref.labels[l] = true
+	}
+
+	if len(files) == 0 {
+		return pref, nil
+	}
+
+	stored, err := s.repo.GetCodeOwnerRules(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]codeowners.Rule, 0, len(stored))
+	for _, r := range stored {
+		rule, err := codeowners.NewRule(r.Pattern, r.Owners)
+		if err != nil {
+			// Правила проверяются при загрузке, так что сюда попадаем только при ручной правке БД
+			continue
+		}
+		rules = append(rules, rule)
+	}
+
+	seen := make(map[string]bool)
+	for _, f := range files {
+		for _, o := range codeowners.OwnersOf(rules, f) {
+			if seen[o] {
+				continue
+			}
+			seen[o] = true
+
+			ids, err := s.resolveOwner(ctx, o)
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range ids {
+				pref.owners[id] = true
+			}
+		}
+	}
+	return pref, nil
+}
+
+// resolveOwner сопоставляет владельца из CODEOWNERS с пользователями:
+// @login - пользователь с таким логином, @org/team-name - все участники команды. Email не поддерживается.
+func (s *Manager) resolveOwner(ctx context.Context, owner string) ([]int, error) {
+	if !strings.HasPrefix(owner, "@") {
+		return nil, nil
+	}
+	name := strings.TrimPrefix(owner, "@")
+
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		team, err := s.repo.GetTeamByName(ctx, name[i+1:])
+		if errors.Is(err, domain.ErrTeamNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		members, err := s.repo.GetUsersByTeam(ctx, team.ID)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]int, 0, len(members))
+		for _, m := range members {
+			ids = append(ids, m.ID)
+		}
+		return ids, nil
+	}
+
+	users, err := s.repo.GetUsersByLogins(ctx, []string{name})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids, nil
+}
+
+// isExpert - есть ли у пользователя тег экспертизы, совпадающий с меткой PR
+func (p *reviewPreference) isExpert(u *domain.User) bool {
+	for _, t := range u.ExpertiseTags {
+		if p.labels[strings.ToLower(t)] {
+			return true
+		}
+	}
+	return false
+}
+
+// selectPreferred выбирает n ревьюеров: сначала владельцы кода, затем эксперты.
+// Если мест больше одного, одно оставляется случайному "непрофильному" кандидату для распространения знаний.
+func selectPreferred(users []domain.User, pref *reviewPreference, n int) []domain.User {
+	if pref == nil {
+		return selectRandomReviewers(users, n)
+	}
+
+	var owners, experts, others []domain.User
+	for _, u := range users {
+		switch {
+		case pref.owners[u.ID]:
+			owners = append(owners, u)
+		case pref.isExpert(&u):
+			experts = append(experts, u)
+		default:
+			others = append(others, u)
+		}
+	}
+	for _, group := range [][]domain.User{owners, experts, others} {
+		rand.Shuffle(len(group), func(i, j int) {
+			group[i], group[j] = group[j], group[i]
+		})
+	}
+	preferred := append(owners, experts...)
+
+	preferredSlots := n
+	if n > 1 && len(others) > 0 {
+		preferredSlots = n - 1
+	}
+	if preferredSlots > len(preferred) {
+		preferredSlots = len(preferred)
+	}
+
+	picked := append([]domain.User{}, preferred[:preferredSlots]...)
+	rest := append(others, preferred[preferredSlots:]...)
+	for _, u := range rest {
+		if len(picked) >= n {
+			break
+		}
+		picked = append(picked, u)
+	}
+	return picked
+}
+
+// normalizeTags приводит теги к нижнему регистру и убирает пустые и повторяющиеся
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		result = append(result, t)
+	}
+	return result
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// --- Team Membership Logic ---
+
+func (s *Manager) GetTeamMembers(ctx context.Context, teamID int) ([]domain.TeamMembership, error) {
+	if _, err := s.repo.GetTeamByID(ctx, teamID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetMembersOfTeams(ctx, []int{teamID})
+}
+
+func (s *Manager) GetUserTeams(ctx context.Context, userID int) ([]domain.TeamMembership, error) {
+	if _, err := s.repo.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserMemberships(ctx, userID)
+}
+
+// SetTeamMembership добавляет пользователя в команду или меняет флаги его участия
+func (s *Manager) SetTeamMembership(ctx context.Context, membership *domain.TeamMembership) (*domain.TeamMembership, error) {
+	if _, err := s.activeTeam(ctx, membership.TeamID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveTeamMembership(ctx, membership); err != nil {
+		return nil, err
+	}
+	// Участник мог стать кандидатом в ревьюеры для PR из очереди
+	if membership.CanReview {
+		s.signalQueue()
+	}
+	return membership, nil
+}
+
+// RemoveTeamMember исключает пользователя из команды. Из основной команды выйти нельзя.
+// Назначенные ревью остаются за пользователем.
+func (s *Manager) RemoveTeamMember(ctx context.Context, teamID int, userID int) error {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.TeamID == teamID {
+		return domain.ErrPrimaryTeamMembership
+	}
+	return s.repo.DeleteTeamMembership(ctx, teamID, userID)
+}
+
+// SetPrimaryTeam делает основной одну из команд пользователя
+func (s *Manager) SetPrimaryTeam(ctx context.Context, userID int, teamID int) (*domain.User, error) {
+	if err := s.repo.SetUserPrimaryTeam(ctx, userID, teamID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserByID(ctx, userID)
+}
+
+// --- Helpers ---
+
+// membershipIn возвращает участие пользователя в команде teamID (nil - не состоит)
+func membershipIn(memberships []domain.TeamMembership, teamID int) *domain.TeamMembership {
+	for i := range memberships {
+		if memberships[i].TeamID == teamID {
+			return &memberships[i]
+		}
+	}
+	return nil
+}
+
+// reviewerTeam - команда заменяемого ревьюера, в которой он ревьюит: сначала из допустимых команд
+// (обычно откуда он и был выбран), затем основная, затем любая другая. Политика не подменяет
+// команду ревьюера командой автора. Если ревьюить ему уже негде - команда автора.
+func (s *Manager) reviewerTeam(ctx context.Context, pr *domain.PullRequest, policy *domain.TeamPolicy, reviewerID int) (int, error) {
+	memberships, err := s.repo.GetUserMemberships(ctx, reviewerID)
+	if err != nil {
+		return 0, err
+	}
+	teams, err := s.candidateTeams(ctx, pr.Author.TeamID, policy)
+	if err != nil {
+		return 0, err
+	}
+	for _, r := range pr.Reviewers {
+		if r.ID == reviewerID && r.TeamID != 0 {
+			teams = append(teams, r.TeamID)
+		}
+	}
+	for _, m := range memberships {
+		teams = append(teams, m.TeamID)
+	}
+	for _, teamID := range teams {
+		if m := membershipIn(memberships, teamID); m != nil && m.CanReview {
+			return teamID, nil
+		}
+	}
+	return pr.Author.TeamID, nil
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/Shishlyannikovvv/project-avito/internal/notify"
+)
+
+// --- Notification Settings ---
+
+// SetUserNotifications задает адрес для писем (пустой - не писать) и события, от которых пользователь отписался
+func (s *Manager) SetUserNotifications(ctx context.Context, userID int, email string, optOut []string) (*domain.User, error) {
+	email = strings.TrimSpace(email)
+	if email != "" {
+		addr, err := mail.ParseAddress(email)
+		if err != nil || addr.Address != email {
+			return nil, domain.ErrInvalidEmail
+		}
+	}
+
+	events := make([]string, 0, len(optOut))
+	seen := make(map[string]bool)
+	for _, e := range optOut {
+		if !domain.IsNotificationEvent(e) {
+			return nil, fmt.Errorf("%w: %s", domain.ErrInvalidEventType, e)
+		}
+		if !seen[e] {
+			seen[e] = true
+			events = append(events, e)
+		}
+	}
+
+	if err := s.repo.SetUserNotifications(ctx, userID, email, events); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserByID(ctx, userID)
+}
+
+// SetUserChatHandle задает ник пользователя в чате для упоминаний (пустой - упоминать по имени)
+func (s *Manager) SetUserChatHandle(ctx context.Context, userID int, handle string) (*domain.User, error) {
+	handle = strings.TrimPrefix(strings.TrimSpace(handle), "@")
+	if err := s.repo.SetUserChatHandle(ctx, userID, handle); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserByID(ctx, userID)
+}
+
+// SetTeamChatWebhook подключает входящий вебхук чата команды. Вебхук во внутреннюю сеть
+// (loopback, частные и link-local адреса, включая адрес метаданных облака) не принимается.
+func (s *Manager) SetTeamChatWebhook(ctx context.Context, teamID int, rawURL string) (*domain.TeamChatWebhook, error) {
+	if _, err := s.repo.GetTeamByID(ctx, teamID); err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, domain.ErrInvalidWebhookURL
+	}
+	if err := checkPublicHost(ctx, u.Hostname()); err != nil {
+		return nil, err
+	}
+
+	webhook := &domain.TeamChatWebhook{TeamID: teamID, URL: u.String()}
+	if err := s.repo.SaveTeamChatWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (s *Manager) DeleteTeamChatWebhook(ctx context.Context, teamID int) error {
+	if _, err := s.repo.GetTeamByID(ctx, teamID); err != nil {
+		return err
+	}
+	return s.repo.DeleteTeamChatWebhook(ctx, teamID)
+}
+
+func (s *Manager) GetNotificationTemplates(ctx context.Context, teamID int) ([]domain.NotificationTemplate, error) {
+	if _, err := s.repo.GetTeamByID(ctx, teamID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetNotificationTemplates(ctx, teamID)
+}
+
+// SaveNotificationTemplate создает или заменяет шаблон команды для типа события
+func (s *Manager) SaveNotificationTemplate(ctx context.Context, tpl *domain.NotificationTemplate) (*domain.NotificationTemplate, error) {
+	if _, err := s.repo.GetTeamByID(ctx, tpl.TeamID); err != nil {
+		return nil, err
+	}
+	if !domain.IsNotificationEvent(tpl.EventType) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrInvalidEventType, tpl.EventType)
+	}
+	if strings.TrimSpace(tpl.Subject) == "" || strings.TrimSpace(tpl.Body) == "" {
+		return nil, fmt.Errorf("%w: subject and body are required", domain.ErrInvalidTemplate)
+	}
+	if err := notify.ValidateTemplate(tpl.Subject, tpl.Body); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidTemplate, err)
+	}
+
+	tpl.ID = 0
+	if err := s.repo.SaveNotificationTemplate(ctx, tpl); err != nil {
+		return nil, err
+	}
+	return s.repo.GetNotificationTemplate(ctx, tpl.TeamID, tpl.EventType)
+}
+
+// Диапазоны, которые netip не считает частными, но которые тоже не адресуют публичный интернет
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "эта сеть"
+	netip.MustParsePrefix("100.64.0.0/10"), // CGNAT (RFC 6598)
+	netip.MustParsePrefix("198.18.0.0/15"), // стенды (RFC 2544)
+}
+
+// checkPublicHost проверяет, что все адреса host публичные: хост, который не резолвится,
+// тоже отклоняется
+func checkPublicHost(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: cannot resolve host %s", domain.ErrInvalidWebhookURL, host)
+	}
+	for _, addr := range addrs {
+		addr = addr.Unmap()
+		public := addr.IsGlobalUnicast() && !addr.IsPrivate()
+		for _, p := range nonPublicPrefixes {
+			public = public && !p.Contains(addr)
+		}
+		if !public {
+			return fmt.Errorf("%w: host %s is not a public address", domain.ErrInvalidWebhookURL, host)
+		}
+	}
+	return nil
+}
+
+// DeleteNotificationTemplate возвращает команде стандартный текст уведомления
+func (s *Manager) DeleteNotificationTemplate(ctx context.Context, teamID int, eventType string) error {
+	if _, err := s.repo.GetTeamByID(ctx, teamID); err != nil {
+		return err
+	}
+	return s.repo.DeleteNotificationTemplate(ctx, teamID, eventType)
+}
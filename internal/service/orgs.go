@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// --- Organizations ---
+
+// Префикс API-ключа, чтобы его было легко узнать в конфигурации и логах
+const apiKeyPrefix = "rk_"
+
+// CreateOrganization создает организацию и возвращает ее API-ключ. В базе хранится только хеш ключа.
+func (s *Manager) CreateOrganization(ctx context.Context, name string) (*domain.Organization, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", domain.ErrInvalidOrgName
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	apiKey := apiKeyPrefix + hex.EncodeToString(secret)
+
+	org := &domain.Organization{Name: name, APIKeyHash: hashAPIKey(apiKey)}
+	if err := s.repo.CreateOrganization(ctx, org); err != nil {
+		return nil, "", err
+	}
+	return org, apiKey, nil
+}
+
+// AuthenticateOrg находит организацию по API-ключу
+func (s *Manager) AuthenticateOrg(ctx context.Context, apiKey string) (*domain.Organization, error) {
+	if !strings.HasPrefix(apiKey, apiKeyPrefix) {
+		return nil, domain.ErrOrganizationNotFound
+	}
+	return s.repo.GetOrganizationByAPIKeyHash(ctx, hashAPIKey(apiKey))
+}
+
+func hashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
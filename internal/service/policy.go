@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// --- Team Policy Logic ---
+
+func (s *Manager) GetTeamPolicy(ctx context.Context, teamID int) (*domain.TeamPolicy, error) {
+	if _, err := s.repo.GetTeamByID(ctx, teamID); err != nil {
+		return nil, err
+	}
+	return s.teamPolicy(ctx, teamID)
+}
+
+func (s *Manager) UpdateTeamPolicy(ctx context.Context, policy *domain.TeamPolicy) (*domain.TeamPolicy, error) {
+	if _, err := s.repo.GetTeamByID(ctx, policy.TeamID); err != nil {
+		return nil, err
+	}
+
+	if policy.MinReviewers < 0 || policy.MaxReviewers < 1 || policy.MinReviewers > policy.MaxReviewers {
+		return nil, domain.ErrInvalidPolicy
+	}
+	if policy.DefaultMaxOpenReviews < 0 {
+		return nil, domain.ErrInvalidCapacity
+	}
+	// Эскалация должна наступать позже напоминания
+	if policy.RemindAfterHours < 0 || policy.EscalateAfterHours < 0 ||
+		(policy.RemindAfterHours > 0 && policy.EscalateAfterHours > 0 && policy.EscalateAfterHours <= policy.RemindAfterHours) {
+		return nil, domain.ErrInvalidPolicy
+	}
+	if policy.RequiredSeniority != "" && domain.SeniorityRank(policy.RequiredSeniority) == 0 {
+		return nil, domain.ErrInvalidSeniority
+	}
+
+	// Запасные команды должны существовать и не совпадать с самой командой
+	seen := make(map[int]bool)
+	for _, id := range policy.FallbackTeamIDs {
+		if id == policy.TeamID || seen[id] {
+			return nil, domain.ErrInvalidPolicy
+		}
+		seen[id] = true
+		if _, err := s.repo.GetTeamByID(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.SaveTeamPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// --- Helpers ---
+
+// teamPolicy возвращает политику команды, а если она не задана - политику по умолчанию
+func (s *Manager) teamPolicy(ctx context.Context, teamID int) (*domain.TeamPolicy, error) {
+	policy, err := s.repo.GetTeamPolicy(ctx, teamID)
+	if errors.Is(err, domain.ErrTeamPolicyNotFound) {
+		return domain.DefaultTeamPolicy(teamID), nil
+	}
+	return policy, err
+}
+
+// eligibleFromTeam возвращает активных, доступных и не перегруженных участников команды,
+// подходящих по политике и не попавших в exclude
+func (s *Manager) eligibleFromTeam(ctx context.Context, teamID int, policy *domain.TeamPolicy, exclude map[int]bool) ([]domain.User, error) {
+	users, err := s.repo.GetTeamReviewers(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	return s.filterEligible(ctx, teamID, users, policy, exclude)
+}
+
+// filterEligible оставляет из users (участников команды teamID) тех, кто может ревьюить по правилам policy
+func (s *Manager) filterEligible(ctx context.Context, teamID int, users []domain.User, policy *domain.TeamPolicy, exclude map[int]bool) ([]domain.User, error) {
+	// Отсутствующие (отпуск и т.п.) в момент назначения не подходят
+	ids := make([]int, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	unavailable, err := s.repo.GetUnavailableUserIDs(ctx, ids, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Лимит открытых ревью по умолчанию берется из политики команды самого кандидата
+	teamPolicy := policy
+	if teamID != policy.TeamID {
+		if teamPolicy, err = s.teamPolicy(ctx, teamID); err != nil {
+			return nil, err
+		}
+	}
+	openReviews, err := s.repo.GetOpenReviewCounts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	minRank := domain.SeniorityRank(policy.RequiredSeniority)
+	eligible := make([]domain.User, 0, len(users))
+	for _, u := range users {
+		if !u.IsActive || exclude[u.ID] || unavailable[u.ID] {
+			continue
+		}
+		if limit := reviewCapacity(&u, teamPolicy); limit > 0 && openReviews[u.ID] >= limit {
+			continue
+		}
+		if minRank > 0 && domain.SeniorityRank(u.Seniority) < minRank {
+			continue
+		}
+		eligible = append(eligible, u)
+	}
+	return eligible, nil
+}
+
+// pickReviewers выбирает до maxCount ревьюеров из команды teamID (с учетом предпочтений pref, если они есть).
+// Если своих не хватает до minCount, добираем из запасных команд (см. candidateTeams, по порядку).
+// exclude дополняется выбранными пользователями.
+func (s *Manager) pickReviewers(ctx context.Context, teamID int, policy *domain.TeamPolicy, pref *reviewPreference, exclude map[int]bool, minCount, maxCount int) ([]domain.User, *domain.AssignmentReport, error) {
+	report := &domain.AssignmentReport{Requested: maxCount}
+
+	own, err := s.eligibleFromTeam(ctx, teamID, policy, exclude)
+	if err != nil {
+		return nil, nil, err
+	}
+	picked := append([]domain.User{}, selectPreferred(own, pref, maxCount)...)
+	for _, u := range picked {
+		exclude[u.ID] = true
+	}
+
+	if len(picked) < minCount {
+		teams, err := s.candidateTeams(ctx, teamID, policy)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, fallbackID := range teams[1:] {
+			if len(picked) >= minCount {
+				break
+			}
+
+			candidates, err := s.eligibleFromTeam(ctx, fallbackID, policy, exclude)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			extra := selectPreferred(candidates, pref, minCount-len(picked))
+			if len(extra) == 0 {
+				continue
+			}
+			for _, u := range extra {
+				exclude[u.ID] = true
+			}
+			picked = append(picked, extra...)
+			report.FellBack = true
+			report.FallbackTeamIDs = append(report.FallbackTeamIDs, fallbackID)
+		}
+	}
+
+	report.Assigned = len(picked)
+	report.UnderAssigned = len(picked) < minCount
+	return picked, report, nil
+}
+
+// candidateTeams - команды, из которых по политике берутся ревьюеры PR команды teamID, по порядку:
+// сама команда, при EscalateUpTree - родительская и соседние, затем FallbackTeamIDs (кроме архивных)
+func (s *Manager) candidateTeams(ctx context.Context, teamID int, policy *domain.TeamPolicy) ([]int, error) {
+	teams := []int{teamID}
+	if policy.SelfTeamOnly {
+		return teams, nil
+	}
+
+	fallbacks := policy.FallbackTeamIDs
+	if policy.EscalateUpTree {
+		tree, err := s.treeFallbackTeams(ctx, teamID)
+		if err != nil {
+			return nil, err
+		}
+		fallbacks = append(tree, fallbacks...)
+	}
+
+	if len(fallbacks) == 0 {
+		return teams, nil
+	}
+	// Архивные команды пропускаем
+	known, err := s.repo.GetTeamsByIDs(ctx, fallbacks)
+	if err != nil {
+		return nil, err
+	}
+	archived := make(map[int]bool)
+	for _, t := range known {
+		if t.ArchivedAt != nil {
+			archived[t.ID] = true
+		}
+	}
+
+	seen := map[int]bool{teamID: true}
+	for _, id := range fallbacks {
+		if !seen[id] && !archived[id] {
+			seen[id] = true
+			teams = append(teams, id)
+		}
+	}
+	return teams, nil
+}
+
+// lockCandidates блокирует назначение всех возможных ревьюеров из candidateTeams(teamID, policy).
+// Лимит открытых ревью проверяется чтением перед записью назначения, поэтому подбор и запись
+// выполняются под блокировкой: параллельные назначения тех же людей ждут, пока она не будет снята.
+func (s *Manager) lockCandidates(ctx context.Context, teamID int, policy *domain.TeamPolicy) (func(), error) {
+	teams, err := s.candidateTeams(ctx, teamID, policy)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	for _, id := range teams {
+		users, err := s.repo.GetTeamReviewers(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			ids = append(ids, u.ID)
+		}
+	}
+	return s.repo.LockReviewers(ctx, ids)
+}
+
+// reviewCapacity возвращает лимит открытых ревью пользователя (0 - без лимита)
+func reviewCapacity(u *domain.User, teamPolicy *domain.TeamPolicy) int {
+	if u.MaxOpenReviews != nil {
+		return *u.MaxOpenReviews
+	}
+	return teamPolicy.DefaultMaxOpenReviews
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// --- Reviewer Queue Logic ---
+
+// ProcessReviewerQueue пытается назначить ревьюеров PR из очереди ожидания (в порядке постановки).
+// Возвращает количество PR, покинувших очередь. Ошибка на одном PR не прерывает обработку остальных;
+// ошибки возвращаются вместе.
+func (s *Manager) ProcessReviewerQueue(ctx context.Context) (int, error) {
+	waiting, err := s.repo.GetWaitingPRs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	assigned := 0
+	var errs []error
+	for i := range waiting {
+		pr := &waiting[i]
+		// Очередь может обходиться по всем организациям; PR обрабатывается в своей
+		ok, err := s.processWaitingPR(domain.WithOrgID(ctx, pr.OrgID), pr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("PR %d: %w", pr.ID, err))
+			continue
+		}
+		if ok {
+			assigned++
+		}
+	}
+	return assigned, errors.Join(errs...)
+}
+
+// RunReviewerQueue разбирает очередь при освобождении ревьюеров (мердж PR) и раз в interval, до отмены ctx
+func (s *Manager) RunReviewerQueue(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.queueSignal:
+		case <-ticker.C:
+		}
+
+		if _, err := s.ProcessReviewerQueue(ctx); err != nil {
+			log.Printf("Reviewer queue error: %v", err)
+		}
+	}
+}
+
+// --- Helpers ---
+
+// processWaitingPR пытается назначить ревьюеров PR из очереди; false - свободных все еще нет
+func (s *Manager) processWaitingPR(ctx context.Context, pr *domain.PullRequest) (bool, error) {
+	if pr.Author == nil {
+		author, err := s.repo.GetUserByID(ctx, pr.AuthorID)
+		if err != nil {
+			return false, err
+		}
+		pr.Author = author
+	}
+
+	policy, err := s.teamPolicy(ctx, pr.Author.TeamID)
+	if err != nil {
+		return false, err
+	}
+
+	pref, err := s.buildPreference(ctx, pr.Author.TeamID, pr.ChangedFiles, pr.Labels)
+	if err != nil {
+		return false, err
+	}
+
+	reviewers, err := s.assignFromQueue(ctx, pr, policy, pref)
+	if err != nil || len(reviewers) == 0 {
+		// Свободных все еще нет - PR остается на своем месте в очереди
+		return false, err
+	}
+	for _, r := range reviewers {
+		s.recordHistory(ctx, pr.ID, domain.HistoryReviewerAdded, r.ID, "assigned from waiting queue")
+		s.publishEvent(ctx, domain.EventReviewerAssigned, pr.ID, pr.Author.TeamID, r.ID)
+	}
+	s.publishEvent(ctx, domain.EventPRAssigned, pr.ID, pr.Author.TeamID, 0)
+	return true, nil
+}
+
+// assignFromQueue подбирает ревьюеров PR из очереди и сохраняет назначение под блокировкой кандидатов
+func (s *Manager) assignFromQueue(ctx context.Context, pr *domain.PullRequest, policy *domain.TeamPolicy, pref *reviewPreference) ([]domain.User, error) {
+	unlock, err := s.lockCandidates(ctx, pr.Author.TeamID, policy)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// PR перечитывается под блокировкой: пока он ждал, ревьюера могли назначить вручную
+	var reviewers []domain.User
+	_, err = s.repo.UpdateOpenPR(ctx, pr.ID, func(pr *domain.PullRequest) error {
+		if pr.WaitingSince == nil {
+			return nil
+		}
+		exclude := map[int]bool{pr.AuthorID: true}
+		for _, r := range pr.Reviewers {
+			exclude[r.ID] = true
+		}
+		var err error
+		reviewers, _, err = s.pickReviewers(ctx, pr.Author.TeamID, policy, pref, exclude, policy.MinReviewers, policy.MaxReviewers)
+		if err != nil || len(reviewers) == 0 {
+			return err
+		}
+		pr.Reviewers = append(pr.Reviewers, reviewers...)
+		pr.WaitingSince = nil
+		return nil
+	})
+	if errors.Is(err, domain.ErrPRAlreadyMerged) || errors.Is(err, domain.ErrPRClosed) {
+		// Из очереди PR ушел сам
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return reviewers, nil
+}
+
+// signalQueue будит обработчик очереди, не блокируясь, если сигнал уже ожидает обработки
+func (s *Manager) signalQueue() {
+	select {
+	case s.queueSignal <- struct{}{}:
+	default:
+	}
+}
+
+// fillQueueInfo заполняет положение PR в очереди ожидания ревьюера
+func (s *Manager) fillQueueInfo(ctx context.Context, pr *domain.PullRequest) error {
+	if pr.WaitingSince == nil || pr.Status != domain.PRStatusOpen {
+		pr.Queue = nil
+		return nil
+	}
+
+	// Впереди считаются только PR, претендующие на тех же ревьюеров: авторы из команд-кандидатов этого PR
+	author := pr.Author
+	if author == nil {
+		var err error
+		if author, err = s.repo.GetUserByID(ctx, pr.AuthorID); err != nil {
+			return err
+		}
+	}
+	policy, err := s.teamPolicy(ctx, author.TeamID)
+	if err != nil {
+		return err
+	}
+	teams, err := s.candidateTeams(ctx, author.TeamID, policy)
+	if err != nil {
+		return err
+	}
+	ahead, err := s.repo.CountWaitingPRsAhead(ctx, pr, teams)
+	if err != nil {
+		return err
+	}
+	pr.Queue = &domain.QueueInfo{
+		Position:   ahead + 1,
+		AgeSeconds: s.now().Sub(*pr.WaitingSince).Seconds(),
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// --- Stale Review Reminders & Escalation ---
+
+// ProcessStaleReviews проходит по открытым назначениям: после RemindAfterHours политики команды автора
+// ревьюеру один раз отправляется напоминание, после EscalateAfterHours ревьюер заменяется через RerollReviewer.
+func (s *Manager) ProcessStaleReviews(ctx context.Context) error {
+	assignments, err := s.repo.GetOpenAssignments(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	failed := 0
+	policies := make(map[int]*domain.TeamPolicy)
+	for _, a := range assignments {
+		ctx := domain.WithOrgID(ctx, a.OrgID)
+		policy, ok := policies[a.AuthorTeamID]
+		if !ok {
+			if policy, err = s.teamPolicy(ctx, a.AuthorTeamID); err != nil {
+				return err
+			}
+			policies[a.AuthorTeamID] = policy
+		}
+
+		waiting := now.Sub(a.AssignedAt)
+
+		if policy.EscalateAfterHours > 0 && waiting >= time.Duration(policy.EscalateAfterHours)*time.Hour {
+			// Ошибка одной эскалации не останавливает проход: назначение останется и повторится в следующий раз
+			if err := s.escalateReview(ctx, a, waiting); err != nil {
+				log.Printf("Failed to escalate review of PR %d by user %d: %v", a.PRID, a.ReviewerID, err)
+				failed++
+			}
+			continue
+		}
+
+		if policy.RemindAfterHours > 0 && a.RemindedAt == nil && waiting >= time.Duration(policy.RemindAfterHours)*time.Hour {
+			if err := s.repo.MarkReviewReminded(ctx, a.PRID, a.ReviewerID, now); err != nil {
+				return err
+			}
+			s.recordHistory(ctx, a.PRID, domain.HistoryReminder, a.ReviewerID,
+				fmt.Sprintf("review pending for %s", waiting.Round(time.Minute)))
+			s.publishEvent(ctx, domain.EventReviewReminder, a.PRID, a.AuthorTeamID, a.ReviewerID)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d review escalations failed", failed)
+	}
+	return nil
+}
+
+// --- Helpers ---
+
+// escalateReview заменяет зависшего ревьюера. Если замены нет, ревьюер остается - попробуем в следующий раз.
+func (s *Manager) escalateReview(ctx context.Context, a domain.ReviewAssignment, waiting time.Duration) error {
+	_, err := s.RerollReviewer(ctx, a.PRID, a.ReviewerID)
+	if errors.Is(err, domain.ErrNoReviewersFound) || errors.Is(err, domain.ErrPRAlreadyMerged) {
+		log.Printf("Escalation of PR %d skipped: %v", a.PRID, err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Саму замену RerollReviewer уже записал в историю, здесь фиксируем причину
+	s.recordHistory(ctx, a.PRID, domain.HistoryEscalated, a.ReviewerID,
+		fmt.Sprintf("no review for %s, reviewer rerolled", waiting.Round(time.Minute)))
+	s.publishEvent(ctx, domain.EventReviewEscalated, a.PRID, a.AuthorTeamID, a.ReviewerID)
+	return nil
+}
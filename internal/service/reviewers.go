@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// --- Manual Reviewer Management ---
+
+// AddReviewer назначает на PR конкретного пользователя сверх уже назначенных
+func (s *Manager) AddReviewer(ctx context.Context, prID int, userID int) (*domain.PullRequest, error) {
+	pr, err := s.loadOpenPR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := s.teamPolicy(ctx, pr.Author.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if len(pr.Reviewers) >= policy.MaxReviewers {
+		return nil, domain.ErrReviewerLimitReached
+	}
+
+	// Лимит открытых ревью проверяется и записывается под блокировкой (см. lockCandidates),
+	// лимит ревьюеров PR - по PR, перечитанному под блокировкой строки
+	unlock, err := s.repo.LockReviewers(ctx, []int{userID})
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	pr, err = s.repo.UpdateOpenPR(ctx, prID, func(pr *domain.PullRequest) error {
+		if len(pr.Reviewers) >= policy.MaxReviewers {
+			return domain.ErrReviewerLimitReached
+		}
+		reviewer, err := s.validateReviewerChoice(ctx, pr, policy, userID)
+		if err != nil {
+			return err
+		}
+		pr.Reviewers = append(pr.Reviewers, *reviewer)
+		pr.WaitingSince = nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordHistory(ctx, pr.ID, domain.HistoryReviewerAdded, userID, "added manually")
+	s.publishEvent(ctx, domain.EventReviewerAssigned, pr.ID, pr.Author.TeamID, userID)
+	s.publishEvent(ctx, domain.EventPRAssigned, pr.ID, pr.Author.TeamID, 0)
+	return pr, nil
+}
+
+// RemoveReviewer снимает ревьюера без замены, если политика команды это допускает
+func (s *Manager) RemoveReviewer(ctx context.Context, prID int, userID int) (*domain.PullRequest, error) {
+	pr, err := s.loadOpenPR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.teamPolicy(ctx, pr.Author.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err = s.repo.UpdateOpenPR(ctx, prID, func(pr *domain.PullRequest) error {
+		if !hasReviewer(pr, userID) {
+			return domain.ErrNotReviewer
+		}
+		if len(pr.Reviewers)-1 < policy.MinReviewers {
+			return domain.ErrReviewerLimitReached
+		}
+		pr.Reviewers = withoutReviewer(pr.Reviewers, userID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.recordHistory(ctx, pr.ID, domain.HistoryReviewerRemoved, userID, "removed manually")
+	s.publishEvent(ctx, domain.EventReviewerUnassigned, pr.ID, pr.Author.TeamID, userID)
+
+	// У снятого ревьюера освободилось место - возможно, кто-то ждет в очереди
+	s.signalQueue()
+	return pr, nil
+}
+
+// ReplaceReviewer - переназначение с явно выбранным новым ревьюером вместо случайного
+func (s *Manager) ReplaceReviewer(ctx context.Context, prID int, oldReviewerID int, newReviewerID int) (*domain.PullRequest, error) {
+	pr, err := s.loadOpenPR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if !hasReviewer(pr, oldReviewerID) {
+		return nil, domain.ErrNotReviewer
+	}
+
+	policy, err := s.teamPolicy(ctx, pr.Author.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := s.repo.LockReviewers(ctx, []int{newReviewerID})
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	pr, err = s.repo.UpdateOpenPR(ctx, prID, func(pr *domain.PullRequest) error {
+		if !hasReviewer(pr, oldReviewerID) {
+			return domain.ErrNotReviewer
+		}
+		reviewer, err := s.validateReviewerChoice(ctx, pr, policy, newReviewerID)
+		if err != nil {
+			return err
+		}
+		swapReviewer(pr, oldReviewerID, *reviewer)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.recordHistory(ctx, pr.ID, domain.HistoryReviewerChanged, oldReviewerID,
+		fmt.Sprintf("replaced manually by user %d", newReviewerID))
+	s.publishEvent(ctx, domain.EventReviewerUnassigned, pr.ID, pr.Author.TeamID, oldReviewerID)
+	s.publishEvent(ctx, domain.EventReviewerAssigned, pr.ID, pr.Author.TeamID, newReviewerID)
+	s.publishEvent(ctx, domain.EventReviewerReplaced, pr.ID, pr.Author.TeamID, oldReviewerID)
+
+	s.signalQueue()
+	return pr, nil
+}
+
+// --- Helpers ---
+
+// loadOpenPR загружает PR вместе с автором и проверяет, что он еще не смержен
+func (s *Manager) loadOpenPR(ctx context.Context, prID int) (*domain.PullRequest, error) {
+	pr, err := s.repo.GetPRByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Проверка: нельзя менять после мерджа
+	if pr.Status == domain.PRStatusMerged {
+		return nil, domain.ErrPRAlreadyMerged
+	}
+	if pr.Status == domain.PRStatusClosed {
+		return nil, domain.ErrPRClosed
+	}
+
+	if pr.Author == nil {
+		// Подгрузим автора если вдруг его нет (хотя Preload в repo должен был сработать)
+		author, err := s.repo.GetUserByID(ctx, pr.AuthorID)
+		if err != nil {
+			return nil, err
+		}
+		pr.Author = author
+	}
+	return pr, nil
+}
+
+// validateReviewerChoice проверяет явно выбранного ревьюера по тем же правилам, что и автоматический выбор:
+// активен, не автор, еще не назначен, из допустимой команды, доступен, не перегружен и подходит по уровню
+func (s *Manager) validateReviewerChoice(ctx context.Context, pr *domain.PullRequest, policy *domain.TeamPolicy, userID int) (*domain.User, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsActive {
+		return nil, domain.ErrReviewerNotActive
+	}
+	if user.ID == pr.AuthorID {
+		return nil, domain.ErrReviewerIsAuthor
+	}
+	if hasReviewer(pr, user.ID) {
+		return nil, domain.ErrAlreadyReviewer
+	}
+
+	// Пользователь должен ревьюить в команде автора или в одной из запасных
+	memberships, err := s.repo.GetUserMemberships(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := s.candidateTeams(ctx, pr.Author.TeamID, policy)
+	if err != nil {
+		return nil, err
+	}
+	reviewTeamID := 0
+	for _, teamID := range teams {
+		if m := membershipIn(memberships, teamID); m != nil && m.CanReview {
+			reviewTeamID = teamID
+			break
+		}
+	}
+	if reviewTeamID == 0 {
+		return nil, domain.ErrReviewerNotEligible
+	}
+
+	eligible, err := s.filterEligible(ctx, reviewTeamID, []domain.User{*user}, policy, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(eligible) == 0 {
+		return nil, domain.ErrReviewerNotEligible
+	}
+	return user, nil
+}
+
+// swapReviewer заменяет oldReviewerID на newReviewer, сохраняя порядок ревьюеров (сохраняет вызывающий)
+func swapReviewer(pr *domain.PullRequest, oldReviewerID int, newReviewer domain.User) {
+	newReviewersList := make([]domain.User, 0, len(pr.Reviewers))
+	for _, r := range pr.Reviewers {
+		if r.ID == oldReviewerID {
+			newReviewersList = append(newReviewersList, newReviewer)
+		} else {
+			newReviewersList = append(newReviewersList, r)
+		}
+	}
+	pr.Reviewers = newReviewersList
+}
+
+// withoutReviewer - список ревьюеров без userID
+func withoutReviewer(reviewers []domain.User, userID int) []domain.User {
+	remaining := make([]domain.User, 0, len(reviewers))
+	for _, r := range reviewers {
+		if r.ID != userID {
+			remaining = append(remaining, r)
+		}
+	}
+	return remaining
+}
+
+// hasReviewer - назначен ли пользователь ревьюером PR
+func hasReviewer(pr *domain.PullRequest, userID int) bool {
+	for _, r := range pr.Reviewers {
+		if r.ID == userID {
+			return true
+		}
+	}
+	return false
+}
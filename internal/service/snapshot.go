@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/Shishlyannikovvv/project-avito/internal/snapshot"
+)
+
+// --- Snapshot ---
+
+// SetAnonymizationKey задает секрет псевдонимов обезличенного снимка: с тем же ключом пользователь
+// в разных снимках получает те же псевдонимы
+func (s *Manager) SetAnonymizationKey(key []byte) {
+	s.anonymizationKey = key
+}
+
+// ExportSnapshot возвращает состояние всех организаций
+func (s *Manager) ExportSnapshot(ctx context.Context, anonymize bool) (*domain.Snapshot, error) {
+	if anonymize && len(s.anonymizationKey) == 0 {
+		return nil, domain.ErrAnonymizationKeyMissing
+	}
+	snap, err := s.repo.ExportSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if anonymize {
+		snapshot.Anonymize(snap, s.anonymizationKey)
+	}
+	return snap, nil
+}
+
+// ImportSnapshot восстанавливает снимок в пустую базу с исходными ID
+func (s *Manager) ImportSnapshot(ctx context.Context, snapshot *domain.Snapshot) error {
+	if err := s.repo.ImportSnapshot(ctx, snapshot); err != nil {
+		return err
+	}
+	// В восстановленной базе могут быть PR, ожидающие ревьюера
+	s.signalQueue()
+	return nil
+}
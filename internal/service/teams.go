@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// --- Team Management ---
+
+func (s *Manager) GetTeam(ctx context.Context, teamID int) (*domain.Team, error) {
+	return s.repo.GetTeamByID(ctx, teamID)
+}
+
+func (s *Manager) RenameTeam(ctx context.Context, teamID int, name string) (*domain.Team, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domain.ErrInvalidTeamName
+	}
+	if err := s.repo.RenameTeam(ctx, teamID, name); err != nil {
+		return nil, err
+	}
+	return s.repo.GetTeamByID(ctx, teamID)
+}
+
+// ArchiveTeam архивирует команду. Участники, для которых она основная, деактивируются, а их ревью
+// переназначаются (или снимаются, если замены нет). Открытые PR команды по стратегии strategy
+// остаются ждать ревью из запасных команд (ArchiveReassign) или закрываются (ArchiveClose).
+// Отметка об архивации ставится последней: если шаг не удался, повторный вызов доделает остальное.
+func (s *Manager) ArchiveTeam(ctx context.Context, teamID int, strategy string) (*domain.Team, error) {
+	if strategy == "" {
+		strategy = domain.ArchiveReassign
+	}
+	if strategy != domain.ArchiveReassign && strategy != domain.ArchiveClose {
+		return nil, domain.ErrInvalidStrategy
+	}
+	if _, err := s.activeTeam(ctx, teamID); err != nil {
+		return nil, err
+	}
+
+	if strategy == domain.ArchiveClose {
+		prs, err := s.repo.GetOpenPRsByTeam(ctx, teamID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range prs {
+			if err := s.closePR(ctx, &prs[i], "team archived"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	members, err := s.repo.GetUsersByTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]int, 0, len(members))
+	for _, m := range members {
+		if m.TeamID == teamID {
+			targets = append(targets, m.ID)
+		}
+	}
+	for _, id := range targets {
+		if err := s.repo.DeactivateUser(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	// Деактивированные участники уже не выбираются на замену
+	for _, id := range targets {
+		if err := s.reassignOpenReviews(ctx, id, true); err != nil {
+			return nil, err
+		}
+	}
+
+	// Команда выпадает из пулов кандидатов
+	if err := s.repo.ArchiveTeam(ctx, teamID, s.now()); err != nil {
+		return nil, err
+	}
+	return s.repo.GetTeamByID(ctx, teamID)
+}
+
+// MoveTeamMembers переводит участников команды fromTeamID (всех, если userIDs не переданы) в toTeamID.
+// Для кого fromTeamID основная - это перевод через UpdateUser (с переназначением ревью),
+// остальные просто меняют участие, сохраняя флаги.
+func (s *Manager) MoveTeamMembers(ctx context.Context, fromTeamID int, toTeamID int, userIDs ...int) ([]domain.User, error) {
+	if _, err := s.repo.GetTeamByID(ctx, fromTeamID); err != nil {
+		return nil, err
+	}
+	if _, err := s.activeTeam(ctx, toTeamID); err != nil {
+		return nil, err
+	}
+
+	memberships, err := s.repo.GetMembersOfTeams(ctx, []int{fromTeamID})
+	if err != nil {
+		return nil, err
+	}
+	requested := make(map[int]bool, len(userIDs))
+	for _, id := range userIDs {
+		requested[id] = true
+	}
+	targets := make([]domain.TeamMembership, 0, len(memberships))
+	for _, m := range memberships {
+		if len(userIDs) == 0 || requested[m.UserID] {
+			targets = append(targets, m)
+			delete(requested, m.UserID)
+		}
+	}
+	// Среди переданных есть пользователи не из этой команды
+	if len(requested) > 0 {
+		return nil, domain.ErrMembershipNotFound
+	}
+
+	moved := make([]domain.User, 0, len(targets))
+	for _, m := range targets {
+		if m.User != nil && m.User.TeamID == fromTeamID {
+			user, err := s.UpdateUser(ctx, m.UserID, domain.UserUpdate{TeamID: &toTeamID})
+			if err != nil {
+				return nil, err
+			}
+			moved = append(moved, *user)
+			continue
+		}
+
+		if err := s.repo.SaveTeamMembership(ctx, &domain.TeamMembership{TeamID: toTeamID, UserID: m.UserID, CanAuthor: m.CanAuthor, CanReview: m.CanReview}); err != nil {
+			return nil, err
+		}
+		if err := s.repo.DeleteTeamMembership(ctx, fromTeamID, m.UserID); err != nil {
+			return nil, err
+		}
+		if m.User != nil {
+			moved = append(moved, *m.User)
+		}
+	}
+	return moved, nil
+}
+
+// --- Helpers ---
+
+// activeTeam возвращает команду, если она не в архиве
+func (s *Manager) activeTeam(ctx context.Context, teamID int) (*domain.Team, error) {
+	team, err := s.repo.GetTeamByID(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if team.ArchivedAt != nil {
+		return nil, domain.ErrTeamArchived
+	}
+	return team, nil
+}
+
+// closePR закрывает открытый PR без мерджа; уже смерженный или закрытый PR пропускается
+func (s *Manager) closePR(ctx context.Context, pr *domain.PullRequest, reason string) error {
+	pr, err := s.repo.UpdateOpenPR(ctx, pr.ID, func(pr *domain.PullRequest) error {
+		pr.Status = domain.PRStatusClosed
+		pr.WaitingSince = nil
+		return nil
+	})
+	if errors.Is(err, domain.ErrPRAlreadyMerged) || errors.Is(err, domain.ErrPRClosed) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	s.recordHistory(ctx, pr.ID, domain.HistoryClosed, 0, reason)
+	s.publishEvent(ctx, domain.EventPRClosed, pr.ID, prTeamID(pr), 0)
+	// Ревьюеры освободились
+	s.signalQueue()
+	return nil
+}
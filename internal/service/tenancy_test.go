@@ -0,0 +1,93 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupOrgs создает две организации и возвращает контексты их запросов
+func setupOrgs(t *testing.T) (context.Context, context.Context) {
+	ctx := context.Background()
+	orgA, keyA, err := testService.CreateOrganization(ctx, "Org A")
+	assert.NoError(t, err)
+	orgB, _, err := testService.CreateOrganization(ctx, "Org B")
+	assert.NoError(t, err)
+
+	authed, err := testService.AuthenticateOrg(ctx, keyA)
+	assert.NoError(t, err)
+	assert.Equal(t, orgA.ID, authed.ID)
+	_, err = testService.AuthenticateOrg(ctx, "rk_unknown")
+	assert.ErrorIs(t, err, domain.ErrOrganizationNotFound)
+
+	return domain.WithOrgID(ctx, orgA.ID), domain.WithOrgID(ctx, orgB.ID)
+}
+
+func TestTeamNamesArePerOrg(t *testing.T) {
+	setupTest(t)
+	ctxA, ctxB := setupOrgs(t)
+
+	_, err := testService.CreateTeam(ctxA, "Platform")
+	assert.NoError(t, err)
+	_, err = testService.CreateTeam(ctxB, "Platform")
+	assert.NoError(t, err, "same name in another org")
+
+	_, err = testService.CreateTeam(ctxA, "Platform")
+	assert.ErrorIs(t, err, domain.ErrTeamAlreadyExists)
+}
+
+func TestOrgIsolation(t *testing.T) {
+	setupTest(t)
+	ctxA, ctxB := setupOrgs(t)
+
+	teamA, _ := testService.CreateTeam(ctxA, "Alpha")
+	authorA, _ := testService.CreateUser(ctxA, "Author A", teamA.ID)
+	reviewerA, _ := testService.CreateUser(ctxA, "Reviewer A", teamA.ID)
+	prA, err := testService.CreatePR(ctxA, "A's change", authorA.ID)
+	assert.NoError(t, err)
+	assert.Len(t, prA.Reviewers, 1)
+
+	teamB, _ := testService.CreateTeam(ctxB, "Beta")
+	userB, _ := testService.CreateUser(ctxB, "User B", teamB.ID)
+
+	// Чужие записи не находятся
+	_, err = testService.GetPR(ctxB, prA.ID)
+	assert.ErrorIs(t, err, domain.ErrPRNotFound)
+	_, err = testService.MergePR(ctxB, prA.ID)
+	assert.ErrorIs(t, err, domain.ErrPRNotFound)
+	_, err = testService.GetTeamPolicy(ctxB, teamA.ID)
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+	_, err = testService.UpdateTeamPolicy(ctxB, &domain.TeamPolicy{TeamID: teamA.ID, MaxReviewers: 1})
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+	_, err = testService.SetUserSeniority(ctxB, reviewerA.ID, domain.SeniorityMiddle)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+	// Ссылки на чужие сущности отклоняются
+	_, err = testService.CreateUser(ctxB, "Intruder", teamA.ID)
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+	_, err = testService.CreatePR(ctxB, "Spoofed author", authorA.ID)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	_, err = testService.AddReviewer(ctxA, prA.ID, userB.ID)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+	// Статистика и журнал событий не смешиваются
+	statsB, err := testService.GetReviewerStats(ctxB)
+	assert.NoError(t, err)
+	assert.Empty(t, statsB)
+	statsA, err := testService.GetReviewerStats(ctxA)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, statsA[reviewerA.ID])
+
+	eventsB, err := testService.GetEventsAfter(ctxB, 0, domain.EventFilter{}, 100)
+	assert.NoError(t, err)
+	assert.Empty(t, eventsB)
+	eventsA, err := testService.GetEventsAfter(ctxA, 0, domain.EventFilter{}, 100)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, eventsA)
+
+	// Организация по умолчанию (запросы без ключа) тоже ничего не видит
+	_, err = testService.GetPR(context.Background(), prA.ID)
+	assert.ErrorIs(t, err, domain.ErrPRNotFound)
+}
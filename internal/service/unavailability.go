@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// --- Unavailability Logic ---
+
+func (s *Manager) AddUnavailability(ctx context.Context, window *domain.UserUnavailability) (*domain.UserUnavailability, error) {
+	if !window.EndsAt.After(window.StartsAt) {
+		return nil, domain.ErrInvalidPeriod
+	}
+	if _, err := s.repo.GetUserByID(ctx, window.UserID); err != nil {
+		return nil, err
+	}
+
+	window.ID = 0
+	window.ReassignedAt = nil
+	if err := s.repo.CreateUnavailability(ctx, window); err != nil {
+		return nil, err
+	}
+	return window, nil
+}
+
+func (s *Manager) ListUnavailability(ctx context.Context, userID int) ([]domain.UserUnavailability, error) {
+	if _, err := s.repo.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUnavailabilityByUser(ctx, userID)
+}
+
+func (s *Manager) UpdateUnavailability(ctx context.Context, window *domain.UserUnavailability) (*domain.UserUnavailability, error) {
+	if !window.EndsAt.After(window.StartsAt) {
+		return nil, domain.ErrInvalidPeriod
+	}
+
+	existing, err := s.userWindow(ctx, window.UserID, window.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing.StartsAt = window.StartsAt
+	existing.EndsAt = window.EndsAt
+	existing.Reason = window.Reason
+	// Если окно сдвинули в будущее, при его начале ревью нужно будет переназначить заново
+	if existing.StartsAt.After(s.now()) {
+		existing.ReassignedAt = nil
+	}
+
+	if err := s.repo.UpdateUnavailability(ctx, existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+func (s *Manager) DeleteUnavailability(ctx context.Context, userID int, windowID int) error {
+	if _, err := s.userWindow(ctx, userID, windowID); err != nil {
+		return err
+	}
+	return s.repo.DeleteUnavailability(ctx, windowID)
+}
+
+// ReassignStartedUnavailability переназначает открытые ревью пользователей, чье отсутствие уже началось.
+// Каждое окно обрабатывается один раз.
+func (s *Manager) ReassignStartedUnavailability(ctx context.Context) error {
+	now := s.now()
+	windows, err := s.repo.GetPendingUnavailabilityStarts(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	// Ошибка одного окна не должна останавливать остальные: неотмеченное окно повторится при следующем запуске
+	failed := 0
+	for _, w := range windows {
+		if err := s.reassignWindow(ctx, w, now); err != nil {
+			log.Printf("Failed to reassign reviews of user %d for unavailability window %d: %v", w.UserID, w.ID, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d unavailability windows failed", failed, len(windows))
+	}
+	return nil
+}
+
+// --- Helpers ---
+
+// reassignWindow переназначает ревью отсутствующего пользователя и отмечает окно обработанным
+func (s *Manager) reassignWindow(ctx context.Context, w domain.UserUnavailability, now time.Time) error {
+	user, err := s.repo.GetUserByID(ctx, w.UserID)
+	if err != nil {
+		return err
+	}
+	ctx = domain.WithOrgID(ctx, user.OrgID)
+	// Пользователь остается активным, поэтому при отсутствии замены он просто остается ревьюером
+	if err := s.reassignOpenReviews(ctx, w.UserID, false); err != nil {
+		return err
+	}
+	return s.repo.MarkUnavailabilityReassigned(ctx, w.ID, now)
+}
+
+// userWindow возвращает окно отсутствия, проверяя, что оно принадлежит пользователю
+func (s *Manager) userWindow(ctx context.Context, userID int, windowID int) (*domain.UserUnavailability, error) {
+	window, err := s.repo.GetUnavailabilityByID(ctx, windowID)
+	if err != nil {
+		return nil, err
+	}
+	if window.UserID != userID {
+		return nil, domain.ErrUnavailabilityNotFound
+	}
+	return window, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// --- User Lifecycle ---
+
+// ActivateUser возвращает деактивированного пользователя в пулы ревьюеров
+func (s *Manager) ActivateUser(ctx context.Context, userID int) (*domain.User, error) {
+	if _, err := s.liveUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.ActivateUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	// Появился кандидат для PR из очереди
+	s.signalQueue()
+	return s.repo.GetUserByID(ctx, userID)
+}
+
+// UpdateUser меняет имя и основную команду. При переводе открытые ревью PR авторов старой команды
+// переназначаются (если замены нет, пользователь остается ревьюером).
+func (s *Manager) UpdateUser(ctx context.Context, userID int, update domain.UserUpdate) (*domain.User, error) {
+	user, err := s.liveUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Сначала проверяем все поля, чтобы ошибка в одном не оставила изменения в другом
+	var name string
+	if update.Name != nil {
+		name = strings.TrimSpace(*update.Name)
+		if name == "" {
+			return nil, domain.ErrInvalidUserName
+		}
+	}
+	transfer := update.TeamID != nil && *update.TeamID != user.TeamID
+	if transfer {
+		if _, err := s.repo.GetTeamByID(ctx, *update.TeamID); err != nil {
+			return nil, err
+		}
+	}
+
+	if update.Name != nil {
+		if err := s.repo.SetUserName(ctx, userID, name); err != nil {
+			return nil, err
+		}
+	}
+
+	if transfer {
+		oldTeamID := user.TeamID
+		// Переназначаем, пока пользователь еще в старой команде: замена берется из нее же
+		fromOldTeam := func(pr *domain.PullRequest) bool { return prTeamID(pr) == oldTeamID }
+		if err := s.reassignReviews(ctx, userID, false, fromOldTeam, oldTeamID); err != nil {
+			return nil, err
+		}
+		if err := s.repo.TransferUser(ctx, userID, *update.TeamID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.GetUserByID(ctx, userID)
+}
+
+// HardDeleteUser окончательно удаляет пользователя: он снимается с открытых ревью (с заменой, если она есть)
+// и обезличивается. Ссылки из PR и истории сохраняются и указывают на обезличенную запись.
+func (s *Manager) HardDeleteUser(ctx context.Context, userID int) error {
+	if _, err := s.liveUser(ctx, userID); err != nil {
+		return err
+	}
+	// Сначала деактивируем, чтобы пользователь не выбирался на замену
+	if err := s.repo.DeactivateUser(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.reassignOpenReviews(ctx, userID, true); err != nil {
+		return err
+	}
+	return s.repo.AnonymizeUser(ctx, userID, s.now())
+}
+
+// --- Helpers ---
+
+// liveUser возвращает пользователя, если он не удален окончательно
+func (s *Manager) liveUser(ctx context.Context, userID int) (*domain.User, error) {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletedAt != nil {
+		return nil, domain.ErrUserDeleted
+	}
+	return user, nil
+}
@@ -0,0 +1,74 @@
+package snapshot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// Anonymize заменяет персональные данные снимка детерминированными псевдонимами (HMAC-SHA256 с ключом key):
+// тот же пользователь с тем же ключом всегда получает тот же псевдоним, а без ключа исходное значение
+// не подобрать. ID не меняются, поэтому ссылки (авторы, ревьюеры, участие в командах) остаются целыми.
+//
+// Заменяются имена пользователей, логины (и ссылки на них в CODEOWNERS), email, ники в чате и названия PR.
+// Причины отсутствия очищаются, вебхуки чатов и хеши API-ключей удаляются: окружение, в которое
+// переносится снимок, не должно писать в рабочие чаты и принимать рабочие ключи.
+func Anonymize(s *domain.Snapshot, key []byte) {
+	p := pseudonymizer{key: key}
+
+	for i := range s.Organizations {
+		s.Organizations[i].APIKeyHash = ""
+	}
+	for i := range s.Users {
+		u := &s.Users[i]
+		u.Name = "User " + p.token("user", fmt.Sprint(u.ID))
+		u.Login = p.login(u.Login)
+		if u.Email != "" {
+			u.Email = p.token("email", u.Email) + "@example.invalid"
+		}
+		if u.ChatHandle != "" {
+			u.ChatHandle = "U" + strings.ToUpper(p.token("chat", u.ChatHandle))
+		}
+	}
+	for i := range s.CodeOwnerRules {
+		owners := s.CodeOwnerRules[i].Owners
+		for j, owner := range owners {
+			// @org/team-name - ссылка на команду, названия команд не персональные
+			if login, ok := strings.CutPrefix(owner, "@"); ok && !strings.Contains(login, "/") {
+				owners[j] = "@" + p.login(login)
+			}
+		}
+	}
+	for i := range s.Unavailability {
+		s.Unavailability[i].Reason = ""
+	}
+	s.ChatWebhooks = nil
+	for i := range s.PullRequests {
+		pr := &s.PullRequests[i]
+		pr.Title = "PR " + p.token("title", fmt.Sprint(pr.ID))
+	}
+}
+
+type pseudonymizer struct {
+	key []byte
+}
+
+// token - 12 hex-символов HMAC от значения; kind разделяет пространства имен (одинаковые строки
+// в разных полях дают разные псевдонимы)
+func (p pseudonymizer) token(kind, value string) string {
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(kind + ":" + value))
+	return hex.EncodeToString(mac.Sum(nil))[:12]
+}
+
+// login - псевдоним логина; у владельцев кода в CODEOWNERS тот же псевдоним, что и у пользователя
+func (p pseudonymizer) login(login string) string {
+	if login == "" {
+		return ""
+	}
+	return "user-" + p.token("login", login)
+}
@@ -0,0 +1,56 @@
+package snapshot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleSnapshot() *domain.Snapshot {
+	return &domain.Snapshot{
+		Organizations: []domain.Organization{{ID: 1, Name: "default", APIKeyHash: "secret-hash"}},
+		Users: []domain.User{
+			{ID: 1, Name: "Alice Smith", Login: "alice", Email: "alice@corp.com", ChatHandle: "U123", TeamID: 1},
+			{ID: 2, Name: "Bob", TeamID: 1},
+		},
+		CodeOwnerRules: []domain.CodeOwnerRule{{TeamID: 1, Pattern: "*.go", Owners: []string{"@alice", "@corp/backend"}}},
+		Unavailability: []domain.UserUnavailability{{ID: 1, UserID: 1, Reason: "surgery"}},
+		ChatWebhooks:   []domain.TeamChatWebhook{{TeamID: 1, URL: "https://hooks.slack.com/services/T/B/x"}},
+		PullRequests:   []domain.PullRequest{{ID: 7, Title: "Fix salary export for Alice", AuthorID: 1}},
+		Reviewers:      []domain.PRReviewer{{PullRequestID: 7, UserID: 2}},
+	}
+}
+
+func TestAnonymize(t *testing.T) {
+	s := sampleSnapshot()
+	Anonymize(s, []byte("k1"))
+
+	alice := s.Users[0]
+	assert.NotContains(t, alice.Name, "Alice")
+	assert.True(t, strings.HasPrefix(alice.Login, "user-"))
+	assert.True(t, strings.HasSuffix(alice.Email, "@example.invalid"))
+	assert.NotEqual(t, "U123", alice.ChatHandle)
+	assert.NotEqual(t, alice.Name, s.Users[1].Name)
+	assert.Empty(t, s.Users[1].Login, "empty identities stay empty")
+	assert.NotContains(t, s.PullRequests[0].Title, "Alice")
+
+	// Ссылки не меняются, CODEOWNERS указывает на тот же псевдоним логина
+	assert.Equal(t, 1, s.PullRequests[0].AuthorID)
+	assert.Equal(t, domain.PRReviewer{PullRequestID: 7, UserID: 2}, s.Reviewers[0])
+	assert.Equal(t, []string{"@" + alice.Login, "@corp/backend"}, s.CodeOwnerRules[0].Owners)
+
+	// Секреты и свободный текст удалены
+	assert.Empty(t, s.Organizations[0].APIKeyHash)
+	assert.Empty(t, s.Unavailability[0].Reason)
+	assert.Empty(t, s.ChatWebhooks)
+
+	// Тот же ключ - те же псевдонимы, другой ключ - другие
+	again := sampleSnapshot()
+	Anonymize(again, []byte("k1"))
+	assert.Equal(t, s.Users, again.Users)
+	other := sampleSnapshot()
+	Anonymize(other, []byte("k2"))
+	assert.NotEqual(t, s.Users[0].Login, other.Users[0].Login)
+}
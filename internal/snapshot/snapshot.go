@@ -0,0 +1,226 @@
+// Package snapshot - переносимый формат резервной копии сервиса.
+// Снимок - JSON lines: первая строка - заголовок с версией формата, далее по записи на строку
+// вида {"type":"team","data":{...}} в порядке восстановления.
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+const (
+	Format = "project-avito-snapshot"
+	// Версия формата; снимки более новой версии не читаются
+	Version = 1
+)
+
+// Типы записей
+const (
+	typeOrganization   = "organization"
+	typeTeam           = "team"
+	typeUser           = "user"
+	typeMembership     = "membership"
+	typePolicy         = "policy"
+	typeCodeOwnerRule  = "codeowner_rule"
+	typeTemplate       = "template"
+	typeChatWebhook    = "chat_webhook"
+	typeUnavailability = "unavailability"
+	typePullRequest    = "pull_request"
+	typeReviewer       = "reviewer"
+	typeHistory        = "history"
+)
+
+type header struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type record struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Поля, скрытые из JSON API, в снимке нужны
+
+type organizationRecord struct {
+	domain.Organization
+	APIKeyHash string `json:"api_key_hash,omitempty"`
+}
+
+type userRecord struct {
+	domain.User
+	OrgID int `json:"org_id"`
+}
+
+type pullRequestRecord struct {
+	domain.PullRequest
+	OrgID int `json:"org_id"`
+	// Ревьюеры восстанавливаются записями reviewer
+	Reviewers []domain.User `json:"reviewers,omitempty"`
+}
+
+type reviewerRecord struct {
+	PullRequestID int        `json:"pr_id"`
+	UserID        int        `json:"user_id"`
+	AssignedAt    time.Time  `json:"assigned_at"`
+	RemindedAt    *time.Time `json:"reminded_at,omitempty"`
+}
+
+// Write пишет снимок в w
+func Write(w io.Writer, s *domain.Snapshot, createdAt time.Time) error {
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(header{Format: Format, Version: Version, CreatedAt: createdAt.UTC()}); err != nil {
+		return err
+	}
+
+	var err error
+	emit := func(typ string, data interface{}) {
+		if err != nil {
+			return
+		}
+		var raw []byte
+		if raw, err = json.Marshal(data); err == nil {
+			err = enc.Encode(record{Type: typ, Data: raw})
+		}
+	}
+
+	for _, o := range s.Organizations {
+		emit(typeOrganization, organizationRecord{Organization: o, APIKeyHash: o.APIKeyHash})
+	}
+	for _, t := range s.Teams {
+		emit(typeTeam, t)
+	}
+	for _, u := range s.Users {
+		u.Team = nil
+		emit(typeUser, userRecord{User: u, OrgID: u.OrgID})
+	}
+	for _, m := range s.Memberships {
+		m.User = nil
+		emit(typeMembership, m)
+	}
+	for _, p := range s.Policies {
+		emit(typePolicy, p)
+	}
+	for _, rule := range s.CodeOwnerRules {
+		emit(typeCodeOwnerRule, rule)
+	}
+	for _, tpl := range s.Templates {
+		emit(typeTemplate, tpl)
+	}
+	for _, wh := range s.ChatWebhooks {
+		emit(typeChatWebhook, wh)
+	}
+	for _, window := range s.Unavailability {
+		emit(typeUnavailability, window)
+	}
+	for _, pr := range s.PullRequests {
+		pr.Author = nil
+		emit(typePullRequest, pullRequestRecord{PullRequest: pr, OrgID: pr.OrgID})
+	}
+	for _, r := range s.Reviewers {
+		emit(typeReviewer, reviewerRecord{PullRequestID: r.PullRequestID, UserID: r.UserID, AssignedAt: r.AssignedAt, RemindedAt: r.RemindedAt})
+	}
+	for _, h := range s.History {
+		emit(typeHistory, h)
+	}
+	return err
+}
+
+// Read читает снимок, записанный Write (этой или более ранней версии формата)
+func Read(r io.Reader) (*domain.Snapshot, error) {
+	dec := json.NewDecoder(r)
+
+	var h header
+	if err := dec.Decode(&h); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", domain.ErrInvalidSnapshot, err)
+	}
+	if h.Format != Format {
+		return nil, fmt.Errorf("%w: unknown format %q", domain.ErrInvalidSnapshot, h.Format)
+	}
+	if h.Version < 1 || h.Version > Version {
+		return nil, fmt.Errorf("%w: unsupported version %d", domain.ErrInvalidSnapshot, h.Version)
+	}
+
+	var s domain.Snapshot
+	for line := 2; ; line++ {
+		var rec record
+		err := dec.Decode(&rec)
+		if errors.Is(err, io.EOF) {
+			return &s, nil
+		}
+		if err == nil {
+			err = addRecord(&s, rec)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", domain.ErrInvalidSnapshot, line, err)
+		}
+	}
+}
+
+func addRecord(s *domain.Snapshot, rec record) error {
+	switch rec.Type {
+	case typeOrganization:
+		var o organizationRecord
+		if err := json.Unmarshal(rec.Data, &o); err != nil {
+			return err
+		}
+		o.Organization.APIKeyHash = o.APIKeyHash
+		s.Organizations = append(s.Organizations, o.Organization)
+	case typeTeam:
+		return appendDecoded(rec.Data, &s.Teams)
+	case typeUser:
+		var u userRecord
+		if err := json.Unmarshal(rec.Data, &u); err != nil {
+			return err
+		}
+		u.User.OrgID = u.OrgID
+		s.Users = append(s.Users, u.User)
+	case typeMembership:
+		return appendDecoded(rec.Data, &s.Memberships)
+	case typePolicy:
+		return appendDecoded(rec.Data, &s.Policies)
+	case typeCodeOwnerRule:
+		return appendDecoded(rec.Data, &s.CodeOwnerRules)
+	case typeTemplate:
+		return appendDecoded(rec.Data, &s.Templates)
+	case typeChatWebhook:
+		return appendDecoded(rec.Data, &s.ChatWebhooks)
+	case typeUnavailability:
+		return appendDecoded(rec.Data, &s.Unavailability)
+	case typePullRequest:
+		var pr pullRequestRecord
+		if err := json.Unmarshal(rec.Data, &pr); err != nil {
+			return err
+		}
+		pr.PullRequest.OrgID = pr.OrgID
+		s.PullRequests = append(s.PullRequests, pr.PullRequest)
+	case typeReviewer:
+		var r reviewerRecord
+		if err := json.Unmarshal(rec.Data, &r); err != nil {
+			return err
+		}
+		s.Reviewers = append(s.Reviewers, domain.PRReviewer{
+			PullRequestID: r.PullRequestID, UserID: r.UserID, AssignedAt: r.AssignedAt, RemindedAt: r.RemindedAt,
+		})
+	case typeHistory:
+		return appendDecoded(rec.Data, &s.History)
+	default:
+		return fmt.Errorf("unknown record type %q", rec.Type)
+	}
+	return nil
+}
+
+func appendDecoded[T any](data json.RawMessage, dst *[]T) error {
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*dst = append(*dst, v)
+	return nil
+}
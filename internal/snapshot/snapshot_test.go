@@ -0,0 +1,55 @@
+package snapshot
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundTrip(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	parent := 1
+	in := &domain.Snapshot{
+		Organizations: []domain.Organization{{ID: 1, Name: "default", APIKeyHash: "abc", CreatedAt: now}},
+		Teams:         []domain.Team{{ID: 1, OrgID: 1, Name: "Platform"}, {ID: 2, OrgID: 1, Name: "Backend", ParentID: &parent}},
+		Users: []domain.User{{
+			ID: 5, Name: "Alice", IsActive: true, Seniority: domain.SenioritySenior, Login: "alice",
+			ExpertiseTags: []string{"go"}, TeamID: 2, OrgID: 1, Team: &domain.Team{ID: 2},
+		}},
+		Memberships:  []domain.TeamMembership{{TeamID: 2, UserID: 5, CanAuthor: true, CanReview: true}},
+		Policies:     []domain.TeamPolicy{{TeamID: 2, MinReviewers: 1, MaxReviewers: 2, FallbackTeamIDs: []int{1}}},
+		PullRequests: []domain.PullRequest{{ID: 9, Title: "Fix", Status: domain.PRStatusOpen, AuthorID: 5, OrgID: 1, Labels: []string{"bug"}}},
+		Reviewers:    []domain.PRReviewer{{PullRequestID: 9, UserID: 5, AssignedAt: now, RemindedAt: &now}},
+		History:      []domain.PRHistoryEntry{{ID: 3, PullRequestID: 9, Event: domain.HistoryCreated, CreatedAt: now}},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, in, now))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 10, "header and one line per record")
+	assert.Contains(t, lines[0], `"version":1`)
+
+	out, err := Read(&buf)
+	assert.NoError(t, err)
+	// Загруженные связи в снимок не попадают
+	in.Users[0].Team = nil
+	assert.Equal(t, in, out)
+}
+
+func TestReadRejectsUnknownInput(t *testing.T) {
+	for name, input := range map[string]string{
+		"empty":        "",
+		"format":       `{"format":"other","version":1}`,
+		"version":      `{"format":"project-avito-snapshot","version":2}`,
+		"record type":  `{"format":"project-avito-snapshot","version":1}` + "\n" + `{"type":"event","data":{}}`,
+		"broken line":  `{"format":"project-avito-snapshot","version":1}` + "\n" + `{"type":"team","data":`,
+		"wrong fields": `{"format":"project-avito-snapshot","version":1}` + "\n" + `{"type":"team","data":{"id":"x"}}`,
+	} {
+		_, err := Read(strings.NewReader(input))
+		assert.ErrorIs(t, err, domain.ErrInvalidSnapshot, name)
+	}
+}
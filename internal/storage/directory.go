@@ -0,0 +1,171 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// --- Directory (импорт/экспорт команд и пользователей) ---
+
+// errDryRun откатывает транзакцию пробного импорта
+var errDryRun = errors.New("dry run")
+
+func (r *Repository) GetTeams(ctx context.Context) ([]domain.Team, error) {
+	var teams []domain.Team
+	err := r.scoped(ctx, "teams").Order("id").Find(&teams).Error
+	return teams, err
+}
+
+func (r *Repository) GetUsers(ctx context.Context) ([]domain.User, error) {
+	var users []domain.User
+	err := r.scoped(ctx, "users").Order("id").Find(&users).Error
+	return users, err
+}
+
+// ImportDirectory создает недостающие команды и создает или обновляет пользователей.
+// Ошибки уровня строки (архивная команда, неоднозначный пользователь) попадают в отчет, а не прерывают импорт,
+// но транзакция в этом случае откатывается, как и при dryRun.
+func (r *Repository) ImportDirectory(ctx context.Context, records []domain.DirectoryRecord, dryRun bool) ([]domain.ImportRowResult, error) {
+	orgID := orgForInsert(ctx)
+	results := make([]domain.ImportRowResult, len(records))
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		failed := false
+		for i := range records {
+			if err := importRecord(tx, orgID, &records[i], &results[i]); err != nil {
+				return err
+			}
+			failed = failed || results[i].Action == domain.ImportFailed
+		}
+		if dryRun || failed {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return results, nil
+}
+
+func importRecord(tx *gorm.DB, orgID int, rec *domain.DirectoryRecord, res *domain.ImportRowResult) error {
+	var team domain.Team
+	err := tx.Where("org_id = ? AND name = ?", orgID, rec.Team).First(&team).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		team = domain.Team{OrgID: orgID, Name: rec.Team}
+		if err := tx.Create(&team).Error; err != nil {
+			return err
+		}
+		res.TeamCreated = true
+	} else if err != nil {
+		return err
+	}
+	res.TeamID = team.ID
+
+	if rec.User == "" {
+		res.Action = domain.ImportUnchanged
+		if res.TeamCreated {
+			res.Action = domain.ImportCreated
+		}
+		return nil
+	}
+	if team.ArchivedAt != nil {
+		res.Action, res.Error = domain.ImportFailed, domain.ErrTeamArchived.Error()
+		return nil
+	}
+
+	// Поиск по естественному ключу; удаленные пользователи не сопоставляются
+	query := tx.Where("org_id = ? AND deleted_at IS NULL", orgID)
+	switch {
+	case rec.Login != "":
+		query = query.Where("login = ?", rec.Login)
+	case rec.Email != "":
+		query = query.Where("email = ?", rec.Email)
+	default:
+		query = query.Where("name = ? AND team_id = ?", rec.User, team.ID)
+	}
+	var matches []domain.User
+	if err := query.Limit(2).Find(&matches).Error; err != nil {
+		return err
+	}
+
+	switch len(matches) {
+	case 0:
+		user := domain.User{
+			Name:       rec.User,
+			IsActive:   rec.IsActive == nil || *rec.IsActive,
+			Seniority:  domain.SeniorityJunior,
+			Login:      rec.Login,
+			Email:      rec.Email,
+			ChatHandle: rec.ChatHandle,
+			TeamID:     team.ID,
+			OrgID:      orgID,
+		}
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		err := tx.Create(&domain.TeamMembership{TeamID: team.ID, UserID: user.ID, CanAuthor: true, CanReview: true}).Error
+		if err != nil {
+			return err
+		}
+		res.Action, res.UserID = domain.ImportCreated, user.ID
+		return nil
+	case 1:
+		res.UserID = matches[0].ID
+		return importUpdateUser(tx, &matches[0], team.ID, rec, res)
+	default:
+		res.Action, res.Error = domain.ImportFailed, "several users match this row"
+		return nil
+	}
+}
+
+// importUpdateUser обновляет заполненные поля строки; перевод в другую команду заменяет участие
+// в основной команде, как TransferUser. Открытые ревью деактивированных и переведенных
+// переназначает сервис после применения импорта (по Deactivated и PreviousTeamID в res).
+func importUpdateUser(tx *gorm.DB, user *domain.User, teamID int, rec *domain.DirectoryRecord, res *domain.ImportRowResult) error {
+	changes := make(map[string]interface{})
+	if rec.User != user.Name {
+		changes["name"] = rec.User
+	}
+	for column, pair := range map[string][2]string{
+		"login":       {rec.Login, user.Login},
+		"email":       {rec.Email, user.Email},
+		"chat_handle": {rec.ChatHandle, user.ChatHandle},
+	} {
+		if pair[0] != "" && pair[0] != pair[1] {
+			changes[column] = pair[0]
+		}
+	}
+	if rec.IsActive != nil && *rec.IsActive != user.IsActive {
+		changes["is_active"] = *rec.IsActive
+	}
+	if teamID != user.TeamID {
+		changes["team_id"] = teamID
+	}
+
+	if len(changes) == 0 {
+		res.Action = domain.ImportUnchanged
+		return nil
+	}
+	if err := tx.Model(&domain.User{}).Where("id = ?", user.ID).Updates(changes).Error; err != nil {
+		return err
+	}
+	res.Deactivated = user.IsActive && rec.IsActive != nil && !*rec.IsActive
+	if teamID != user.TeamID {
+		res.PreviousTeamID = user.TeamID
+		if err := tx.Delete(&domain.TeamMembership{}, "team_id = ? AND user_id = ?", user.TeamID, user.ID).Error; err != nil {
+			return err
+		}
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&domain.TeamMembership{TeamID: teamID, UserID: user.ID, CanAuthor: true, CanReview: true}).Error
+		if err != nil {
+			return err
+		}
+	}
+	res.Action = domain.ImportUpdated
+	return nil
+}
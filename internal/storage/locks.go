@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"database/sql/driver"
+	"log"
+	"sort"
+)
+
+// reviewerLockSpace - первый ключ двухключевых advisory-блокировок ревьюеров (второй - ID пользователя),
+// чтобы они не пересекались с одноключевой блокировкой выбора лидера
+const reviewerLockSpace = 7301
+
+// LockReviewers берет сессионные advisory-блокировки пользователей userIDs на выделенном соединении
+// и держит их до вызова unlock. ID блокируются по возрастанию, поэтому встречные вызовы не взаимоблокируются.
+func (r *Repository) LockReviewers(ctx context.Context, userIDs []int) (func(), error) {
+	ids := append([]int(nil), userIDs...)
+	sort.Ints(ids)
+	if len(ids) == 0 {
+		return func() {}, nil
+	}
+
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock := func() {
+		// Блокировки сессионные: соединение, на котором их не удалось снять, в пул не возвращаем
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock_all()"); err != nil {
+			log.Printf("Failed to release reviewer locks: %v", err)
+			conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}
+
+	for i, id := range ids {
+		if i > 0 && id == ids[i-1] {
+			continue
+		}
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1, $2)", reviewerLockSpace, id); err != nil {
+			unlock()
+			return nil, err
+		}
+	}
+	return unlock, nil
+}
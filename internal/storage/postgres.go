@@ -7,6 +7,7 @@ import (
 	"github.com/Shishlyannikovvv/project-avito/internal/domain"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func NewPostgresDB(host, user, password, dbname, port string) (*gorm.DB, error) {
@@ -18,12 +19,92 @@ func NewPostgresDB(host, user, password, dbname, port string) (*gorm.DB, error)
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// pr_reviewers хранит время назначения ревьюера, поэтому описываем join-таблицу явно
+	if err := db.SetupJoinTable(&domain.PullRequest{}, "Reviewers", &domain.PRReviewer{}); err != nil {
+		return nil, fmt.Errorf("failed to set up join table: %w", err)
+	}
+
 	// Автомиграция - создает таблицы на основе структур из domain/models.go
-	err = db.AutoMigrate(&domain.Team{}, &domain.User{}, &domain.PullRequest{})
+	err = db.AutoMigrate(
+		&domain.Organization{},
+		&domain.Team{},
+		&domain.User{},
+		&domain.TeamMembership{},
+		&domain.PullRequest{},
+		&domain.TeamPolicy{},
+		&domain.UserUnavailability{},
+		&domain.CodeOwnerRule{},
+		&domain.PRHistoryEntry{},
+		&domain.NotificationTemplate{},
+		&domain.TeamChatWebhook{},
+		&domain.Event{},
+		&domain.IdempotencyRecord{},
+		&rateLimitBucket{},
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
+	if err := migrateOrganizations(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate organizations: %w", err)
+	}
+	// Назначения, сделанные до появления assigned_at, отсчитываются от миграции: иначе первый же проход
+	// напоминаний счел бы их давними и эскалировал все разом
+	err = db.Exec("UPDATE pr_reviewers SET assigned_at = now() WHERE assigned_at IS NULL OR assigned_at < '1970-01-02'").Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate reviewer assignment times: %w", err)
+	}
+	// Пользователи, созданные до появления уровней, - junior: пустой уровень не проходит RequiredSeniority
+	err = db.Exec("UPDATE users SET seniority = ? WHERE seniority IS NULL OR seniority = ''", domain.SeniorityJunior).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate user seniority: %w", err)
+	}
+	// Пользователи, созданные до появления участия в нескольких командах, - участники основной команды
+	err = db.Exec("INSERT INTO team_memberships (team_id, user_id, can_author, can_review) " +
+		"SELECT team_id, id, true, true FROM users WHERE deleted_at IS NULL ON CONFLICT DO NOTHING").Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate team memberships: %w", err)
+	}
 
 	log.Println("Connected to PostgreSQL and ran migrations successfully")
 	return db, nil
 }
+
+// migrateOrganizations переводит базу без организаций на схему с организациями:
+// существующие данные принадлежат организации по умолчанию
+func migrateOrganizations(db *gorm.DB) error {
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&domain.Organization{ID: domain.DefaultOrgID, Name: "default"}).Error
+	if err != nil {
+		return err
+	}
+	// ID задан явно, поэтому последовательность нужно сдвинуть
+	err = db.Exec("SELECT setval(pg_get_serial_sequence('organizations', 'id'), GREATEST((SELECT MAX(id) FROM organizations), 1))").Error
+	if err != nil {
+		return err
+	}
+
+	// Раньше название команды было уникально глобально, теперь - в пределах организации
+	migrator := db.Migrator()
+	for _, name := range []string{"uni_teams_name", "idx_teams_name"} {
+		if migrator.HasConstraint(&domain.Team{}, name) {
+			if err := migrator.DropConstraint(&domain.Team{}, name); err != nil {
+				return err
+			}
+		}
+		if migrator.HasIndex(&domain.Team{}, name) {
+			if err := migrator.DropIndex(&domain.Team{}, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	// То же для ключей идемпотентности: первичный ключ (key) заменяется на (org_id, key)
+	return db.Exec(`DO $$
+BEGIN
+	IF (SELECT count(*) FROM information_schema.key_column_usage
+		WHERE table_name = 'idempotency_records' AND constraint_name = 'idempotency_records_pkey') = 1 THEN
+		ALTER TABLE idempotency_records DROP CONSTRAINT idempotency_records_pkey;
+		ALTER TABLE idempotency_records ADD PRIMARY KEY (org_id, key);
+	END IF;
+END $$`).Error
+}
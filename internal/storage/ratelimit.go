@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/ratelimit"
+	"gorm.io/gorm"
+)
+
+// rateLimitBucket - корзина token bucket в общей таблице
+type rateLimitBucket struct {
+	Key       string `gorm:"primaryKey"`
+	Tokens    float64
+	Allowed   bool      // итог последней попытки (нужен, чтобы вернуть его из того же UPDATE)
+	UpdatedAt time.Time `gorm:"index"`
+}
+
+// RateLimitStore - общее для всех реплик хранилище корзин в Postgres.
+// Пополнение и списание токена выполняются одним upsert, поэтому параллельные запросы не теряют списания.
+type RateLimitStore struct {
+	db *gorm.DB
+}
+
+func NewRateLimitStore(db *gorm.DB) *RateLimitStore {
+	return &RateLimitStore{db: db}
+}
+
+// Токены в существующей корзине после пополнения (в SET все выражения видят строку до изменения)
+const availableTokensSQL = `LEAST(CAST(@burst AS double precision), rate_limit_buckets.tokens +
+	GREATEST(CAST(EXTRACT(EPOCH FROM now() - rate_limit_buckets.updated_at) AS double precision), 0) * CAST(@rate AS double precision))`
+
+const takeTokenSQL = `
+INSERT INTO rate_limit_buckets (key, tokens, allowed, updated_at)
+VALUES (@key, CAST(@burst AS double precision) - 1, true, now())
+ON CONFLICT (key) DO UPDATE SET
+	tokens = CASE WHEN ` + availableTokensSQL + ` >= 1 THEN ` + availableTokensSQL + ` - 1 ELSE ` + availableTokensSQL + ` END,
+	allowed = ` + availableTokensSQL + ` >= 1,
+	updated_at = now()
+RETURNING tokens, allowed`
+
+func (s *RateLimitStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	var row struct {
+		Tokens  float64
+		Allowed bool
+	}
+	err := s.db.WithContext(ctx).Raw(takeTokenSQL, map[string]interface{}{
+		"key":   key,
+		"burst": float64(limit.Burst),
+		"rate":  limit.Rate,
+	}).Scan(&row).Error
+	if err != nil {
+		return ratelimit.Result{}, err
+	}
+	return ratelimit.ResultFor(limit, row.Tokens, row.Allowed), nil
+}
+
+// DeleteIdle удаляет корзины, не тронутые с before (они уже наполнились бы целиком)
+func (s *RateLimitStore) DeleteIdle(ctx context.Context, before time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).Where("updated_at < ?", before).Delete(&rateLimitBucket{})
+	return result.RowsAffected, result.Error
+}
@@ -2,9 +2,12 @@ package storage
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/Shishlyannikovvv/project-avito/internal/domain"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository struct {
@@ -15,15 +18,46 @@ func NewRepository(db *gorm.DB) *Repository {
 	return &Repository{db: db}
 }
 
+// --- Organization ---
+
+func (r *Repository) CreateOrganization(ctx context.Context, org *domain.Organization) error {
+	return r.db.WithContext(ctx).Create(org).Error
+}
+
+func (r *Repository) GetOrganizationByAPIKeyHash(ctx context.Context, hash string) (*domain.Organization, error) {
+	var org domain.Organization
+	err := r.db.WithContext(ctx).Where("api_key_hash = ?", hash).First(&org).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+	return &org, nil
+}
+
 // --- Team ---
 
 func (r *Repository) CreateTeam(ctx context.Context, team *domain.Team) error {
+	// Без ограничения по организации (импорт, фоновые задачи) сохраняется заданная организация
+	if team.OrgID == 0 || orgScope(ctx) != 0 {
+		team.OrgID = orgForInsert(ctx)
+	}
+	// Название уникально в организации; проверяем заранее, чтобы вернуть понятную ошибку
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.Team{}).Where("org_id = ? AND name = ?", team.OrgID, team.Name).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return domain.ErrTeamAlreadyExists
+	}
 	return r.db.WithContext(ctx).Create(team).Error
 }
 
 func (r *Repository) GetTeamByName(ctx context.Context, name string) (*domain.Team, error) {
 	var team domain.Team
-	err := r.db.WithContext(ctx).Where("name = ?", name).First(&team).Error
+	err := r.scoped(ctx, "teams").Where("name = ?", name).First(&team).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domain.ErrTeamNotFound
@@ -33,15 +67,237 @@ func (r *Repository) GetTeamByName(ctx context.Context, name string) (*domain.Te
 	return &team, nil
 }
 
+func (r *Repository) GetTeamByID(ctx context.Context, id int) (*domain.Team, error) {
+	var team domain.Team
+	err := r.scoped(ctx, "teams").First(&team, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrTeamNotFound
+		}
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (r *Repository) GetTeamsByIDs(ctx context.Context, ids []int) ([]domain.Team, error) {
+	var teams []domain.Team
+	err := r.scoped(ctx, "teams").Where("id IN ?", ids).Find(&teams).Error
+	return teams, err
+}
+
+func (r *Repository) RenameTeam(ctx context.Context, teamID int, name string) error {
+	orgID, err := r.orgOf(ctx, "teams", teamID, domain.ErrTeamNotFound)
+	if err != nil {
+		return err
+	}
+	var count int64
+	err = r.db.WithContext(ctx).Model(&domain.Team{}).Where("org_id = ? AND name = ? AND id <> ?", orgID, name, teamID).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return domain.ErrTeamAlreadyExists
+	}
+	return r.db.WithContext(ctx).Model(&domain.Team{}).Where("id = ?", teamID).Update("name", name).Error
+}
+
+func (r *Repository) ArchiveTeam(ctx context.Context, teamID int, at time.Time) error {
+	result := r.scoped(ctx, "teams").Model(&domain.Team{}).Where("id = ?", teamID).Update("archived_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrTeamNotFound
+	}
+	return nil
+}
+
+// --- Team Hierarchy ---
+
+func (r *Repository) GetChildTeams(ctx context.Context, parentID *int) ([]domain.Team, error) {
+	var teams []domain.Team
+	query := r.scoped(ctx, "teams")
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+	err := query.Order("id").Find(&teams).Error
+	return teams, err
+}
+
+func (r *Repository) SetTeamParent(ctx context.Context, teamID int, parentID *int) error {
+	result := r.scoped(ctx, "teams").Model(&domain.Team{}).Where("id = ?", teamID).Update("parent_id", parentID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrTeamNotFound
+	}
+	return nil
+}
+
+func (r *Repository) GetSubtreeTeamIDs(ctx context.Context, teamID int) ([]int, error) {
+	if _, err := r.orgOf(ctx, "teams", teamID, domain.ErrTeamNotFound); err != nil {
+		return nil, err
+	}
+	// UNION (а не UNION ALL) не даст зациклиться, даже если цикл попал в базу в обход MoveTeam
+	var ids []int
+	err := r.db.WithContext(ctx).Raw(`WITH RECURSIVE subtree AS (
+		SELECT id FROM teams WHERE id = ?
+		UNION
+		SELECT teams.id FROM teams JOIN subtree ON teams.parent_id = subtree.id
+	) SELECT id FROM subtree ORDER BY id`, teamID).Scan(&ids).Error
+	return ids, err
+}
+
+// --- Team Policy ---
+
+func (r *Repository) GetTeamPolicy(ctx context.Context, teamID int) (*domain.TeamPolicy, error) {
+	var policy domain.TeamPolicy
+	err := r.scopedVia(ctx, "team_id", "teams").Where("team_id = ?", teamID).First(&policy).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrTeamPolicyNotFound
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *Repository) SaveTeamPolicy(ctx context.Context, policy *domain.TeamPolicy) error {
+	if _, err := r.orgOf(ctx, "teams", policy.TeamID, domain.ErrTeamNotFound); err != nil {
+		return err
+	}
+	// TeamID - первичный ключ, поэтому Save работает как upsert
+	return r.db.WithContext(ctx).Save(policy).Error
+}
+
+// --- Code Owners ---
+
+func (r *Repository) GetCodeOwnerRules(ctx context.Context, teamID int) ([]domain.CodeOwnerRule, error) {
+	var rules []domain.CodeOwnerRule
+	err := r.scopedVia(ctx, "team_id", "teams").Where("team_id = ?", teamID).Order("position").Find(&rules).Error
+	return rules, err
+}
+
+func (r *Repository) ReplaceCodeOwnerRules(ctx context.Context, teamID int, rules []domain.CodeOwnerRule) error {
+	if _, err := r.orgOf(ctx, "teams", teamID, domain.ErrTeamNotFound); err != nil {
+		return err
+	}
+	// Файл CODEOWNERS загружается целиком, поэтому старые правила удаляем в той же транзакции
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("team_id = ?", teamID).Delete(&domain.CodeOwnerRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		return tx.Create(&rules).Error
+	})
+}
+
+// --- Notification Templates ---
+
+func (r *Repository) GetNotificationTemplate(ctx context.Context, teamID int, eventType string) (*domain.NotificationTemplate, error) {
+	var tpl domain.NotificationTemplate
+	err := r.scopedVia(ctx, "team_id", "teams").Where("team_id = ? AND event_type = ?", teamID, eventType).First(&tpl).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	return &tpl, nil
+}
+
+func (r *Repository) GetNotificationTemplates(ctx context.Context, teamID int) ([]domain.NotificationTemplate, error) {
+	var templates []domain.NotificationTemplate
+	err := r.scopedVia(ctx, "team_id", "teams").Where("team_id = ?", teamID).Order("event_type").Find(&templates).Error
+	return templates, err
+}
+
+func (r *Repository) SaveNotificationTemplate(ctx context.Context, tpl *domain.NotificationTemplate) error {
+	if _, err := r.orgOf(ctx, "teams", tpl.TeamID, domain.ErrTeamNotFound); err != nil {
+		return err
+	}
+	// Один шаблон на пару (команда, событие) - повторное сохранение перезаписывает текст
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "team_id"}, {Name: "event_type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"subject", "body"}),
+	}).Create(tpl).Error
+}
+
+func (r *Repository) DeleteNotificationTemplate(ctx context.Context, teamID int, eventType string) error {
+	result := r.scopedVia(ctx, "team_id", "teams").Where("team_id = ? AND event_type = ?", teamID, eventType).Delete(&domain.NotificationTemplate{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrTemplateNotFound
+	}
+	return nil
+}
+
+// --- Chat Webhooks ---
+
+func (r *Repository) GetTeamChatWebhook(ctx context.Context, teamID int) (*domain.TeamChatWebhook, error) {
+	var webhook domain.TeamChatWebhook
+	err := r.scopedVia(ctx, "team_id", "teams").Where("team_id = ?", teamID).First(&webhook).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrChatWebhookNotFound
+		}
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *Repository) GetTeamChatWebhooks(ctx context.Context) ([]domain.TeamChatWebhook, error) {
+	var webhooks []domain.TeamChatWebhook
+	err := r.scopedVia(ctx, "team_id", "teams").Order("team_id").Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *Repository) SaveTeamChatWebhook(ctx context.Context, webhook *domain.TeamChatWebhook) error {
+	if _, err := r.orgOf(ctx, "teams", webhook.TeamID, domain.ErrTeamNotFound); err != nil {
+		return err
+	}
+	// TeamID - первичный ключ, поэтому Save работает как upsert
+	return r.db.WithContext(ctx).Save(webhook).Error
+}
+
+func (r *Repository) DeleteTeamChatWebhook(ctx context.Context, teamID int) error {
+	result := r.scopedVia(ctx, "team_id", "teams").Delete(&domain.TeamChatWebhook{}, "team_id = ?", teamID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrChatWebhookNotFound
+	}
+	return nil
+}
+
 // --- User ---
 
 func (r *Repository) CreateUser(ctx context.Context, user *domain.User) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	orgID, err := r.orgOf(ctx, "teams", user.TeamID, domain.ErrTeamNotFound)
+	if err != nil {
+		return err
+	}
+	user.OrgID = orgID
+	// Пользователь сразу становится участником основной команды
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return tx.Create(&domain.TeamMembership{TeamID: user.TeamID, UserID: user.ID, CanAuthor: true, CanReview: true}).Error
+	})
 }
 
 func (r *Repository) GetUserByID(ctx context.Context, id int) (*domain.User, error) {
 	var user domain.User
-	err := r.db.WithContext(ctx).Preload("Team").First(&user, id).Error
+	err := r.scoped(ctx, "users").Preload("Team").First(&user, id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domain.ErrUserNotFound
@@ -51,9 +307,26 @@ func (r *Repository) GetUserByID(ctx context.Context, id int) (*domain.User, err
 	return &user, nil
 }
 
+func (r *Repository) GetUsersByIDs(ctx context.Context, ids []int) ([]domain.User, error) {
+	var users []domain.User
+	err := r.scoped(ctx, "users").Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
 func (r *Repository) DeactivateUser(ctx context.Context, id int) error {
 	// Обновляем поле IsActive на false
-	result := r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("is_active", false)
+	result := r.scoped(ctx, "users").Model(&domain.User{}).Where("id = ?", id).Update("is_active", false)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *Repository) ActivateUser(ctx context.Context, id int) error {
+	result := r.scoped(ctx, "users").Model(&domain.User{}).Where("id = ?", id).Update("is_active", true)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -63,23 +336,336 @@ func (r *Repository) DeactivateUser(ctx context.Context, id int) error {
 	return nil
 }
 
+func (r *Repository) SetUserName(ctx context.Context, id int, name string) error {
+	result := r.scoped(ctx, "users").Model(&domain.User{}).Where("id = ?", id).Update("name", name)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *Repository) TransferUser(ctx context.Context, id int, toTeamID int) error {
+	userOrg, err := r.orgOf(ctx, "users", id, domain.ErrUserNotFound)
+	if err != nil {
+		return err
+	}
+	teamOrg, err := r.orgOf(ctx, "teams", toTeamID, domain.ErrTeamNotFound)
+	if err != nil {
+		return err
+	}
+	if userOrg != teamOrg {
+		return domain.ErrTeamNotFound
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user domain.User
+		if err := tx.Select("id", "team_id").First(&user, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&domain.TeamMembership{}, "team_id = ? AND user_id = ?", user.TeamID, id).Error; err != nil {
+			return err
+		}
+		// Если пользователь уже состоял в новой команде, его флаги сохраняются
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&domain.TeamMembership{TeamID: toTeamID, UserID: id, CanAuthor: true, CanReview: true}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&domain.User{}).Where("id = ?", id).Update("team_id", toTeamID).Error
+	})
+}
+
+func (r *Repository) AnonymizeUser(ctx context.Context, id int, at time.Time) error {
+	if _, err := r.orgOf(ctx, "users", id, domain.ErrUserNotFound); err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&domain.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"name":           fmt.Sprintf("Deleted user %d", id),
+			"is_active":      false,
+			"login":          "",
+			"email":          "",
+			"chat_handle":    "",
+			"expertise_tags": "[]",
+			"notify_opt_out": "[]",
+			"deleted_at":     at,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&domain.TeamMembership{}, "user_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.UserUnavailability{}, "user_id = ?", id).Error
+	})
+}
+
+func (r *Repository) SetUserSeniority(ctx context.Context, id int, seniority string) error {
+	result := r.scoped(ctx, "users").Model(&domain.User{}).Where("id = ?", id).Update("seniority", seniority)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *Repository) SetUserMaxOpenReviews(ctx context.Context, id int, limit *int) error {
+	result := r.scoped(ctx, "users").Model(&domain.User{}).Where("id = ?", id).Update("max_open_reviews", limit)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *Repository) SetUserExpertise(ctx context.Context, id int, login string, tags []string) error {
+	result := r.scoped(ctx, "users").Model(&domain.User{ID: id}).Select("login", "expertise_tags").Updates(&domain.User{
+		Login:         login,
+		ExpertiseTags: tags,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *Repository) SetUserNotifications(ctx context.Context, id int, email string, optOut []string) error {
+	result := r.scoped(ctx, "users").Model(&domain.User{ID: id}).Select("email", "notify_opt_out").Updates(&domain.User{
+		Email:        email,
+		NotifyOptOut: optOut,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *Repository) SetUserChatHandle(ctx context.Context, id int, handle string) error {
+	result := r.scoped(ctx, "users").Model(&domain.User{ID: id}).Update("chat_handle", handle)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *Repository) GetUsersByLogins(ctx context.Context, logins []string) ([]domain.User, error) {
+	var users []domain.User
+	if len(logins) == 0 {
+		return users, nil
+	}
+	err := r.scoped(ctx, "users").Where("login IN ?", logins).Find(&users).Error
+	return users, err
+}
+
 func (r *Repository) GetUsersByTeam(ctx context.Context, teamID int) ([]domain.User, error) {
 	var users []domain.User
 	// Нам нужны только активные пользователи для назначения ревью
-	err := r.db.WithContext(ctx).Where("team_id = ? AND is_active = ?", teamID, true).Find(&users).Error
+	err := r.scoped(ctx, "users").
+		Joins("JOIN team_memberships ON team_memberships.user_id = users.id").
+		Where("team_memberships.team_id = ? AND users.is_active = ?", teamID, true).
+		Order("users.id").
+		Find(&users).Error
+	return users, err
+}
+
+func (r *Repository) GetTeamReviewers(ctx context.Context, teamID int) ([]domain.User, error) {
+	var users []domain.User
+	err := r.scoped(ctx, "users").
+		Joins("JOIN team_memberships ON team_memberships.user_id = users.id").
+		Joins("JOIN teams ON teams.id = team_memberships.team_id").
+		Where("team_memberships.team_id = ? AND team_memberships.can_review AND users.is_active = ?", teamID, true).
+		Where("teams.archived_at IS NULL").
+		Order("users.id").
+		Find(&users).Error
 	return users, err
 }
 
+func (r *Repository) GetMembersOfTeams(ctx context.Context, teamIDs []int) ([]domain.TeamMembership, error) {
+	var memberships []domain.TeamMembership
+	err := r.scopedVia(ctx, "team_id", "teams").
+		Preload("User").
+		Where("team_id IN ?", teamIDs).
+		Order("team_id, user_id").
+		Find(&memberships).Error
+	return memberships, err
+}
+
+// --- Team Membership ---
+
+func (r *Repository) GetUserMemberships(ctx context.Context, userID int) ([]domain.TeamMembership, error) {
+	var memberships []domain.TeamMembership
+	err := r.scopedVia(ctx, "user_id", "users").Where("user_id = ?", userID).Order("team_id").Find(&memberships).Error
+	return memberships, err
+}
+
+func (r *Repository) SaveTeamMembership(ctx context.Context, membership *domain.TeamMembership) error {
+	teamOrg, err := r.orgOf(ctx, "teams", membership.TeamID, domain.ErrTeamNotFound)
+	if err != nil {
+		return err
+	}
+	userOrg, err := r.orgOf(ctx, "users", membership.UserID, domain.ErrUserNotFound)
+	if err != nil {
+		return err
+	}
+	// Без ограничения по организации (фоновые задачи) проверяем, что команда и пользователь из одной
+	if teamOrg != userOrg {
+		return domain.ErrTeamNotFound
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "team_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"can_author", "can_review"}),
+	}).Omit("User").Create(membership).Error
+}
+
+func (r *Repository) DeleteTeamMembership(ctx context.Context, teamID int, userID int) error {
+	result := r.scopedVia(ctx, "team_id", "teams").Delete(&domain.TeamMembership{}, "team_id = ? AND user_id = ?", teamID, userID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrMembershipNotFound
+	}
+	return nil
+}
+
+func (r *Repository) SetUserPrimaryTeam(ctx context.Context, userID int, teamID int) error {
+	result := r.scoped(ctx, "users").Model(&domain.User{}).
+		Where("id = ? AND EXISTS (SELECT 1 FROM team_memberships WHERE user_id = users.id AND team_id = ?)", userID, teamID).
+		Update("team_id", teamID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if _, err := r.orgOf(ctx, "users", userID, domain.ErrUserNotFound); err != nil {
+			return err
+		}
+		return domain.ErrMembershipNotFound
+	}
+	return nil
+}
+
+// --- User Unavailability ---
+
+func (r *Repository) CreateUnavailability(ctx context.Context, window *domain.UserUnavailability) error {
+	if _, err := r.orgOf(ctx, "users", window.UserID, domain.ErrUserNotFound); err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Create(window).Error
+}
+
+func (r *Repository) GetUnavailabilityByID(ctx context.Context, id int) (*domain.UserUnavailability, error) {
+	var window domain.UserUnavailability
+	err := r.scopedVia(ctx, "user_id", "users").First(&window, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrUnavailabilityNotFound
+		}
+		return nil, err
+	}
+	return &window, nil
+}
+
+func (r *Repository) GetUnavailabilityByUser(ctx context.Context, userID int) ([]domain.UserUnavailability, error) {
+	var windows []domain.UserUnavailability
+	err := r.scopedVia(ctx, "user_id", "users").Where("user_id = ?", userID).Order("starts_at").Find(&windows).Error
+	return windows, err
+}
+
+func (r *Repository) UpdateUnavailability(ctx context.Context, window *domain.UserUnavailability) error {
+	// Save вставляет запись, если обновлять нечего, поэтому видимость окна проверяем отдельно
+	var count int64
+	err := r.scopedVia(ctx, "user_id", "users").Model(&domain.UserUnavailability{}).Where("id = ?", window.ID).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return domain.ErrUnavailabilityNotFound
+	}
+	return r.db.WithContext(ctx).Save(window).Error
+}
+
+func (r *Repository) DeleteUnavailability(ctx context.Context, id int) error {
+	result := r.scopedVia(ctx, "user_id", "users").Delete(&domain.UserUnavailability{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUnavailabilityNotFound
+	}
+	return nil
+}
+
+func (r *Repository) GetUnavailableUserIDs(ctx context.Context, userIDs []int, at time.Time) (map[int]bool, error) {
+	unavailable := make(map[int]bool)
+	if len(userIDs) == 0 {
+		return unavailable, nil
+	}
+
+	var ids []int
+	err := r.scopedVia(ctx, "user_id", "users").
+		Model(&domain.UserUnavailability{}).
+		Where("user_id IN ? AND starts_at <= ? AND ends_at > ?", userIDs, at, at).
+		Distinct().
+		Pluck("user_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		unavailable[id] = true
+	}
+	return unavailable, nil
+}
+
+func (r *Repository) GetPendingUnavailabilityStarts(ctx context.Context, at time.Time) ([]domain.UserUnavailability, error) {
+	var windows []domain.UserUnavailability
+	// Берем только еще не закончившиеся окна: переназначать ревью после возвращения нет смысла
+	err := r.scopedVia(ctx, "user_id", "users").
+		Where("reassigned_at IS NULL AND starts_at <= ? AND ends_at > ?", at, at).
+		Order("starts_at").
+		Find(&windows).Error
+	return windows, err
+}
+
+func (r *Repository) MarkUnavailabilityReassigned(ctx context.Context, id int, at time.Time) error {
+	return r.scopedVia(ctx, "user_id", "users").
+		Model(&domain.UserUnavailability{}).
+		Where("id = ?", id).
+		Update("reassigned_at", at).Error
+}
+
 // --- Pull Request ---
 
 func (r *Repository) CreatePR(ctx context.Context, pr *domain.PullRequest) error {
+	orgID, err := r.orgOf(ctx, "users", pr.AuthorID, domain.ErrUserNotFound)
+	if err != nil {
+		return err
+	}
+	pr.OrgID = orgID
 	return r.db.WithContext(ctx).Create(pr).Error
 }
 
 func (r *Repository) GetPRByID(ctx context.Context, id int) (*domain.PullRequest, error) {
 	var pr domain.PullRequest
 	// Preload загружает связанные сущности (Автора и Список ревьюеров)
-	err := r.db.WithContext(ctx).
+	err := r.scoped(ctx, "pull_requests").
 		Preload("Author").
 		Preload("Reviewers").
 		First(&pr, id).Error
@@ -93,16 +679,104 @@ func (r *Repository) GetPRByID(ctx context.Context, id int) (*domain.PullRequest
 	return &pr, nil
 }
 
-func (r *Repository) UpdatePR(ctx context.Context, pr *domain.PullRequest) error {
-	// Save сохраняет все поля, включая обновленные ассоциации (Reviewers)
-	return r.db.WithContext(ctx).Save(pr).Error
+// UpdateOpenPR перечитывает PR под SELECT ... FOR UPDATE и применяет к нему change в той же транзакции.
+// Пишутся только измененные колонки (UPDATE ... WHERE status = 'OPEN') и строки pr_reviewers
+// добавленных/снятых ревьюеров, так что параллельные изменения PR не затирают друг друга.
+func (r *Repository) UpdateOpenPR(ctx context.Context, id int, change func(pr *domain.PullRequest) error) (*domain.PullRequest, error) {
+	var pr domain.PullRequest
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		locked := tx.Model(&domain.PullRequest{}).Clauses(clause.Locking{Strength: "UPDATE"})
+		if orgID := orgScope(ctx); orgID != 0 {
+			locked = locked.Where("org_id = ?", orgID)
+		}
+		var statuses []string
+		if err := locked.Where("id = ?", id).Pluck("status", &statuses).Error; err != nil {
+			return err
+		}
+		if len(statuses) == 0 {
+			return domain.ErrPRNotFound
+		}
+		switch statuses[0] {
+		case domain.PRStatusMerged:
+			return domain.ErrPRAlreadyMerged
+		case domain.PRStatusClosed:
+			return domain.ErrPRClosed
+		}
+
+		if err := tx.Preload("Author").Preload("Reviewers").First(&pr, id).Error; err != nil {
+			return err
+		}
+		waitingSince := pr.WaitingSince
+		before := make(map[int]bool, len(pr.Reviewers))
+		for _, u := range pr.Reviewers {
+			before[u.ID] = true
+		}
+
+		if err := change(&pr); err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{}
+		if pr.Status != domain.PRStatusOpen {
+			updates["status"] = pr.Status
+		}
+		if !sameTime(waitingSince, pr.WaitingSince) {
+			updates["waiting_since"] = pr.WaitingSince
+		}
+		if len(updates) > 0 {
+			res := tx.Model(&domain.PullRequest{}).
+				Where("id = ? AND status = ?", id, domain.PRStatusOpen).
+				Updates(updates)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return domain.ErrPRAlreadyMerged
+			}
+		}
+
+		var added []domain.PRReviewer
+		for _, u := range pr.Reviewers {
+			if before[u.ID] {
+				delete(before, u.ID)
+				continue
+			}
+			added = append(added, domain.PRReviewer{PullRequestID: id, UserID: u.ID})
+		}
+		if len(before) > 0 {
+			removed := make([]int, 0, len(before))
+			for userID := range before {
+				removed = append(removed, userID)
+			}
+			err := tx.Where("pull_request_id = ? AND user_id IN ?", id, removed).Delete(&domain.PRReviewer{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		if len(added) > 0 {
+			return tx.Create(&added).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// sameTime - равны ли два необязательных момента времени
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
 }
 
 func (r *Repository) GetPRsByReviewer(ctx context.Context, reviewerID int) ([]domain.PullRequest, error) {
 	var prs []domain.PullRequest
 	// Сложный запрос: найти PR, где в списке ревьюеров есть наш юзер
 	// Используем JOIN таблицу pr_reviewers, которую GORM создал автоматически
-	err := r.db.WithContext(ctx).
+	err := r.scoped(ctx, "pull_requests").
 		Preload("Author").
 		Preload("Reviewers").
 		Joins("JOIN pr_reviewers ON pr_reviewers.pull_request_id = pull_requests.id").
@@ -112,6 +786,249 @@ func (r *Repository) GetPRsByReviewer(ctx context.Context, reviewerID int) ([]do
 	return prs, err
 }
 
+func (r *Repository) GetOpenPRsByTeam(ctx context.Context, teamID int) ([]domain.PullRequest, error) {
+	var prs []domain.PullRequest
+	err := r.scoped(ctx, "pull_requests").
+		Preload("Author").
+		Preload("Reviewers").
+		Joins("JOIN users ON users.id = pull_requests.author_id").
+		Where("users.team_id = ? AND pull_requests.status = ?", teamID, domain.PRStatusOpen).
+		Order("pull_requests.id").
+		Find(&prs).Error
+	return prs, err
+}
+
+func (r *Repository) GetOpenPRsByTeams(ctx context.Context, teamIDs []int) ([]domain.PullRequest, error) {
+	var prs []domain.PullRequest
+	err := r.scoped(ctx, "pull_requests").
+		Preload("Author").
+		Preload("Reviewers").
+		Joins("JOIN users ON users.id = pull_requests.author_id").
+		Where("users.team_id IN ? AND pull_requests.status = ?", teamIDs, domain.PRStatusOpen).
+		Order("pull_requests.id").
+		Find(&prs).Error
+	return prs, err
+}
+
+func (r *Repository) GetOpenPRsByReviewers(ctx context.Context, reviewerIDs []int) ([]domain.PullRequest, error) {
+	var prs []domain.PullRequest
+	err := r.scoped(ctx, "pull_requests").
+		Preload("Author").
+		Preload("Reviewers").
+		Where("status = ? AND id IN (?)", domain.PRStatusOpen,
+			r.db.Table("pr_reviewers").Select("pull_request_id").Where("user_id IN ?", reviewerIDs)).
+		Order("id").
+		Find(&prs).Error
+	return prs, err
+}
+
+func (r *Repository) GetOpenReviewCounts(ctx context.Context, userIDs []int) (map[int]int, error) {
+	counts := make(map[int]int)
+	if len(userIDs) == 0 {
+		return counts, nil
+	}
+
+	var results []struct {
+		UserID int
+		Count  int64
+	}
+	err := r.scoped(ctx, "pull_requests").
+		Model(&domain.PullRequest{}).
+		Select("pr_reviewers.user_id, count(pull_request_id) as count").
+		Joins("JOIN pr_reviewers ON pr_reviewers.pull_request_id = pull_requests.id").
+		Where("pull_requests.status = ? AND pr_reviewers.user_id IN ?", domain.PRStatusOpen, userIDs).
+		Group("pr_reviewers.user_id").
+		Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, res := range results {
+		counts[res.UserID] = int(res.Count)
+	}
+	return counts, nil
+}
+
+func (r *Repository) GetOpenAssignments(ctx context.Context) ([]domain.ReviewAssignment, error) {
+	var assignments []domain.ReviewAssignment
+	err := r.scoped(ctx, "pull_requests").
+		Table("pr_reviewers").
+		Select("pr_reviewers.pull_request_id AS pr_id, pr_reviewers.user_id AS reviewer_id, "+
+			"users.team_id AS author_team_id, pull_requests.org_id, pr_reviewers.assigned_at, pr_reviewers.reminded_at").
+		Joins("JOIN pull_requests ON pull_requests.id = pr_reviewers.pull_request_id").
+		Joins("JOIN users ON users.id = pull_requests.author_id").
+		Where("pull_requests.status = ?", domain.PRStatusOpen).
+		Order("pr_reviewers.assigned_at").
+		Scan(&assignments).Error
+	return assignments, err
+}
+
+func (r *Repository) MarkReviewReminded(ctx context.Context, prID int, reviewerID int, at time.Time) error {
+	return r.scopedVia(ctx, "pull_request_id", "pull_requests").
+		Model(&domain.PRReviewer{}).
+		Where("pull_request_id = ? AND user_id = ?", prID, reviewerID).
+		Update("reminded_at", at).Error
+}
+
+// --- Events ---
+
+func (r *Repository) AddEvent(ctx context.Context, event *domain.Event) error {
+	// Событие принадлежит организации команды автора PR
+	if event.OrgID == 0 {
+		event.OrgID = orgForInsert(ctx)
+		if event.TeamID != 0 {
+			if orgID, err := r.orgOf(ctx, "teams", event.TeamID, domain.ErrTeamNotFound); err == nil {
+				event.OrgID = orgID
+			}
+		}
+	}
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *Repository) GetEventsAfter(ctx context.Context, afterID int64, filter domain.EventFilter, limit int) ([]domain.Event, error) {
+	query := r.scoped(ctx, "events").Where("id > ?", afterID)
+	if filter.TeamID != 0 {
+		query = query.Where("team_id = ?", filter.TeamID)
+	}
+	if filter.UserID != 0 {
+		// События PR в целом (user_id = 0) относятся ко всем его ревьюерам
+		query = query.Where("user_id = ? OR (user_id = 0 AND pr_id IN (?))", filter.UserID,
+			r.db.Table("pr_reviewers").Select("pull_request_id").Where("user_id = ?", filter.UserID))
+	}
+
+	var events []domain.Event
+	err := query.Order("id").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+func (r *Repository) GetLatestEventID(ctx context.Context) (int64, error) {
+	var id int64
+	err := r.scoped(ctx, "events").Model(&domain.Event{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error
+	return id, err
+}
+
+func (r *Repository) DeleteEventsBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.scoped(ctx, "events").Where("at < ?", before).Delete(&domain.Event{})
+	return result.RowsAffected, result.Error
+}
+
+// --- Idempotency Keys ---
+
+// Сколько ключ остается занятым незавершенным запросом; заметно дольше любого обычного запроса
+const idempotencyPendingLease = 5 * time.Minute
+
+func (r *Repository) CreateIdempotencyRecord(ctx context.Context, record *domain.IdempotencyRecord) (bool, error) {
+	record.OrgID = orgForInsert(ctx)
+	// Истекший ключ можно занять заново, как и ключ, запрос по которому не завершился за idempotencyPendingLease
+	// (процесс упал, не сохранив ответ и не освободив ключ)
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "org_id"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"request_hash", "status_code", "content_type", "body", "created_at", "expires_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{gorm.Expr(
+			"idempotency_records.expires_at <= ? OR (idempotency_records.status_code = 0 AND idempotency_records.created_at <= ?)",
+			record.CreatedAt, record.CreatedAt.Add(-idempotencyPendingLease))}},
+	}).Create(record)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *Repository) GetIdempotencyRecord(ctx context.Context, key string) (*domain.IdempotencyRecord, error) {
+	var record domain.IdempotencyRecord
+	err := r.scoped(ctx, "idempotency_records").Where("key = ?", key).First(&record).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrIdempotencyKeyNotFound
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *Repository) CompleteIdempotencyRecord(ctx context.Context, record *domain.IdempotencyRecord) error {
+	result := r.scoped(ctx, "idempotency_records").Model(&domain.IdempotencyRecord{}).Where("key = ?", record.Key).
+		Updates(map[string]interface{}{
+			"status_code":  record.StatusCode,
+			"content_type": record.ContentType,
+			"body":         record.Body,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrIdempotencyKeyNotFound
+	}
+	return nil
+}
+
+func (r *Repository) DeleteIdempotencyRecord(ctx context.Context, key string) error {
+	return r.scoped(ctx, "idempotency_records").Where("key = ?", key).Delete(&domain.IdempotencyRecord{}).Error
+}
+
+func (r *Repository) DeleteExpiredIdempotencyRecords(ctx context.Context, now time.Time) (int64, error) {
+	result := r.scoped(ctx, "idempotency_records").Where("expires_at <= ?", now).Delete(&domain.IdempotencyRecord{})
+	return result.RowsAffected, result.Error
+}
+
+// --- PR History ---
+
+func (r *Repository) AddPRHistory(ctx context.Context, entry *domain.PRHistoryEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *Repository) GetPRHistory(ctx context.Context, prID int) ([]domain.PRHistoryEntry, error) {
+	var entries []domain.PRHistoryEntry
+	err := r.scopedVia(ctx, "pull_request_id", "pull_requests").Where("pull_request_id = ?", prID).Order("created_at, id").Find(&entries).Error
+	return entries, err
+}
+
+func (r *Repository) GetWaitingPRs(ctx context.Context) ([]domain.PullRequest, error) {
+	var prs []domain.PullRequest
+	err := r.scoped(ctx, "pull_requests").
+		Preload("Author").
+		Preload("Reviewers").
+		Where("waiting_since IS NOT NULL AND status = ?", domain.PRStatusOpen).
+		Order("waiting_since, id").
+		Find(&prs).Error
+	return prs, err
+}
+
+func (r *Repository) CountWaitingPRsAhead(ctx context.Context, pr *domain.PullRequest, teamIDs []int) (int, error) {
+	var count int64
+	err := r.scoped(ctx, "pull_requests").
+		Model(&domain.PullRequest{}).
+		Where("waiting_since IS NOT NULL AND status = ?", domain.PRStatusOpen).
+		Where("author_id IN (?)", r.db.Table("users").Select("id").Where("team_id IN ?", teamIDs)).
+		Where("waiting_since < ? OR (waiting_since = ? AND id < ?)", pr.WaitingSince, pr.WaitingSince, pr.ID).
+		Count(&count).Error
+	return int(count), err
+}
+
+// GetReviewerStatsForTeams - GetReviewerStats по ревьюерам, состоящим в командах teamIDs
+func (r *Repository) GetReviewerStatsForTeams(ctx context.Context, teamIDs []int) (map[int]int, error) {
+	var results []struct {
+		UserID int
+		Count  int64
+	}
+	err := r.scoped(ctx, "pull_requests").
+		Model(&domain.PullRequest{}).
+		Select("pr_reviewers.user_id, count(pull_request_id) as count").
+		Joins("JOIN pr_reviewers ON pr_reviewers.pull_request_id = pull_requests.id").
+		Where("EXISTS (SELECT 1 FROM team_memberships WHERE team_memberships.user_id = pr_reviewers.user_id AND team_memberships.team_id IN ?)", teamIDs).
+		Group("pr_reviewers.user_id").
+		Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[int]int)
+	for _, res := range results {
+		stats[res.UserID] = int(res.Count)
+	}
+	return stats, nil
+}
+
 // GetReviewerStats подсчитывает, сколько PR назначено каждому пользователю
 func (r *Repository) GetReviewerStats(ctx context.Context) (map[int]int, error) {
 	var results []struct {
@@ -120,7 +1037,7 @@ func (r *Repository) GetReviewerStats(ctx context.Context) (map[int]int, error)
 	}
 
 	// Запрос к join-таблице many2many pr_reviewers
-	err := r.db.WithContext(ctx).
+	err := r.scoped(ctx, "pull_requests").
 		Model(&domain.PullRequest{}).
 		Select("pr_reviewers.user_id, count(pull_request_id) as count").
 		Joins("JOIN pr_reviewers ON pr_reviewers.pull_request_id = pull_requests.id").
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// --- Snapshot (резервная копия всех организаций) ---
+
+// Таблицы с автоинкрементным id: после восстановления с явными ID их последовательности сдвигаются
+var snapshotSequenceTables = []string{
+	"organizations", "teams", "users", "code_owner_rules", "notification_templates",
+	"user_unavailabilities", "pull_requests", "pr_history_entries",
+}
+
+// ExportSnapshot читает все данные без ограничения организацией
+func (r *Repository) ExportSnapshot(ctx context.Context) (*domain.Snapshot, error) {
+	db := r.db.WithContext(ctx)
+	var s domain.Snapshot
+	steps := []struct {
+		dest  interface{}
+		order string
+	}{
+		{&s.Organizations, "id"},
+		{&s.Teams, "id"},
+		{&s.Users, "id"},
+		{&s.Memberships, "team_id, user_id"},
+		{&s.Policies, "team_id"},
+		{&s.CodeOwnerRules, "id"},
+		{&s.Templates, "id"},
+		{&s.ChatWebhooks, "team_id"},
+		{&s.Unavailability, "id"},
+		{&s.PullRequests, "id"},
+		{&s.Reviewers, "pull_request_id, user_id"},
+		{&s.History, "id"},
+	}
+	for _, step := range steps {
+		if err := db.Order(step.order).Find(step.dest).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &s, nil
+}
+
+// ImportSnapshot восстанавливает снимок в пустую базу (допускается только организация по умолчанию,
+// которую создает миграция) с исходными ID
+func (r *Repository) ImportSnapshot(ctx context.Context, s *domain.Snapshot) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Raw("SELECT (SELECT count(*) FROM teams) + (SELECT count(*) FROM users) + "+
+			"(SELECT count(*) FROM pull_requests) + (SELECT count(*) FROM organizations WHERE id <> ?)", domain.DefaultOrgID).
+			Scan(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return domain.ErrSnapshotNotEmpty
+		}
+
+		// Организация по умолчанию уже есть - перезаписываем ее данными снимка
+		if len(s.Organizations) > 0 {
+			if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&s.Organizations).Error; err != nil {
+				return err
+			}
+		}
+		// Порядок важен: внешние ключи ссылаются на ранее вставленные записи
+		batches := []interface{}{
+			&s.Teams, &s.Users, &s.Memberships, &s.Policies, &s.CodeOwnerRules, &s.Templates, &s.ChatWebhooks,
+			&s.Unavailability, &s.PullRequests, &s.Reviewers, &s.History,
+		}
+		for _, rows := range batches {
+			if reflect.ValueOf(rows).Elem().Len() == 0 {
+				continue
+			}
+			// Записи вставляются как есть, связанные записи восстанавливаются своими пачками
+			if err := tx.Omit(clause.Associations).CreateInBatches(rows, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, table := range snapshotSequenceTables {
+			query := fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%[1]s', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM %[1]s", table)
+			if err := tx.Exec(query).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
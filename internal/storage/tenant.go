@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"gorm.io/gorm"
+)
+
+// --- Ограничение запросов организацией ---
+
+// orgScope возвращает организацию контекста (0 - контекст не ограничен, см. domain.WithAllOrgs)
+func orgScope(ctx context.Context) int {
+	orgID, ok := domain.OrgIDFromContext(ctx)
+	if !ok {
+		return 0
+	}
+	return orgID
+}
+
+// scoped - запрос к таблице table с колонкой org_id в пределах организации контекста
+func (r *Repository) scoped(ctx context.Context, table string) *gorm.DB {
+	db := r.db.WithContext(ctx)
+	if orgID := orgScope(ctx); orgID != 0 {
+		db = db.Where(table+".org_id = ?", orgID)
+	}
+	return db
+}
+
+// scopedVia - запрос к таблице без org_id, которая ссылается колонкой column на таблицу parent с org_id
+// (например, team_policies.team_id -> teams)
+func (r *Repository) scopedVia(ctx context.Context, column, parent string) *gorm.DB {
+	db := r.db.WithContext(ctx)
+	if orgID := orgScope(ctx); orgID != 0 {
+		db = db.Where(column+" IN (?)", r.db.Table(parent).Select("id").Where("org_id = ?", orgID))
+	}
+	return db
+}
+
+// orgOf возвращает организацию записи id таблицы table; запись чужой организации не видна (notFound)
+func (r *Repository) orgOf(ctx context.Context, table string, id int, notFound error) (int, error) {
+	var orgIDs []int
+	err := r.scoped(ctx, table).Table(table).Where("id = ?", id).Pluck("org_id", &orgIDs).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(orgIDs) == 0 {
+		return 0, notFound
+	}
+	return orgIDs[0], nil
+}
+
+// orgForInsert - организация новой записи, не привязанной к команде или пользователю
+func orgForInsert(ctx context.Context) int {
+	if orgID := orgScope(ctx); orgID != 0 {
+		return orgID
+	}
+	return domain.DefaultOrgID
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// sqlRecorder - логгер GORM, запоминающий выполненные запросы
+type sqlRecorder struct {
+	logger.Interface
+	mu      sync.Mutex
+	queries []string
+}
+
+func (l *sqlRecorder) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	sql, _ := fc()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.queries = append(l.queries, sql)
+}
+
+func (l *sqlRecorder) last() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.queries) == 0 {
+		return ""
+	}
+	return l.queries[len(l.queries)-1]
+}
+
+// newDryRunRepository - репозиторий, который строит SQL, но не обращается к базе
+func newDryRunRepository(t *testing.T) (*Repository, *sqlRecorder) {
+	rec := &sqlRecorder{Interface: logger.Discard}
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=dry_run"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		// Изменения иначе открывают транзакцию, а с ней и соединение
+		SkipDefaultTransaction: true,
+		Logger:                 rec,
+	})
+	assert.NoError(t, err)
+	return NewRepository(db), rec
+}
+
+func TestQueriesAreScopedToOrg(t *testing.T) {
+	repo, rec := newDryRunRepository(t)
+	ctx := domain.WithOrgID(context.Background(), 5)
+
+	_, _ = repo.GetPRByID(ctx, 10)
+	assert.Contains(t, rec.last(), "pull_requests.org_id = 5")
+
+	_, _ = repo.GetUserByID(ctx, 10)
+	assert.Contains(t, rec.last(), "users.org_id = 5")
+
+	_, _ = repo.GetTeamReviewers(ctx, 3)
+	assert.Contains(t, rec.last(), "users.org_id = 5")
+
+	_, _ = repo.DeleteEventsBefore(ctx, time.Now())
+	assert.Contains(t, rec.last(), "events.org_id = 5")
+
+	// Таблицы без org_id ограничиваются через родительскую запись
+	_, _ = repo.GetTeamPolicy(ctx, 3)
+	assert.Contains(t, rec.last(), `team_id IN (SELECT id FROM "teams" WHERE org_id = 5)`)
+
+	_, _ = repo.GetPRHistory(ctx, 10)
+	assert.Contains(t, rec.last(), `pull_request_id IN (SELECT id FROM "pull_requests" WHERE org_id = 5)`)
+}
+
+func TestQueriesWithoutOrg(t *testing.T) {
+	repo, rec := newDryRunRepository(t)
+
+	// Запрос без API-ключа (AUTH_REQUIRED=false) работает с организацией по умолчанию
+	_, _ = repo.GetPRByID(context.Background(), 10)
+	assert.Contains(t, rec.last(), "pull_requests.org_id = 1")
+
+	// Фоновые задачи видят все организации
+	_, _ = repo.GetPRByID(domain.WithAllOrgs(context.Background()), 10)
+	assert.NotContains(t, rec.last(), "org_id")
+	_, _ = repo.GetTeamPolicy(domain.WithAllOrgs(context.Background()), 3)
+	assert.NotContains(t, rec.last(), "org_id")
+}
@@ -0,0 +1,260 @@
+// Package client - типизированный клиент REST API сервиса назначения ревьюеров (/api/v1).
+//
+// Ошибки API возвращаются как *APIError; errors.Is сопоставляет их с ошибками пакета domain
+// (domain.ErrPRAlreadyMerged, domain.ErrTeamNotFound и т.д.). Запросы повторяются с экспоненциальной
+// задержкой при сетевых ошибках, 5xx и 429; POST-запросы повторяются с тем же Idempotency-Key.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	mathrand "math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Значения Config по умолчанию
+const (
+	DefaultMaxRetries = 3
+	DefaultMinBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff = 5 * time.Second
+	defaultTimeout    = 30 * time.Second
+)
+
+// Заголовки идемпотентности (см. api.Idempotency)
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// Config - параметры клиента
+type Config struct {
+	// Адрес сервиса без /api/v1, например http://localhost:8080
+	BaseURL string
+	// API-ключ организации (пусто - без авторизации)
+	APIKey string
+	// Токен администратора установки для CreateOrganization и снимков
+	AdminToken string
+	// nil - http.Client с таймаутом 30 секунд
+	HTTPClient *http.Client
+	// Число повторов после первой попытки: 0 - DefaultMaxRetries, отрицательное - без повторов
+	MaxRetries int
+	// Границы задержки между повторами (0 - значения по умолчанию)
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// Client - клиент REST API; безопасен для одновременного использования
+type Client struct {
+	baseURL string
+	cfg     Config
+	http    *http.Client
+}
+
+func New(cfg Config) *Client {
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = DefaultMinBackoff
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = max(DefaultMaxBackoff, cfg.MinBackoff)
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultTimeout}
+	}
+	return &Client{
+		baseURL: strings.TrimRight(cfg.BaseURL, "/") + "/api/v1",
+		cfg:     cfg,
+		http:    httpClient,
+	}
+}
+
+type idempotencyKeyCtx struct{}
+
+// WithIdempotencyKey задает ключ идемпотентности для POST-запросов с этим контекстом.
+// Без него клиент генерирует новый ключ на каждый вызов метода.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx{}, key)
+}
+
+// NewIdempotencyKey возвращает случайный ключ идемпотентности
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err) // crypto/rand не возвращает ошибок на поддерживаемых платформах
+	}
+	return hex.EncodeToString(b)
+}
+
+// call описывает вызов API
+type call struct {
+	method string
+	path   string
+	query  url.Values
+	// JSON-тело (если body не задан)
+	in          interface{}
+	body        []byte
+	contentType string
+	// Маршруты /admin авторизуются токеном администратора
+	admin bool
+}
+
+// doJSON выполняет вызов и декодирует JSON-ответ в out (nil - ответ не нужен)
+func (c *Client) doJSON(ctx context.Context, cl call, out interface{}) error {
+	resp, err := c.do(ctx, cl)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s %s response: %w", cl.method, cl.path, err)
+	}
+	return nil
+}
+
+// do выполняет вызов с повторами и возвращает ответ 2xx; тело ответа закрывает вызывающий
+func (c *Client) do(ctx context.Context, cl call) (*http.Response, error) {
+	body := cl.body
+	contentType := cl.contentType
+	if body == nil && cl.in != nil {
+		data, err := json.Marshal(cl.in)
+		if err != nil {
+			return nil, err
+		}
+		body, contentType = data, "application/json"
+	}
+
+	// Повтор POST безопасен только с ключом идемпотентности; маршруты /admin его не поддерживают
+	var idempotencyKey string
+	retry := true
+	if cl.method == http.MethodPost {
+		if cl.admin {
+			retry = false
+		} else if key, ok := ctx.Value(idempotencyKeyCtx{}).(string); ok && key != "" {
+			idempotencyKey = key
+		} else {
+			idempotencyKey = NewIdempotencyKey()
+		}
+	}
+
+	target := c.baseURL + cl.path
+	if len(cl.query) > 0 {
+		target += "?" + cl.query.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, cl.method, target, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if idempotencyKey != "" {
+			req.Header.Set(IdempotencyKeyHeader, idempotencyKey)
+		}
+		token := c.cfg.APIKey
+		if cl.admin {
+			token = c.cfg.AdminToken
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := c.http.Do(req)
+		var retryAfter time.Duration
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			return resp, nil
+		default:
+			apiErr := readAPIError(resp)
+			if !retryable(apiErr, idempotencyKey != "") {
+				return nil, apiErr
+			}
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+			err = apiErr
+		}
+
+		if !retry || attempt >= c.cfg.MaxRetries {
+			return nil, err
+		}
+		timer := time.NewTimer(max(c.backoff(attempt), retryAfter))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable - можно ли повторить запрос, получивший ошибку API
+func retryable(err *APIError, idempotent bool) bool {
+	switch {
+	case err.StatusCode >= http.StatusInternalServerError, err.StatusCode == http.StatusTooManyRequests:
+		return true
+	case err.StatusCode == http.StatusConflict && idempotent:
+		// Первый запрос с тем же ключом еще выполняется или только что освободил ключ
+		return strings.Contains(err.Message, IdempotencyKeyHeader)
+	}
+	return false
+}
+
+// backoff - экспоненциальная задержка перед повтором attempt со случайным разбросом в пределах половины
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.cfg.MaxBackoff
+	if shifted := c.cfg.MinBackoff << attempt; attempt < 32 && shifted > 0 {
+		d = min(shifted, c.cfg.MaxBackoff)
+	}
+	return d/2 + time.Duration(mathrand.Int63n(int64(d/2)+1))
+}
+
+func parseRetryAfter(v string) time.Duration {
+	seconds, err := strconv.Atoi(v)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// readAPIError читает ответ с ошибкой и закрывает его тело
+func readAPIError(resp *http.Response) *APIError {
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	apiErr := &APIError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode), Body: data}
+	var payload struct {
+		Error   string      `json:"error"`
+		Code    string      `json:"code"`
+		Details interface{} `json:"details"`
+	}
+	if json.Unmarshal(data, &payload) == nil && payload.Error != "" {
+		apiErr.Message = payload.Error
+		apiErr.Code = payload.Code
+		if payload.Details != nil {
+			apiErr.Details = fmt.Sprint(payload.Details)
+		}
+	}
+	apiErr.Err = domainError(apiErr)
+	return apiErr
+}
@@ -0,0 +1,199 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/api"
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeService реализует только нужные тестам методы
+type fakeService struct {
+	domain.Service
+}
+
+func (s *fakeService) CreateTeamWithParent(ctx context.Context, name string, parentID *int) (*domain.Team, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, domain.ErrInvalidTeamName
+	}
+	return &domain.Team{ID: 1, Name: name, ParentID: parentID}, nil
+}
+
+func (s *fakeService) GetTeam(ctx context.Context, teamID int) (*domain.Team, error) {
+	return nil, domain.ErrTeamNotFound
+}
+
+func (s *fakeService) RerollReviewer(ctx context.Context, prID int, oldReviewerID int) (*domain.PullRequest, error) {
+	return nil, fmt.Errorf("reroll PR %d: %w", prID, domain.ErrNoReviewersFound)
+}
+
+func (s *fakeService) AddReviewer(ctx context.Context, prID int, userID int) (*domain.PullRequest, error) {
+	return nil, domain.ErrUserNotFound
+}
+
+func (s *fakeService) GetReviewerStats(ctx context.Context) (map[int]int, error) {
+	return map[int]int{2: 5, 10: 1}, nil
+}
+
+func (s *fakeService) ImportDirectory(ctx context.Context, records []domain.DirectoryRecord, dryRun bool) (*domain.ImportReport, error) {
+	return &domain.ImportReport{DryRun: dryRun, Failed: 1, Rows: []domain.ImportRowResult{
+		{Row: 1, Action: domain.ImportFailed, Error: "user is required"},
+	}}, nil
+}
+
+func newTestClient(t *testing.T, handler http.Handler, cfg Config) *Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	cfg.BaseURL = srv.URL
+	if cfg.MinBackoff == 0 {
+		cfg.MinBackoff = time.Millisecond
+	}
+	return New(cfg)
+}
+
+func TestDomainErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c := newTestClient(t, api.SetupRouter(api.NewHandler(&fakeService{})), Config{})
+	ctx := context.Background()
+
+	team, err := c.CreateTeam(ctx, "Backend", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Backend", team.Name)
+
+	_, err = c.CreateTeam(ctx, " ", nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidTeamName)
+	assert.Equal(t, http.StatusBadRequest, StatusCode(err))
+
+	// Ошибка определяется по коду в ответе, а не по маршруту
+	_, err = c.GetTeam(ctx, 42)
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+	_, err = c.AddReviewer(ctx, 1, 99)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	assert.NotErrorIs(t, err, domain.ErrPRNotFound)
+	assert.Equal(t, http.StatusNotFound, StatusCode(err))
+
+	// Обернутая сервисом ошибка
+	_, err = c.RerollReviewer(ctx, 1, 2)
+	assert.ErrorIs(t, err, domain.ErrNoReviewersFound)
+	assert.Equal(t, http.StatusConflict, StatusCode(err))
+
+	stats, err := c.GetReviewerStats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int{2: 5, 10: 1}, stats)
+
+	// С ошибками в строках возвращается и отчет, и ошибка
+	report, err := c.ImportDirectory(ctx, []domain.DirectoryRecord{{Team: "Backend"}}, true)
+	assert.Equal(t, http.StatusUnprocessableEntity, StatusCode(err))
+	if assert.NotNil(t, report) {
+		assert.Equal(t, "user is required", report.Rows[0].Error)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	failures := 2
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		if len(keys) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"id": 7, "status": "MERGED"}`)
+	})
+
+	c := newTestClient(t, handler, Config{})
+	pr, err := c.MergePR(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.PRStatusMerged, pr.Status)
+	// Все попытки - с одним ключом идемпотентности
+	assert.Len(t, keys, 3)
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[2])
+
+	// Ключ из контекста
+	keys = nil
+	_, err = c.MergePR(WithIdempotencyKey(context.Background(), "merge-7"), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"merge-7", "merge-7", "merge-7"}, keys)
+
+	// Без повторов - первая ошибка
+	keys = nil
+	_, err = newTestClient(t, handler, Config{MaxRetries: -1}).MergePR(context.Background(), 7)
+	assert.Equal(t, http.StatusServiceUnavailable, StatusCode(err))
+	assert.Len(t, keys, 1)
+
+	// Повторы закончились - последняя ошибка
+	keys = nil
+	failures = 10
+	_, err = c.GetPR(context.Background(), 7)
+	assert.Equal(t, http.StatusServiceUnavailable, StatusCode(err))
+	assert.Len(t, keys, DefaultMaxRetries+1)
+	assert.Empty(t, keys[0]) // GET идемпотентен и без ключа
+}
+
+func TestNoRetryOnClientErrors(t *testing.T) {
+	attempts := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"error": "pull request already merged", "code": "pr_already_merged"}`)
+	})
+
+	c := newTestClient(t, handler, Config{})
+	_, err := c.RerollReviewer(context.Background(), 1, 2)
+	assert.ErrorIs(t, err, domain.ErrPRAlreadyMerged)
+	assert.Equal(t, 2, attempts)
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "pull request already merged (HTTP 409)", apiErr.Error())
+}
+
+func TestStreamEvents(t *testing.T) {
+	var cursors []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cursors = append(cursors, r.URL.Query().Get("last_event_id"))
+		w.Header().Set("Content-Type", "text/event-stream")
+		// Каждое подключение отдает одно событие и обрывается
+		id := len(cursors)
+		fmt.Fprintf(w, ": ping\n\nid:%d\nevent:%s\ndata:{\"id\":%d,\"type\":%q,\"pr_id\":5}\n\n",
+			id, domain.EventReviewerAssigned, id, domain.EventReviewerAssigned)
+	})
+
+	c := newTestClient(t, handler, Config{})
+	stop := errors.New("stop")
+	var events []domain.Event
+	err := c.StreamEvents(context.Background(), domain.EventFilter{TeamID: 3}, 0, func(e domain.Event) error {
+		events = append(events, e)
+		if len(events) == 2 {
+			return stop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stop)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, int64(2), events[1].ID)
+		assert.Equal(t, 5, events[1].PRID)
+	}
+	// Переподключение продолжает после последнего полученного события
+	assert.Equal(t, []string{"", "1"}, cursors)
+}
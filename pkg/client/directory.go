@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// ImportDirectory импортирует команды и пользователей. Если в строках есть ошибки, ничего не применяется:
+// возвращается отчет со строками и *APIError с кодом 422.
+func (c *Client) ImportDirectory(ctx context.Context, records []domain.DirectoryRecord, dryRun bool) (*domain.ImportReport, error) {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return nil, err
+	}
+	return c.importDirectory(ctx, data, "application/json", dryRun)
+}
+
+// ImportDirectoryCSV - то же для CSV (колонки team,user,login,email,chat_handle,active)
+func (c *Client) ImportDirectoryCSV(ctx context.Context, r io.Reader, dryRun bool) (*domain.ImportReport, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return c.importDirectory(ctx, data, "text/csv", dryRun)
+}
+
+func (c *Client) importDirectory(ctx context.Context, data []byte, contentType string, dryRun bool) (*domain.ImportReport, error) {
+	cl := call{method: http.MethodPost, path: "/import", body: data, contentType: contentType}
+	if dryRun {
+		cl.query = url.Values{"dry_run": {"true"}}
+	}
+	var report domain.ImportReport
+	err := c.doJSON(ctx, cl, &report)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity &&
+		json.Unmarshal(apiErr.Body, &report) == nil && report.Failed > 0 {
+		return &report, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// ExportDirectory выгружает команды и пользователей (архивные команды и удаленные пользователи не входят)
+func (c *Client) ExportDirectory(ctx context.Context) ([]domain.DirectoryRecord, error) {
+	var records []domain.DirectoryRecord
+	if err := c.doJSON(ctx, call{method: http.MethodGet, path: "/export", query: url.Values{"format": {"json"}}}, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// ExportDirectoryCSV пишет выгрузку в CSV в w
+func (c *Client) ExportDirectoryCSV(ctx context.Context, w io.Writer) error {
+	return c.download(ctx, call{method: http.MethodGet, path: "/export", query: url.Values{"format": {"csv"}}}, w)
+}
+
+// --- Администрирование (Config.AdminToken) ---
+
+// CreateOrganization создает организацию и возвращает ее API-ключ (показывается только здесь)
+func (c *Client) CreateOrganization(ctx context.Context, name string) (*domain.Organization, string, error) {
+	var resp struct {
+		Organization domain.Organization `json:"organization"`
+		APIKey       string              `json:"api_key"`
+	}
+	cl := call{method: http.MethodPost, path: "/admin/orgs", in: map[string]string{"name": name}, admin: true}
+	if err := c.doJSON(ctx, cl, &resp); err != nil {
+		return nil, "", err
+	}
+	return &resp.Organization, resp.APIKey, nil
+}
+
+// ExportSnapshot пишет снимок всей установки (JSON Lines) в w; anonymize - с псевдонимами вместо личных данных
+func (c *Client) ExportSnapshot(ctx context.Context, w io.Writer, anonymize bool) error {
+	cl := call{method: http.MethodGet, path: "/admin/snapshot", admin: true}
+	if anonymize {
+		cl.query = url.Values{"anonymize": {"true"}}
+	}
+	return c.download(ctx, cl, w)
+}
+
+// ImportSnapshot восстанавливает снимок в пустую базу и возвращает число восстановленных записей по видам
+func (c *Client) ImportSnapshot(ctx context.Context, r io.Reader) (map[string]int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var counts map[string]int
+	cl := call{method: http.MethodPost, path: "/admin/snapshot", body: data, contentType: "application/x-ndjson", admin: true}
+	if err := c.doJSON(ctx, cl, &counts); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (c *Client) download(ctx context.Context, cl call, w io.Writer) error {
+	resp, err := c.do(ctx, cl)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+var (
+	// 401 и 403: нет или неверный API-ключ / токен администратора
+	ErrUnauthorized = errors.New("unauthorized")
+	// 429: превышен лимит запросов (после всех повторов)
+	ErrRateLimited = errors.New("rate limit exceeded")
+)
+
+// APIError - ответ API с кодом ошибки
+type APIError struct {
+	StatusCode int
+	Message    string
+	Details    string
+	// Код ошибки домена из ответа (например, pr_not_found); пусто - ответ без кода
+	Code string
+	// Тело ответа как есть (например, отчет импорта с ошибками в строках)
+	Body []byte
+	// Соответствующая ошибка домена или ErrUnauthorized / ErrRateLimited; nil - не распознана
+	Err error
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s (HTTP %d)", e.Message, e.StatusCode)
+	if e.Details != "" {
+		msg += ": " + e.Details
+	}
+	return msg
+}
+
+func (e *APIError) Unwrap() error { return e.Err }
+
+// domainError сопоставляет ответ ошибке домена по коду из тела ответа (см. handleServiceError в API)
+func domainError(e *APIError) error {
+	switch e.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	return domain.ErrorByCode(e.Code)
+}
+
+// StatusCode возвращает HTTP-код ошибки API (0 - ошибка не от API, например сетевая)
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// StreamEvents читает поток доменных событий (SSE) и вызывает handle для каждого события.
+// lastEventID > 0 - продолжить после этого события, иначе только новые события. Оборванный поток
+// переоткрывается с Last-Event-ID. Возвращает ошибку handle, ошибку API без повтора или ctx.Err().
+func (c *Client) StreamEvents(ctx context.Context, filter domain.EventFilter, lastEventID int64, handle func(domain.Event) error) error {
+	query := url.Values{}
+	if filter.TeamID != 0 {
+		query.Set("team_id", strconv.Itoa(filter.TeamID))
+	}
+	if filter.UserID != 0 {
+		query.Set("user_id", strconv.Itoa(filter.UserID))
+	}
+
+	// Поток живет дольше таймаута обычных запросов
+	stream := *c
+	httpClient := *c.http
+	httpClient.Timeout = 0
+	stream.http = &httpClient
+
+	for attempt := 0; ; attempt++ {
+		if lastEventID > 0 {
+			query.Set("last_event_id", strconv.FormatInt(lastEventID, 10))
+		}
+		resp, err := stream.do(ctx, call{method: http.MethodGet, path: "/events/stream", query: query})
+		if err != nil {
+			return err
+		}
+		received, err := readEvents(resp, func(e domain.Event) error {
+			lastEventID = e.ID
+			return handle(e)
+		})
+		resp.Body.Close()
+		var handleErr *handlerError
+		if errors.As(err, &handleErr) {
+			return handleErr.err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if received {
+			attempt = 0
+		}
+		timer := time.NewTimer(c.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// handlerError - ошибка обработчика события (в отличие от обрыва потока, не переподключаемся)
+type handlerError struct {
+	err error
+}
+
+func (e *handlerError) Error() string { return e.err.Error() }
+
+// readEvents разбирает поток до его конца; received - было ли хотя бы одно событие
+func readEvents(resp *http.Response, handle func(domain.Event) error) (bool, error) {
+	received := false
+	var data strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// Конец события
+			if data.Len() == 0 {
+				continue
+			}
+			var event domain.Event
+			err := json.Unmarshal([]byte(data.String()), &event)
+			data.Reset()
+			if err != nil {
+				return received, err
+			}
+			received = true
+			if err := handle(event); err != nil {
+				return received, &handlerError{err: err}
+			}
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+		// Поля id и event дублируют данные события, комментарии (": ping") пропускаем
+	}
+	return received, scanner.Err()
+}
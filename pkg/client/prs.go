@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// CreatePR создает PR с автоназначением ревьюеров; files и labels необязательны
+func (c *Client) CreatePR(ctx context.Context, title string, authorID int, files, labels []string) (*domain.PullRequest, error) {
+	in := map[string]interface{}{"title": title, "author_id": authorID, "files": files, "labels": labels}
+	// 404 здесь - не найден автор
+	return c.prCall(ctx, http.MethodPost, "/prs", in)
+}
+
+// GetPR возвращает PR с положением в очереди ожидания ревьюера
+func (c *Client) GetPR(ctx context.Context, prID int) (*domain.PullRequest, error) {
+	return c.prCall(ctx, http.MethodGet, fmt.Sprintf("/prs/%d", prID), nil)
+}
+
+func (c *Client) GetPRHistory(ctx context.Context, prID int) ([]domain.PRHistoryEntry, error) {
+	var history []domain.PRHistoryEntry
+	cl := call{method: http.MethodGet, path: fmt.Sprintf("/prs/%d/history", prID)}
+	if err := c.doJSON(ctx, cl, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// MergePR мерджит PR; повторный мердж возвращает тот же PR
+func (c *Client) MergePR(ctx context.Context, prID int) (*domain.PullRequest, error) {
+	return c.prCall(ctx, http.MethodPost, fmt.Sprintf("/prs/%d/merge", prID), nil)
+}
+
+// RerollReviewer заменяет ревьюера случайным подходящим
+func (c *Client) RerollReviewer(ctx context.Context, prID, oldReviewerID int) (*domain.PullRequest, error) {
+	in := map[string]int{"old_reviewer_id": oldReviewerID}
+	return c.prCall(ctx, http.MethodPost, fmt.Sprintf("/prs/%d/reroll", prID), in)
+}
+
+// ReplaceReviewer заменяет ревьюера конкретным пользователем
+func (c *Client) ReplaceReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID int) (*domain.PullRequest, error) {
+	in := map[string]int{"old_reviewer_id": oldReviewerID, "new_reviewer_id": newReviewerID}
+	return c.prCall(ctx, http.MethodPost, fmt.Sprintf("/prs/%d/reroll", prID), in)
+}
+
+func (c *Client) AddReviewer(ctx context.Context, prID, userID int) (*domain.PullRequest, error) {
+	return c.prCall(ctx, http.MethodPost, fmt.Sprintf("/prs/%d/reviewers", prID), map[string]int{"user_id": userID})
+}
+
+func (c *Client) RemoveReviewer(ctx context.Context, prID, userID int) (*domain.PullRequest, error) {
+	return c.prCall(ctx, http.MethodDelete, fmt.Sprintf("/prs/%d/reviewers/%d", prID, userID), nil)
+}
+
+// GetReviewerPRs возвращает PR, на которые назначен ревьюер
+func (c *Client) GetReviewerPRs(ctx context.Context, reviewerID int) ([]domain.PullRequest, error) {
+	var prs []domain.PullRequest
+	cl := call{method: http.MethodGet, path: fmt.Sprintf("/users/%d/prs", reviewerID)}
+	if err := c.doJSON(ctx, cl, &prs); err != nil {
+		return nil, err
+	}
+	return prs, nil
+}
+
+func (c *Client) prCall(ctx context.Context, method, path string, in interface{}) (*domain.PullRequest, error) {
+	var pr domain.PullRequest
+	if err := c.doJSON(ctx, call{method: method, path: path, in: in}, &pr); err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// --- Статистика ---
+
+// GetReviewerStats возвращает число назначений по ID ревьюера
+func (c *Client) GetReviewerStats(ctx context.Context) (map[int]int, error) {
+	return c.stats(ctx, nil)
+}
+
+// GetTeamReviewerStats - то же по ревьюерам из поддерева команды
+func (c *Client) GetTeamReviewerStats(ctx context.Context, teamID int) (map[int]int, error) {
+	return c.stats(ctx, url.Values{"team_id": {strconv.Itoa(teamID)}})
+}
+
+func (c *Client) stats(ctx context.Context, query url.Values) (map[int]int, error) {
+	var resp struct {
+		Counts map[int]int `json:"reviewer_assignments_count"`
+	}
+	cl := call{method: http.MethodGet, path: "/stats/reviewers", query: query}
+	if err := c.doJSON(ctx, cl, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Counts == nil {
+		resp.Counts = make(map[int]int)
+	}
+	return resp.Counts, nil
+}
@@ -0,0 +1,202 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// CreateTeam создает команду; parentID == nil - команда верхнего уровня
+func (c *Client) CreateTeam(ctx context.Context, name string, parentID *int) (*domain.Team, error) {
+	var team domain.Team
+	in := map[string]interface{}{"name": name, "parent_id": parentID}
+	err := c.doJSON(ctx, call{method: http.MethodPost, path: "/teams", in: in}, &team)
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (c *Client) GetTeam(ctx context.Context, teamID int) (*domain.Team, error) {
+	var team domain.Team
+	err := c.doJSON(ctx, call{method: http.MethodGet, path: fmt.Sprintf("/teams/%d", teamID)}, &team)
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (c *Client) RenameTeam(ctx context.Context, teamID int, name string) (*domain.Team, error) {
+	var team domain.Team
+	cl := call{method: http.MethodPatch, path: fmt.Sprintf("/teams/%d", teamID), in: map[string]string{"name": name}}
+	if err := c.doJSON(ctx, cl, &team); err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// ArchiveTeam архивирует команду; strategy - domain.ArchiveReassign или domain.ArchiveClose (пусто - reassign)
+func (c *Client) ArchiveTeam(ctx context.Context, teamID int, strategy string) (*domain.Team, error) {
+	var team domain.Team
+	cl := call{method: http.MethodPost, path: fmt.Sprintf("/teams/%d/archive", teamID), in: map[string]string{"strategy": strategy}}
+	if err := c.doJSON(ctx, cl, &team); err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// MoveTeamMembers переводит участников в другую команду (без userIDs - всех)
+func (c *Client) MoveTeamMembers(ctx context.Context, fromTeamID, toTeamID int, userIDs ...int) ([]domain.User, error) {
+	var resp struct {
+		Moved []domain.User `json:"moved"`
+	}
+	in := map[string]interface{}{"to_team_id": toTeamID, "user_ids": userIDs}
+	cl := call{method: http.MethodPost, path: fmt.Sprintf("/teams/%d/members/move", fromTeamID), in: in}
+	if err := c.doJSON(ctx, cl, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Moved, nil
+}
+
+// MoveTeam переносит команду в другой отдел (parentID == nil - на верхний уровень)
+func (c *Client) MoveTeam(ctx context.Context, teamID int, parentID *int) (*domain.Team, error) {
+	var team domain.Team
+	cl := call{method: http.MethodPut, path: fmt.Sprintf("/teams/%d/parent", teamID), in: map[string]*int{"parent_id": parentID}}
+	if err := c.doJSON(ctx, cl, &team); err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// GetSubtreeMembers возвращает участников команды и всех ее подкоманд
+func (c *Client) GetSubtreeMembers(ctx context.Context, teamID int) ([]domain.User, error) {
+	var resp struct {
+		Members []domain.User `json:"members"`
+	}
+	cl := call{method: http.MethodGet, path: fmt.Sprintf("/teams/%d/subtree/members", teamID)}
+	if err := c.doJSON(ctx, cl, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Members, nil
+}
+
+func (c *Client) GetTeamMembers(ctx context.Context, teamID int) ([]domain.TeamMembership, error) {
+	var resp struct {
+		Members []domain.TeamMembership `json:"members"`
+	}
+	cl := call{method: http.MethodGet, path: fmt.Sprintf("/teams/%d/members", teamID)}
+	if err := c.doJSON(ctx, cl, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Members, nil
+}
+
+// SetTeamMembership добавляет пользователя в команду или меняет флаги участия
+func (c *Client) SetTeamMembership(ctx context.Context, membership *domain.TeamMembership) (*domain.TeamMembership, error) {
+	var saved domain.TeamMembership
+	in := map[string]bool{"can_author": membership.CanAuthor, "can_review": membership.CanReview}
+	cl := call{method: http.MethodPut, path: fmt.Sprintf("/teams/%d/members/%d", membership.TeamID, membership.UserID), in: in}
+	if err := c.doJSON(ctx, cl, &saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+func (c *Client) RemoveTeamMember(ctx context.Context, teamID, userID int) error {
+	cl := call{method: http.MethodDelete, path: fmt.Sprintf("/teams/%d/members/%d", teamID, userID)}
+	return c.doJSON(ctx, cl, nil)
+}
+
+// MassDeactivateTeamUsers деактивирует участников команды (без userIDs - всех) с переназначением их ревью
+func (c *Client) MassDeactivateTeamUsers(ctx context.Context, teamID int, userIDs ...int) error {
+	cl := call{method: http.MethodPost, path: fmt.Sprintf("/teams/%d/deactivate", teamID), in: map[string][]int{"user_ids": userIDs}}
+	return c.doJSON(ctx, cl, nil)
+}
+
+// --- Политика и владельцы кода ---
+
+func (c *Client) GetTeamPolicy(ctx context.Context, teamID int) (*domain.TeamPolicy, error) {
+	var policy domain.TeamPolicy
+	cl := call{method: http.MethodGet, path: fmt.Sprintf("/teams/%d/policy", teamID)}
+	if err := c.doJSON(ctx, cl, &policy); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// UpdateTeamPolicy сохраняет политику команды policy.TeamID
+func (c *Client) UpdateTeamPolicy(ctx context.Context, policy *domain.TeamPolicy) (*domain.TeamPolicy, error) {
+	var saved domain.TeamPolicy
+	cl := call{method: http.MethodPut, path: fmt.Sprintf("/teams/%d/policy", policy.TeamID), in: policy}
+	if err := c.doJSON(ctx, cl, &saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+func (c *Client) GetCodeOwners(ctx context.Context, teamID int) ([]domain.CodeOwnerRule, error) {
+	var rules []domain.CodeOwnerRule
+	cl := call{method: http.MethodGet, path: fmt.Sprintf("/teams/%d/codeowners", teamID)}
+	if err := c.doJSON(ctx, cl, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// UploadCodeOwners загружает файл CODEOWNERS как есть
+func (c *Client) UploadCodeOwners(ctx context.Context, teamID int, content io.Reader) (*domain.CodeOwnersReport, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	var report domain.CodeOwnersReport
+	cl := call{method: http.MethodPut, path: fmt.Sprintf("/teams/%d/codeowners", teamID), body: data, contentType: "text/plain"}
+	if err := c.doJSON(ctx, cl, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// --- Уведомления ---
+
+func (c *Client) GetNotificationTemplates(ctx context.Context, teamID int) ([]domain.NotificationTemplate, error) {
+	var templates []domain.NotificationTemplate
+	cl := call{method: http.MethodGet, path: fmt.Sprintf("/teams/%d/templates", teamID)}
+	if err := c.doJSON(ctx, cl, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// SaveNotificationTemplate сохраняет шаблон письма tpl.EventType команды tpl.TeamID
+func (c *Client) SaveNotificationTemplate(ctx context.Context, tpl *domain.NotificationTemplate) (*domain.NotificationTemplate, error) {
+	var saved domain.NotificationTemplate
+	in := map[string]string{"subject": tpl.Subject, "body": tpl.Body}
+	cl := call{method: http.MethodPut, path: fmt.Sprintf("/teams/%d/templates/%s", tpl.TeamID, tpl.EventType), in: in}
+	if err := c.doJSON(ctx, cl, &saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+func (c *Client) DeleteNotificationTemplate(ctx context.Context, teamID int, eventType string) error {
+	cl := call{method: http.MethodDelete, path: fmt.Sprintf("/teams/%d/templates/%s", teamID, eventType)}
+	return c.doJSON(ctx, cl, nil)
+}
+
+func (c *Client) SetTeamChatWebhook(ctx context.Context, teamID int, url string) (*domain.TeamChatWebhook, error) {
+	var webhook domain.TeamChatWebhook
+	cl := call{method: http.MethodPut, path: fmt.Sprintf("/teams/%d/chat-webhook", teamID), in: map[string]string{"url": url}}
+	if err := c.doJSON(ctx, cl, &webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (c *Client) DeleteTeamChatWebhook(ctx context.Context, teamID int) error {
+	cl := call{method: http.MethodDelete, path: fmt.Sprintf("/teams/%d/chat-webhook", teamID)}
+	return c.doJSON(ctx, cl, nil)
+}
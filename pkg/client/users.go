@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Shishlyannikovvv/project-avito/internal/domain"
+)
+
+// CreateUser создает активного пользователя в основной команде teamID
+func (c *Client) CreateUser(ctx context.Context, name string, teamID int) (*domain.User, error) {
+	var user domain.User
+	in := map[string]interface{}{"name": name, "team_id": teamID}
+	if err := c.doJSON(ctx, call{method: http.MethodPost, path: "/users", in: in}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// DeactivateUser деактивирует пользователя с переназначением его открытых ревью
+func (c *Client) DeactivateUser(ctx context.Context, userID int) error {
+	cl := call{method: http.MethodDelete, path: fmt.Sprintf("/users/%d", userID)}
+	return c.doJSON(ctx, cl, nil)
+}
+
+// HardDeleteUser удаляет пользователя с обезличиванием (запись остается для истории PR)
+func (c *Client) HardDeleteUser(ctx context.Context, userID int) error {
+	cl := call{method: http.MethodDelete, path: fmt.Sprintf("/users/%d", userID), query: url.Values{"hard": {"true"}}}
+	return c.doJSON(ctx, cl, nil)
+}
+
+func (c *Client) ActivateUser(ctx context.Context, userID int) (*domain.User, error) {
+	return c.userCall(ctx, http.MethodPost, fmt.Sprintf("/users/%d/activate", userID), nil)
+}
+
+// UpdateUser меняет имя и основную команду (nil-поля update не меняются)
+func (c *Client) UpdateUser(ctx context.Context, userID int, update domain.UserUpdate) (*domain.User, error) {
+	in := map[string]interface{}{"name": update.Name, "team_id": update.TeamID}
+	return c.userCall(ctx, http.MethodPatch, fmt.Sprintf("/users/%d", userID), in)
+}
+
+func (c *Client) SetUserSeniority(ctx context.Context, userID int, seniority string) (*domain.User, error) {
+	return c.userCall(ctx, http.MethodPut, fmt.Sprintf("/users/%d/seniority", userID), map[string]string{"seniority": seniority})
+}
+
+// SetUserMaxOpenReviews задает лимит открытых ревью (nil - лимит команды по умолчанию)
+func (c *Client) SetUserMaxOpenReviews(ctx context.Context, userID int, limit *int) (*domain.User, error) {
+	return c.userCall(ctx, http.MethodPut, fmt.Sprintf("/users/%d/capacity", userID), map[string]*int{"max_open_reviews": limit})
+}
+
+func (c *Client) SetUserExpertise(ctx context.Context, userID int, login string, tags []string) (*domain.User, error) {
+	in := map[string]interface{}{"login": login, "tags": tags}
+	return c.userCall(ctx, http.MethodPut, fmt.Sprintf("/users/%d/expertise", userID), in)
+}
+
+func (c *Client) SetUserNotifications(ctx context.Context, userID int, email string, optOut []string) (*domain.User, error) {
+	in := map[string]interface{}{"email": email, "opt_out": optOut}
+	return c.userCall(ctx, http.MethodPut, fmt.Sprintf("/users/%d/notifications", userID), in)
+}
+
+func (c *Client) SetUserChatHandle(ctx context.Context, userID int, handle string) (*domain.User, error) {
+	return c.userCall(ctx, http.MethodPut, fmt.Sprintf("/users/%d/chat", userID), map[string]string{"handle": handle})
+}
+
+// SetPrimaryTeam меняет команду, от имени которой пользователь создает PR
+func (c *Client) SetPrimaryTeam(ctx context.Context, userID, teamID int) (*domain.User, error) {
+	return c.userCall(ctx, http.MethodPut, fmt.Sprintf("/users/%d/primary-team", userID), map[string]int{"team_id": teamID})
+}
+
+func (c *Client) GetUserTeams(ctx context.Context, userID int) ([]domain.TeamMembership, error) {
+	var resp struct {
+		Teams []domain.TeamMembership `json:"teams"`
+	}
+	cl := call{method: http.MethodGet, path: fmt.Sprintf("/users/%d/teams", userID)}
+	if err := c.doJSON(ctx, cl, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Teams, nil
+}
+
+func (c *Client) userCall(ctx context.Context, method, path string, in interface{}) (*domain.User, error) {
+	var user domain.User
+	if err := c.doJSON(ctx, call{method: method, path: path, in: in}, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// --- Периоды отсутствия ---
+
+// AddUnavailability добавляет период отсутствия пользователя window.UserID
+func (c *Client) AddUnavailability(ctx context.Context, window *domain.UserUnavailability) (*domain.UserUnavailability, error) {
+	return c.unavailabilityCall(ctx, http.MethodPost, fmt.Sprintf("/users/%d/unavailability", window.UserID), window)
+}
+
+func (c *Client) ListUnavailability(ctx context.Context, userID int) ([]domain.UserUnavailability, error) {
+	var windows []domain.UserUnavailability
+	cl := call{method: http.MethodGet, path: fmt.Sprintf("/users/%d/unavailability", userID)}
+	if err := c.doJSON(ctx, cl, &windows); err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// UpdateUnavailability меняет период window.ID пользователя window.UserID
+func (c *Client) UpdateUnavailability(ctx context.Context, window *domain.UserUnavailability) (*domain.UserUnavailability, error) {
+	return c.unavailabilityCall(ctx, http.MethodPut, fmt.Sprintf("/users/%d/unavailability/%d", window.UserID, window.ID), window)
+}
+
+func (c *Client) DeleteUnavailability(ctx context.Context, userID, windowID int) error {
+	cl := call{method: http.MethodDelete, path: fmt.Sprintf("/users/%d/unavailability/%d", userID, windowID)}
+	return c.doJSON(ctx, cl, nil)
+}
+
+func (c *Client) unavailabilityCall(ctx context.Context, method, path string, window *domain.UserUnavailability) (*domain.UserUnavailability, error) {
+	in := map[string]interface{}{"starts_at": window.StartsAt, "ends_at": window.EndsAt, "reason": window.Reason}
+	var saved domain.UserUnavailability
+	if err := c.doJSON(ctx, call{method: method, path: path, in: in}, &saved); err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
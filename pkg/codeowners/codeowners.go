@@ -0,0 +1,150 @@
+// Package codeowners разбирает файлы формата CODEOWNERS (GitHub/GitLab) и сопоставляет пути с владельцами.
+package codeowners
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Rule - одна строка CODEOWNERS: шаблон пути и его владельцы
+type Rule struct {
+	Pattern string
+	Owners  []string // как в файле: @login, @org/team, email
+	Line    int      // номер строки в исходном файле (0 - правило создано не из файла)
+
+	re *regexp.Regexp
+}
+
+// NewRule компилирует шаблон пути в правило
+func NewRule(pattern string, owners []string) (Rule, error) {
+	re, err := compile(pattern)
+	if err != nil {
+		return Rule{}, err
+	}
+	return Rule{Pattern: pattern, Owners: owners, re: re}, nil
+}
+
+// Match сообщает, подходит ли путь (относительно корня репозитория) под шаблон правила
+func (r Rule) Match(path string) bool {
+	if r.re == nil {
+		return false
+	}
+	return r.re.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+// Parse читает файл CODEOWNERS. Пустые строки, комментарии и заголовки секций GitLab ([Section]) пропускаются.
+func Parse(r io.Reader) ([]Rule, error) {
+	var rules []Rule
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := stripComment(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "[") || strings.HasPrefix(line, "^[") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		pattern := fields[0]
+		if strings.HasPrefix(pattern, "!") {
+			return nil, fmt.Errorf("line %d: negated patterns are not supported", lineNo)
+		}
+
+		rule, err := NewRule(pattern, fields[1:])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		rule.Line = lineNo
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// OwnersOf возвращает владельцев пути. Как и в GitHub, побеждает последнее совпавшее правило;
+// правило без владельцев означает, что у пути владельцев нет.
+func OwnersOf(rules []Rule, path string) []string {
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rules[i].Match(path) {
+			return rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// --- Helpers ---
+
+// stripComment убирает комментарий (# в начале строки или после пробела) и лишние пробелы
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			line = line[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(line)
+}
+
+// compile переводит шаблон в стиле gitignore в регулярное выражение:
+//   - "/" в начале или в середине привязывает шаблон к корню, иначе он ищется на любой глубине;
+//   - "/" в конце - шаблон совпадает только с каталогом (и всем внутри);
+//   - "*" и "?" не пересекают "/", "**" - пересекает;
+//   - "dir/*" совпадает только с прямыми потомками каталога.
+func compile(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+
+	p := pattern
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	anchored := strings.HasPrefix(p, "/") || strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		// Шаблон "/" - весь репозиторий
+		return regexp.Compile(`^.*$`)
+	}
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case p[i] == '*':
+			b.WriteString("[^/]*")
+		case p[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(p[i])))
+		}
+	}
+
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case strings.HasSuffix(p, "/*"):
+		b.WriteString("$")
+	default:
+		// Шаблон может указывать и на файл, и на каталог со всем содержимым
+		b.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,78 @@
+package codeowners
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleFile = `
+# Владельцы по умолчанию
+*                 @lead
+
+[Backend]
+*.go              @gopher @org/backend
+/internal/api/    @api-owner
+docs/*            docs@example.com
+**/migrations     @dba
+/cmd/stresser     # без владельцев
+`
+
+func TestParse(t *testing.T) {
+	rules, err := Parse(strings.NewReader(sampleFile))
+	assert.NoError(t, err)
+	assert.Len(t, rules, 6)
+
+	assert.Equal(t, "*.go", rules[1].Pattern)
+	assert.Equal(t, []string{"@gopher", "@org/backend"}, rules[1].Owners)
+	assert.Equal(t, 6, rules[1].Line)
+	assert.Empty(t, rules[5].Owners)
+
+	_, err = Parse(strings.NewReader("!*.md @someone"))
+	assert.Error(t, err)
+}
+
+func TestOwnersOf(t *testing.T) {
+	rules, err := Parse(strings.NewReader(sampleFile))
+	assert.NoError(t, err)
+
+	cases := map[string][]string{
+		"README.md":                         {"@lead"},
+		"internal/service/manager.go":       {"@gopher", "@org/backend"},
+		"internal/api/handlers.go":          {"@api-owner"},
+		"docs/intro.md":                     {"docs@example.com"},
+		"docs/guides/setup.md":              {"@lead"},
+		"internal/storage/migrations/1.sql": {"@dba"},
+		"cmd/stresser/main.go":              nil,
+	}
+	for path, want := range cases {
+		if want == nil {
+			assert.Empty(t, OwnersOf(rules, path), path)
+			continue
+		}
+		assert.Equal(t, want, OwnersOf(rules, path), path)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"*.js", "web/app.js", true},
+		{"/build/", "build/out/app", true},
+		{"/build/", "src/build/app", false},
+		{"build/", "src/build/app", true},
+		{"apps/**/config.yaml", "apps/a/b/config.yaml", true},
+		{"apps/**/config.yaml", "apps/config.yaml", true},
+		{"lib?.go", "lib1.go", true},
+		{"lib?.go", "lib/1.go", false},
+	}
+	for _, c := range cases {
+		rule, err := NewRule(c.pattern, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, c.match, rule.Match(c.path), "%s ~ %s", c.pattern, c.path)
+	}
+}
@@ -0,0 +1,1855 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: reviewer/v1/reviewer.proto
+
+package reviewerpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PRStatus int32
+
+const (
+	PRStatus_PR_STATUS_UNSPECIFIED PRStatus = 0
+	PRStatus_PR_STATUS_OPEN        PRStatus = 1
+	PRStatus_PR_STATUS_MERGED      PRStatus = 2
+)
+
+// Enum value maps for PRStatus.
+var (
+	PRStatus_name = map[int32]string{
+		0: "PR_STATUS_UNSPECIFIED",
+		1: "PR_STATUS_OPEN",
+		2: "PR_STATUS_MERGED",
+	}
+	PRStatus_value = map[string]int32{
+		"PR_STATUS_UNSPECIFIED": 0,
+		"PR_STATUS_OPEN":        1,
+		"PR_STATUS_MERGED":      2,
+	}
+)
+
+func (x PRStatus) Enum() *PRStatus {
+	p := new(PRStatus)
+	*p = x
+	return p
+}
+
+func (x PRStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PRStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_reviewer_v1_reviewer_proto_enumTypes[0].Descriptor()
+}
+
+func (PRStatus) Type() protoreflect.EnumType {
+	return &file_reviewer_v1_reviewer_proto_enumTypes[0]
+}
+
+func (x PRStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PRStatus.Descriptor instead.
+func (PRStatus) EnumDescriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{0}
+}
+
+type Team struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Team) Reset() {
+	*x = Team{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Team) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Team) ProtoMessage() {}
+
+func (x *Team) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Team.ProtoReflect.Descriptor instead.
+func (*Team) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Team) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Team) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type User struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	IsActive       bool                   `protobuf:"varint,3,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	TeamId         int32                  `protobuf:"varint,4,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	Seniority      string                 `protobuf:"bytes,5,opt,name=seniority,proto3" json:"seniority,omitempty"`
+	MaxOpenReviews *int32                 `protobuf:"varint,6,opt,name=max_open_reviews,json=maxOpenReviews,proto3,oneof" json:"max_open_reviews,omitempty"`
+	Login          string                 `protobuf:"bytes,7,opt,name=login,proto3" json:"login,omitempty"`
+	ExpertiseTags  []string               `protobuf:"bytes,8,rep,name=expertise_tags,json=expertiseTags,proto3" json:"expertise_tags,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *User) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *User) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *User) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *User) GetTeamId() int32 {
+	if x != nil {
+		return x.TeamId
+	}
+	return 0
+}
+
+func (x *User) GetSeniority() string {
+	if x != nil {
+		return x.Seniority
+	}
+	return ""
+}
+
+func (x *User) GetMaxOpenReviews() int32 {
+	if x != nil && x.MaxOpenReviews != nil {
+		return *x.MaxOpenReviews
+	}
+	return 0
+}
+
+func (x *User) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
+func (x *User) GetExpertiseTags() []string {
+	if x != nil {
+		return x.ExpertiseTags
+	}
+	return nil
+}
+
+type PullRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Status        PRStatus               `protobuf:"varint,3,opt,name=status,proto3,enum=reviewer.v1.PRStatus" json:"status,omitempty"`
+	AuthorId      int32                  `protobuf:"varint,4,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Author        *User                  `protobuf:"bytes,5,opt,name=author,proto3" json:"author,omitempty"`
+	Reviewers     []*User                `protobuf:"bytes,6,rep,name=reviewers,proto3" json:"reviewers,omitempty"`
+	ChangedFiles  []string               `protobuf:"bytes,7,rep,name=changed_files,json=changedFiles,proto3" json:"changed_files,omitempty"`
+	Labels        []string               `protobuf:"bytes,8,rep,name=labels,proto3" json:"labels,omitempty"`
+	WaitingSince  *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=waiting_since,json=waitingSince,proto3" json:"waiting_since,omitempty"`
+	Assignment    *AssignmentReport      `protobuf:"bytes,10,opt,name=assignment,proto3" json:"assignment,omitempty"`
+	Queue         *QueueInfo             `protobuf:"bytes,11,opt,name=queue,proto3" json:"queue,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PullRequest) Reset() {
+	*x = PullRequest{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullRequest) ProtoMessage() {}
+
+func (x *PullRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullRequest.ProtoReflect.Descriptor instead.
+func (*PullRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PullRequest) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PullRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *PullRequest) GetStatus() PRStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PRStatus_PR_STATUS_UNSPECIFIED
+}
+
+func (x *PullRequest) GetAuthorId() int32 {
+	if x != nil {
+		return x.AuthorId
+	}
+	return 0
+}
+
+func (x *PullRequest) GetAuthor() *User {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+func (x *PullRequest) GetReviewers() []*User {
+	if x != nil {
+		return x.Reviewers
+	}
+	return nil
+}
+
+func (x *PullRequest) GetChangedFiles() []string {
+	if x != nil {
+		return x.ChangedFiles
+	}
+	return nil
+}
+
+func (x *PullRequest) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *PullRequest) GetWaitingSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.WaitingSince
+	}
+	return nil
+}
+
+func (x *PullRequest) GetAssignment() *AssignmentReport {
+	if x != nil {
+		return x.Assignment
+	}
+	return nil
+}
+
+func (x *PullRequest) GetQueue() *QueueInfo {
+	if x != nil {
+		return x.Queue
+	}
+	return nil
+}
+
+type AssignmentReport struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Requested       int32                  `protobuf:"varint,1,opt,name=requested,proto3" json:"requested,omitempty"`
+	Assigned        int32                  `protobuf:"varint,2,opt,name=assigned,proto3" json:"assigned,omitempty"`
+	UnderAssigned   bool                   `protobuf:"varint,3,opt,name=under_assigned,json=underAssigned,proto3" json:"under_assigned,omitempty"`
+	FellBack        bool                   `protobuf:"varint,4,opt,name=fell_back,json=fellBack,proto3" json:"fell_back,omitempty"`
+	FallbackTeamIds []int32                `protobuf:"varint,5,rep,packed,name=fallback_team_ids,json=fallbackTeamIds,proto3" json:"fallback_team_ids,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AssignmentReport) Reset() {
+	*x = AssignmentReport{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignmentReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignmentReport) ProtoMessage() {}
+
+func (x *AssignmentReport) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignmentReport.ProtoReflect.Descriptor instead.
+func (*AssignmentReport) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AssignmentReport) GetRequested() int32 {
+	if x != nil {
+		return x.Requested
+	}
+	return 0
+}
+
+func (x *AssignmentReport) GetAssigned() int32 {
+	if x != nil {
+		return x.Assigned
+	}
+	return 0
+}
+
+func (x *AssignmentReport) GetUnderAssigned() bool {
+	if x != nil {
+		return x.UnderAssigned
+	}
+	return false
+}
+
+func (x *AssignmentReport) GetFellBack() bool {
+	if x != nil {
+		return x.FellBack
+	}
+	return false
+}
+
+func (x *AssignmentReport) GetFallbackTeamIds() []int32 {
+	if x != nil {
+		return x.FallbackTeamIds
+	}
+	return nil
+}
+
+type QueueInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Position      int32                  `protobuf:"varint,1,opt,name=position,proto3" json:"position,omitempty"`
+	AgeSeconds    float64                `protobuf:"fixed64,2,opt,name=age_seconds,json=ageSeconds,proto3" json:"age_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueueInfo) Reset() {
+	*x = QueueInfo{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueInfo) ProtoMessage() {}
+
+func (x *QueueInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueInfo.ProtoReflect.Descriptor instead.
+func (*QueueInfo) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *QueueInfo) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *QueueInfo) GetAgeSeconds() float64 {
+	if x != nil {
+		return x.AgeSeconds
+	}
+	return 0
+}
+
+type TeamPolicy struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	TeamId                int32                  `protobuf:"varint,1,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	MinReviewers          int32                  `protobuf:"varint,2,opt,name=min_reviewers,json=minReviewers,proto3" json:"min_reviewers,omitempty"`
+	MaxReviewers          int32                  `protobuf:"varint,3,opt,name=max_reviewers,json=maxReviewers,proto3" json:"max_reviewers,omitempty"`
+	SelfTeamOnly          bool                   `protobuf:"varint,4,opt,name=self_team_only,json=selfTeamOnly,proto3" json:"self_team_only,omitempty"`
+	FallbackTeamIds       []int32                `protobuf:"varint,5,rep,packed,name=fallback_team_ids,json=fallbackTeamIds,proto3" json:"fallback_team_ids,omitempty"`
+	RequiredSeniority     string                 `protobuf:"bytes,6,opt,name=required_seniority,json=requiredSeniority,proto3" json:"required_seniority,omitempty"`
+	DefaultMaxOpenReviews int32                  `protobuf:"varint,7,opt,name=default_max_open_reviews,json=defaultMaxOpenReviews,proto3" json:"default_max_open_reviews,omitempty"`
+	RemindAfterHours      int32                  `protobuf:"varint,8,opt,name=remind_after_hours,json=remindAfterHours,proto3" json:"remind_after_hours,omitempty"`
+	EscalateAfterHours    int32                  `protobuf:"varint,9,opt,name=escalate_after_hours,json=escalateAfterHours,proto3" json:"escalate_after_hours,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *TeamPolicy) Reset() {
+	*x = TeamPolicy{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TeamPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TeamPolicy) ProtoMessage() {}
+
+func (x *TeamPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TeamPolicy.ProtoReflect.Descriptor instead.
+func (*TeamPolicy) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TeamPolicy) GetTeamId() int32 {
+	if x != nil {
+		return x.TeamId
+	}
+	return 0
+}
+
+func (x *TeamPolicy) GetMinReviewers() int32 {
+	if x != nil {
+		return x.MinReviewers
+	}
+	return 0
+}
+
+func (x *TeamPolicy) GetMaxReviewers() int32 {
+	if x != nil {
+		return x.MaxReviewers
+	}
+	return 0
+}
+
+func (x *TeamPolicy) GetSelfTeamOnly() bool {
+	if x != nil {
+		return x.SelfTeamOnly
+	}
+	return false
+}
+
+func (x *TeamPolicy) GetFallbackTeamIds() []int32 {
+	if x != nil {
+		return x.FallbackTeamIds
+	}
+	return nil
+}
+
+func (x *TeamPolicy) GetRequiredSeniority() string {
+	if x != nil {
+		return x.RequiredSeniority
+	}
+	return ""
+}
+
+func (x *TeamPolicy) GetDefaultMaxOpenReviews() int32 {
+	if x != nil {
+		return x.DefaultMaxOpenReviews
+	}
+	return 0
+}
+
+func (x *TeamPolicy) GetRemindAfterHours() int32 {
+	if x != nil {
+		return x.RemindAfterHours
+	}
+	return 0
+}
+
+func (x *TeamPolicy) GetEscalateAfterHours() int32 {
+	if x != nil {
+		return x.EscalateAfterHours
+	}
+	return 0
+}
+
+type HistoryEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	PrId          int32                  `protobuf:"varint,2,opt,name=pr_id,json=prId,proto3" json:"pr_id,omitempty"`
+	Event         string                 `protobuf:"bytes,3,opt,name=event,proto3" json:"event,omitempty"`
+	UserId        *int32                 `protobuf:"varint,4,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	Details       string                 `protobuf:"bytes,5,opt,name=details,proto3" json:"details,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryEntry) Reset() {
+	*x = HistoryEntry{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryEntry) ProtoMessage() {}
+
+func (x *HistoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryEntry.ProtoReflect.Descriptor instead.
+func (*HistoryEntry) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HistoryEntry) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *HistoryEntry) GetPrId() int32 {
+	if x != nil {
+		return x.PrId
+	}
+	return 0
+}
+
+func (x *HistoryEntry) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetUserId() int32 {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return 0
+}
+
+func (x *HistoryEntry) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+func (x *HistoryEntry) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreateTeamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTeamRequest) Reset() {
+	*x = CreateTeamRequest{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTeamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTeamRequest) ProtoMessage() {}
+
+func (x *CreateTeamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTeamRequest.ProtoReflect.Descriptor instead.
+func (*CreateTeamRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreateTeamRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetTeamPolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TeamId        int32                  `protobuf:"varint,1,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTeamPolicyRequest) Reset() {
+	*x = GetTeamPolicyRequest{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTeamPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTeamPolicyRequest) ProtoMessage() {}
+
+func (x *GetTeamPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTeamPolicyRequest.ProtoReflect.Descriptor instead.
+func (*GetTeamPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetTeamPolicyRequest) GetTeamId() int32 {
+	if x != nil {
+		return x.TeamId
+	}
+	return 0
+}
+
+type UpdateTeamPolicyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Policy        *TeamPolicy            `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTeamPolicyRequest) Reset() {
+	*x = UpdateTeamPolicyRequest{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTeamPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTeamPolicyRequest) ProtoMessage() {}
+
+func (x *UpdateTeamPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTeamPolicyRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTeamPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateTeamPolicyRequest) GetPolicy() *TeamPolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+type CreateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	TeamId        int32                  `protobuf:"varint,2,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateUserRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateUserRequest) GetTeamId() int32 {
+	if x != nil {
+		return x.TeamId
+	}
+	return 0
+}
+
+type DeactivateUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int32                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeactivateUserRequest) Reset() {
+	*x = DeactivateUserRequest{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeactivateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeactivateUserRequest) ProtoMessage() {}
+
+func (x *DeactivateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeactivateUserRequest.ProtoReflect.Descriptor instead.
+func (*DeactivateUserRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeactivateUserRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type DeactivateUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeactivateUserResponse) Reset() {
+	*x = DeactivateUserResponse{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeactivateUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeactivateUserResponse) ProtoMessage() {}
+
+func (x *DeactivateUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeactivateUserResponse.ProtoReflect.Descriptor instead.
+func (*DeactivateUserResponse) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{12}
+}
+
+type MassDeactivateTeamUsersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TeamId        int32                  `protobuf:"varint,1,opt,name=team_id,json=teamId,proto3" json:"team_id,omitempty"`
+	UserIds       []int32                `protobuf:"varint,2,rep,packed,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MassDeactivateTeamUsersRequest) Reset() {
+	*x = MassDeactivateTeamUsersRequest{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MassDeactivateTeamUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MassDeactivateTeamUsersRequest) ProtoMessage() {}
+
+func (x *MassDeactivateTeamUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MassDeactivateTeamUsersRequest.ProtoReflect.Descriptor instead.
+func (*MassDeactivateTeamUsersRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *MassDeactivateTeamUsersRequest) GetTeamId() int32 {
+	if x != nil {
+		return x.TeamId
+	}
+	return 0
+}
+
+func (x *MassDeactivateTeamUsersRequest) GetUserIds() []int32 {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+type MassDeactivateTeamUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MassDeactivateTeamUsersResponse) Reset() {
+	*x = MassDeactivateTeamUsersResponse{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MassDeactivateTeamUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MassDeactivateTeamUsersResponse) ProtoMessage() {}
+
+func (x *MassDeactivateTeamUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MassDeactivateTeamUsersResponse.ProtoReflect.Descriptor instead.
+func (*MassDeactivateTeamUsersResponse) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{14}
+}
+
+type CreatePRRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	AuthorId      int32                  `protobuf:"varint,2,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Files         []string               `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
+	Labels        []string               `protobuf:"bytes,4,rep,name=labels,proto3" json:"labels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePRRequest) Reset() {
+	*x = CreatePRRequest{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePRRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePRRequest) ProtoMessage() {}
+
+func (x *CreatePRRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePRRequest.ProtoReflect.Descriptor instead.
+func (*CreatePRRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CreatePRRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreatePRRequest) GetAuthorId() int32 {
+	if x != nil {
+		return x.AuthorId
+	}
+	return 0
+}
+
+func (x *CreatePRRequest) GetFiles() []string {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *CreatePRRequest) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type GetPRRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PrId          int32                  `protobuf:"varint,1,opt,name=pr_id,json=prId,proto3" json:"pr_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPRRequest) Reset() {
+	*x = GetPRRequest{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPRRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPRRequest) ProtoMessage() {}
+
+func (x *GetPRRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPRRequest.ProtoReflect.Descriptor instead.
+func (*GetPRRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetPRRequest) GetPrId() int32 {
+	if x != nil {
+		return x.PrId
+	}
+	return 0
+}
+
+type MergePRRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PrId          int32                  `protobuf:"varint,1,opt,name=pr_id,json=prId,proto3" json:"pr_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MergePRRequest) Reset() {
+	*x = MergePRRequest{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergePRRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergePRRequest) ProtoMessage() {}
+
+func (x *MergePRRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergePRRequest.ProtoReflect.Descriptor instead.
+func (*MergePRRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *MergePRRequest) GetPrId() int32 {
+	if x != nil {
+		return x.PrId
+	}
+	return 0
+}
+
+type GetPRHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PrId          int32                  `protobuf:"varint,1,opt,name=pr_id,json=prId,proto3" json:"pr_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPRHistoryRequest) Reset() {
+	*x = GetPRHistoryRequest{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPRHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPRHistoryRequest) ProtoMessage() {}
+
+func (x *GetPRHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPRHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetPRHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetPRHistoryRequest) GetPrId() int32 {
+	if x != nil {
+		return x.PrId
+	}
+	return 0
+}
+
+type GetPRHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*HistoryEntry        `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPRHistoryResponse) Reset() {
+	*x = GetPRHistoryResponse{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPRHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPRHistoryResponse) ProtoMessage() {}
+
+func (x *GetPRHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPRHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetPRHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetPRHistoryResponse) GetEntries() []*HistoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type ListReviewerPRsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewerId    int32                  `protobuf:"varint,1,opt,name=reviewer_id,json=reviewerId,proto3" json:"reviewer_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewerPRsRequest) Reset() {
+	*x = ListReviewerPRsRequest{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewerPRsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewerPRsRequest) ProtoMessage() {}
+
+func (x *ListReviewerPRsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewerPRsRequest.ProtoReflect.Descriptor instead.
+func (*ListReviewerPRsRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ListReviewerPRsRequest) GetReviewerId() int32 {
+	if x != nil {
+		return x.ReviewerId
+	}
+	return 0
+}
+
+type ListReviewerPRsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PullRequests  []*PullRequest         `protobuf:"bytes,1,rep,name=pull_requests,json=pullRequests,proto3" json:"pull_requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReviewerPRsResponse) Reset() {
+	*x = ListReviewerPRsResponse{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReviewerPRsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReviewerPRsResponse) ProtoMessage() {}
+
+func (x *ListReviewerPRsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReviewerPRsResponse.ProtoReflect.Descriptor instead.
+func (*ListReviewerPRsResponse) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListReviewerPRsResponse) GetPullRequests() []*PullRequest {
+	if x != nil {
+		return x.PullRequests
+	}
+	return nil
+}
+
+type RerollReviewerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PrId          int32                  `protobuf:"varint,1,opt,name=pr_id,json=prId,proto3" json:"pr_id,omitempty"`
+	OldReviewerId int32                  `protobuf:"varint,2,opt,name=old_reviewer_id,json=oldReviewerId,proto3" json:"old_reviewer_id,omitempty"`
+	NewReviewerId int32                  `protobuf:"varint,3,opt,name=new_reviewer_id,json=newReviewerId,proto3" json:"new_reviewer_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RerollReviewerRequest) Reset() {
+	*x = RerollReviewerRequest{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RerollReviewerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RerollReviewerRequest) ProtoMessage() {}
+
+func (x *RerollReviewerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RerollReviewerRequest.ProtoReflect.Descriptor instead.
+func (*RerollReviewerRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RerollReviewerRequest) GetPrId() int32 {
+	if x != nil {
+		return x.PrId
+	}
+	return 0
+}
+
+func (x *RerollReviewerRequest) GetOldReviewerId() int32 {
+	if x != nil {
+		return x.OldReviewerId
+	}
+	return 0
+}
+
+func (x *RerollReviewerRequest) GetNewReviewerId() int32 {
+	if x != nil {
+		return x.NewReviewerId
+	}
+	return 0
+}
+
+type AddReviewerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PrId          int32                  `protobuf:"varint,1,opt,name=pr_id,json=prId,proto3" json:"pr_id,omitempty"`
+	UserId        int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddReviewerRequest) Reset() {
+	*x = AddReviewerRequest{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddReviewerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddReviewerRequest) ProtoMessage() {}
+
+func (x *AddReviewerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddReviewerRequest.ProtoReflect.Descriptor instead.
+func (*AddReviewerRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *AddReviewerRequest) GetPrId() int32 {
+	if x != nil {
+		return x.PrId
+	}
+	return 0
+}
+
+func (x *AddReviewerRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type RemoveReviewerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PrId          int32                  `protobuf:"varint,1,opt,name=pr_id,json=prId,proto3" json:"pr_id,omitempty"`
+	UserId        int32                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveReviewerRequest) Reset() {
+	*x = RemoveReviewerRequest{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveReviewerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveReviewerRequest) ProtoMessage() {}
+
+func (x *RemoveReviewerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveReviewerRequest.ProtoReflect.Descriptor instead.
+func (*RemoveReviewerRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RemoveReviewerRequest) GetPrId() int32 {
+	if x != nil {
+		return x.PrId
+	}
+	return 0
+}
+
+func (x *RemoveReviewerRequest) GetUserId() int32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type GetReviewerStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReviewerStatsRequest) Reset() {
+	*x = GetReviewerStatsRequest{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReviewerStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReviewerStatsRequest) ProtoMessage() {}
+
+func (x *GetReviewerStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReviewerStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetReviewerStatsRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{25}
+}
+
+type GetReviewerStatsResponse struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	ReviewerAssignmentsCount map[int32]int32        `protobuf:"bytes,1,rep,name=reviewer_assignments_count,json=reviewerAssignmentsCount,proto3" json:"reviewer_assignments_count,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *GetReviewerStatsResponse) Reset() {
+	*x = GetReviewerStatsResponse{}
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReviewerStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReviewerStatsResponse) ProtoMessage() {}
+
+func (x *GetReviewerStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_reviewer_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReviewerStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetReviewerStatsResponse) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_reviewer_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetReviewerStatsResponse) GetReviewerAssignmentsCount() map[int32]int32 {
+	if x != nil {
+		return x.ReviewerAssignmentsCount
+	}
+	return nil
+}
+
+var File_reviewer_v1_reviewer_proto protoreflect.FileDescriptor
+
+const file_reviewer_v1_reviewer_proto_rawDesc = "" +
+	"\n" +
+	"\x1areviewer/v1/reviewer.proto\x12\vreviewer.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"*\n" +
+	"\x04Team\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\xff\x01\n" +
+	"\x04User\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1b\n" +
+	"\tis_active\x18\x03 \x01(\bR\bisActive\x12\x17\n" +
+	"\ateam_id\x18\x04 \x01(\x05R\x06teamId\x12\x1c\n" +
+	"\tseniority\x18\x05 \x01(\tR\tseniority\x12-\n" +
+	"\x10max_open_reviews\x18\x06 \x01(\x05H\x00R\x0emaxOpenReviews\x88\x01\x01\x12\x14\n" +
+	"\x05login\x18\a \x01(\tR\x05login\x12%\n" +
+	"\x0eexpertise_tags\x18\b \x03(\tR\rexpertiseTagsB\x13\n" +
+	"\x11_max_open_reviews\"\xc6\x03\n" +
+	"\vPullRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12-\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x15.reviewer.v1.PRStatusR\x06status\x12\x1b\n" +
+	"\tauthor_id\x18\x04 \x01(\x05R\bauthorId\x12)\n" +
+	"\x06author\x18\x05 \x01(\v2\x11.reviewer.v1.UserR\x06author\x12/\n" +
+	"\treviewers\x18\x06 \x03(\v2\x11.reviewer.v1.UserR\treviewers\x12#\n" +
+	"\rchanged_files\x18\a \x03(\tR\fchangedFiles\x12\x16\n" +
+	"\x06labels\x18\b \x03(\tR\x06labels\x12?\n" +
+	"\rwaiting_since\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\fwaitingSince\x12=\n" +
+	"\n" +
+	"assignment\x18\n" +
+	" \x01(\v2\x1d.reviewer.v1.AssignmentReportR\n" +
+	"assignment\x12,\n" +
+	"\x05queue\x18\v \x01(\v2\x16.reviewer.v1.QueueInfoR\x05queue\"\xbc\x01\n" +
+	"\x10AssignmentReport\x12\x1c\n" +
+	"\trequested\x18\x01 \x01(\x05R\trequested\x12\x1a\n" +
+	"\bassigned\x18\x02 \x01(\x05R\bassigned\x12%\n" +
+	"\x0eunder_assigned\x18\x03 \x01(\bR\runderAssigned\x12\x1b\n" +
+	"\tfell_back\x18\x04 \x01(\bR\bfellBack\x12*\n" +
+	"\x11fallback_team_ids\x18\x05 \x03(\x05R\x0ffallbackTeamIds\"H\n" +
+	"\tQueueInfo\x12\x1a\n" +
+	"\bposition\x18\x01 \x01(\x05R\bposition\x12\x1f\n" +
+	"\vage_seconds\x18\x02 \x01(\x01R\n" +
+	"ageSeconds\"\x89\x03\n" +
+	"\n" +
+	"TeamPolicy\x12\x17\n" +
+	"\ateam_id\x18\x01 \x01(\x05R\x06teamId\x12#\n" +
+	"\rmin_reviewers\x18\x02 \x01(\x05R\fminReviewers\x12#\n" +
+	"\rmax_reviewers\x18\x03 \x01(\x05R\fmaxReviewers\x12$\n" +
+	"\x0eself_team_only\x18\x04 \x01(\bR\fselfTeamOnly\x12*\n" +
+	"\x11fallback_team_ids\x18\x05 \x03(\x05R\x0ffallbackTeamIds\x12-\n" +
+	"\x12required_seniority\x18\x06 \x01(\tR\x11requiredSeniority\x127\n" +
+	"\x18default_max_open_reviews\x18\a \x01(\x05R\x15defaultMaxOpenReviews\x12,\n" +
+	"\x12remind_after_hours\x18\b \x01(\x05R\x10remindAfterHours\x120\n" +
+	"\x14escalate_after_hours\x18\t \x01(\x05R\x12escalateAfterHours\"\xc8\x01\n" +
+	"\fHistoryEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x13\n" +
+	"\x05pr_id\x18\x02 \x01(\x05R\x04prId\x12\x14\n" +
+	"\x05event\x18\x03 \x01(\tR\x05event\x12\x1c\n" +
+	"\auser_id\x18\x04 \x01(\x05H\x00R\x06userId\x88\x01\x01\x12\x18\n" +
+	"\adetails\x18\x05 \x01(\tR\adetails\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAtB\n" +
+	"\n" +
+	"\b_user_id\"'\n" +
+	"\x11CreateTeamRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"/\n" +
+	"\x14GetTeamPolicyRequest\x12\x17\n" +
+	"\ateam_id\x18\x01 \x01(\x05R\x06teamId\"J\n" +
+	"\x17UpdateTeamPolicyRequest\x12/\n" +
+	"\x06policy\x18\x01 \x01(\v2\x17.reviewer.v1.TeamPolicyR\x06policy\"@\n" +
+	"\x11CreateUserRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x17\n" +
+	"\ateam_id\x18\x02 \x01(\x05R\x06teamId\"0\n" +
+	"\x15DeactivateUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x05R\x06userId\"\x18\n" +
+	"\x16DeactivateUserResponse\"T\n" +
+	"\x1eMassDeactivateTeamUsersRequest\x12\x17\n" +
+	"\ateam_id\x18\x01 \x01(\x05R\x06teamId\x12\x19\n" +
+	"\buser_ids\x18\x02 \x03(\x05R\auserIds\"!\n" +
+	"\x1fMassDeactivateTeamUsersResponse\"r\n" +
+	"\x0fCreatePRRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x1b\n" +
+	"\tauthor_id\x18\x02 \x01(\x05R\bauthorId\x12\x14\n" +
+	"\x05files\x18\x03 \x03(\tR\x05files\x12\x16\n" +
+	"\x06labels\x18\x04 \x03(\tR\x06labels\"#\n" +
+	"\fGetPRRequest\x12\x13\n" +
+	"\x05pr_id\x18\x01 \x01(\x05R\x04prId\"%\n" +
+	"\x0eMergePRRequest\x12\x13\n" +
+	"\x05pr_id\x18\x01 \x01(\x05R\x04prId\"*\n" +
+	"\x13GetPRHistoryRequest\x12\x13\n" +
+	"\x05pr_id\x18\x01 \x01(\x05R\x04prId\"K\n" +
+	"\x14GetPRHistoryResponse\x123\n" +
+	"\aentries\x18\x01 \x03(\v2\x19.reviewer.v1.HistoryEntryR\aentries\"9\n" +
+	"\x16ListReviewerPRsRequest\x12\x1f\n" +
+	"\vreviewer_id\x18\x01 \x01(\x05R\n" +
+	"reviewerId\"X\n" +
+	"\x17ListReviewerPRsResponse\x12=\n" +
+	"\rpull_requests\x18\x01 \x03(\v2\x18.reviewer.v1.PullRequestR\fpullRequests\"|\n" +
+	"\x15RerollReviewerRequest\x12\x13\n" +
+	"\x05pr_id\x18\x01 \x01(\x05R\x04prId\x12&\n" +
+	"\x0fold_reviewer_id\x18\x02 \x01(\x05R\roldReviewerId\x12&\n" +
+	"\x0fnew_reviewer_id\x18\x03 \x01(\x05R\rnewReviewerId\"B\n" +
+	"\x12AddReviewerRequest\x12\x13\n" +
+	"\x05pr_id\x18\x01 \x01(\x05R\x04prId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x05R\x06userId\"E\n" +
+	"\x15RemoveReviewerRequest\x12\x13\n" +
+	"\x05pr_id\x18\x01 \x01(\x05R\x04prId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x05R\x06userId\"\x19\n" +
+	"\x17GetReviewerStatsRequest\"\xeb\x01\n" +
+	"\x18GetReviewerStatsResponse\x12\x81\x01\n" +
+	"\x1areviewer_assignments_count\x18\x01 \x03(\v2C.reviewer.v1.GetReviewerStatsResponse.ReviewerAssignmentsCountEntryR\x18reviewerAssignmentsCount\x1aK\n" +
+	"\x1dReviewerAssignmentsCountEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x05R\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01*O\n" +
+	"\bPRStatus\x12\x19\n" +
+	"\x15PR_STATUS_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0ePR_STATUS_OPEN\x10\x01\x12\x14\n" +
+	"\x10PR_STATUS_MERGED\x10\x022\xc6\t\n" +
+	"\x0fReviewerService\x12?\n" +
+	"\n" +
+	"CreateTeam\x12\x1e.reviewer.v1.CreateTeamRequest\x1a\x11.reviewer.v1.Team\x12K\n" +
+	"\rGetTeamPolicy\x12!.reviewer.v1.GetTeamPolicyRequest\x1a\x17.reviewer.v1.TeamPolicy\x12Q\n" +
+	"\x10UpdateTeamPolicy\x12$.reviewer.v1.UpdateTeamPolicyRequest\x1a\x17.reviewer.v1.TeamPolicy\x12?\n" +
+	"\n" +
+	"CreateUser\x12\x1e.reviewer.v1.CreateUserRequest\x1a\x11.reviewer.v1.User\x12Y\n" +
+	"\x0eDeactivateUser\x12\".reviewer.v1.DeactivateUserRequest\x1a#.reviewer.v1.DeactivateUserResponse\x12t\n" +
+	"\x17MassDeactivateTeamUsers\x12+.reviewer.v1.MassDeactivateTeamUsersRequest\x1a,.reviewer.v1.MassDeactivateTeamUsersResponse\x12B\n" +
+	"\bCreatePR\x12\x1c.reviewer.v1.CreatePRRequest\x1a\x18.reviewer.v1.PullRequest\x12<\n" +
+	"\x05GetPR\x12\x19.reviewer.v1.GetPRRequest\x1a\x18.reviewer.v1.PullRequest\x12@\n" +
+	"\aMergePR\x12\x1b.reviewer.v1.MergePRRequest\x1a\x18.reviewer.v1.PullRequest\x12S\n" +
+	"\fGetPRHistory\x12 .reviewer.v1.GetPRHistoryRequest\x1a!.reviewer.v1.GetPRHistoryResponse\x12\\\n" +
+	"\x0fListReviewerPRs\x12#.reviewer.v1.ListReviewerPRsRequest\x1a$.reviewer.v1.ListReviewerPRsResponse\x12N\n" +
+	"\x0eRerollReviewer\x12\".reviewer.v1.RerollReviewerRequest\x1a\x18.reviewer.v1.PullRequest\x12H\n" +
+	"\vAddReviewer\x12\x1f.reviewer.v1.AddReviewerRequest\x1a\x18.reviewer.v1.PullRequest\x12N\n" +
+	"\x0eRemoveReviewer\x12\".reviewer.v1.RemoveReviewerRequest\x1a\x18.reviewer.v1.PullRequest\x12_\n" +
+	"\x10GetReviewerStats\x12$.reviewer.v1.GetReviewerStatsRequest\x1a%.reviewer.v1.GetReviewerStatsResponseBEZCgithub.com/Shishlyannikovvv/project-avito/pkg/reviewerpb;reviewerpbb\x06proto3"
+
+var (
+	file_reviewer_v1_reviewer_proto_rawDescOnce sync.Once
+	file_reviewer_v1_reviewer_proto_rawDescData []byte
+)
+
+func file_reviewer_v1_reviewer_proto_rawDescGZIP() []byte {
+	file_reviewer_v1_reviewer_proto_rawDescOnce.Do(func() {
+		file_reviewer_v1_reviewer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_reviewer_v1_reviewer_proto_rawDesc), len(file_reviewer_v1_reviewer_proto_rawDesc)))
+	})
+	return file_reviewer_v1_reviewer_proto_rawDescData
+}
+
+var file_reviewer_v1_reviewer_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_reviewer_v1_reviewer_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
+var file_reviewer_v1_reviewer_proto_goTypes = []any{
+	(PRStatus)(0),                           // 0: reviewer.v1.PRStatus
+	(*Team)(nil),                            // 1: reviewer.v1.Team
+	(*User)(nil),                            // 2: reviewer.v1.User
+	(*PullRequest)(nil),                     // 3: reviewer.v1.PullRequest
+	(*AssignmentReport)(nil),                // 4: reviewer.v1.AssignmentReport
+	(*QueueInfo)(nil),                       // 5: reviewer.v1.QueueInfo
+	(*TeamPolicy)(nil),                      // 6: reviewer.v1.TeamPolicy
+	(*HistoryEntry)(nil),                    // 7: reviewer.v1.HistoryEntry
+	(*CreateTeamRequest)(nil),               // 8: reviewer.v1.CreateTeamRequest
+	(*GetTeamPolicyRequest)(nil),            // 9: reviewer.v1.GetTeamPolicyRequest
+	(*UpdateTeamPolicyRequest)(nil),         // 10: reviewer.v1.UpdateTeamPolicyRequest
+	(*CreateUserRequest)(nil),               // 11: reviewer.v1.CreateUserRequest
+	(*DeactivateUserRequest)(nil),           // 12: reviewer.v1.DeactivateUserRequest
+	(*DeactivateUserResponse)(nil),          // 13: reviewer.v1.DeactivateUserResponse
+	(*MassDeactivateTeamUsersRequest)(nil),  // 14: reviewer.v1.MassDeactivateTeamUsersRequest
+	(*MassDeactivateTeamUsersResponse)(nil), // 15: reviewer.v1.MassDeactivateTeamUsersResponse
+	(*CreatePRRequest)(nil),                 // 16: reviewer.v1.CreatePRRequest
+	(*GetPRRequest)(nil),                    // 17: reviewer.v1.GetPRRequest
+	(*MergePRRequest)(nil),                  // 18: reviewer.v1.MergePRRequest
+	(*GetPRHistoryRequest)(nil),             // 19: reviewer.v1.GetPRHistoryRequest
+	(*GetPRHistoryResponse)(nil),            // 20: reviewer.v1.GetPRHistoryResponse
+	(*ListReviewerPRsRequest)(nil),          // 21: reviewer.v1.ListReviewerPRsRequest
+	(*ListReviewerPRsResponse)(nil),         // 22: reviewer.v1.ListReviewerPRsResponse
+	(*RerollReviewerRequest)(nil),           // 23: reviewer.v1.RerollReviewerRequest
+	(*AddReviewerRequest)(nil),              // 24: reviewer.v1.AddReviewerRequest
+	(*RemoveReviewerRequest)(nil),           // 25: reviewer.v1.RemoveReviewerRequest
+	(*GetReviewerStatsRequest)(nil),         // 26: reviewer.v1.GetReviewerStatsRequest
+	(*GetReviewerStatsResponse)(nil),        // 27: reviewer.v1.GetReviewerStatsResponse
+	nil,                                     // 28: reviewer.v1.GetReviewerStatsResponse.ReviewerAssignmentsCountEntry
+	(*timestamppb.Timestamp)(nil),           // 29: google.protobuf.Timestamp
+}
+var file_reviewer_v1_reviewer_proto_depIdxs = []int32{
+	0,  // 0: reviewer.v1.PullRequest.status:type_name -> reviewer.v1.PRStatus
+	2,  // 1: reviewer.v1.PullRequest.author:type_name -> reviewer.v1.User
+	2,  // 2: reviewer.v1.PullRequest.reviewers:type_name -> reviewer.v1.User
+	29, // 3: reviewer.v1.PullRequest.waiting_since:type_name -> google.protobuf.Timestamp
+	4,  // 4: reviewer.v1.PullRequest.assignment:type_name -> reviewer.v1.AssignmentReport
+	5,  // 5: reviewer.v1.PullRequest.queue:type_name -> reviewer.v1.QueueInfo
+	29, // 6: reviewer.v1.HistoryEntry.created_at:type_name -> google.protobuf.Timestamp
+	6,  // 7: reviewer.v1.UpdateTeamPolicyRequest.policy:type_name -> reviewer.v1.TeamPolicy
+	7,  // 8: reviewer.v1.GetPRHistoryResponse.entries:type_name -> reviewer.v1.HistoryEntry
+	3,  // 9: reviewer.v1.ListReviewerPRsResponse.pull_requests:type_name -> reviewer.v1.PullRequest
+	28, // 10: reviewer.v1.GetReviewerStatsResponse.reviewer_assignments_count:type_name -> reviewer.v1.GetReviewerStatsResponse.ReviewerAssignmentsCountEntry
+	8,  // 11: reviewer.v1.ReviewerService.CreateTeam:input_type -> reviewer.v1.CreateTeamRequest
+	9,  // 12: reviewer.v1.ReviewerService.GetTeamPolicy:input_type -> reviewer.v1.GetTeamPolicyRequest
+	10, // 13: reviewer.v1.ReviewerService.UpdateTeamPolicy:input_type -> reviewer.v1.UpdateTeamPolicyRequest
+	11, // 14: reviewer.v1.ReviewerService.CreateUser:input_type -> reviewer.v1.CreateUserRequest
+	12, // 15: reviewer.v1.ReviewerService.DeactivateUser:input_type -> reviewer.v1.DeactivateUserRequest
+	14, // 16: reviewer.v1.ReviewerService.MassDeactivateTeamUsers:input_type -> reviewer.v1.MassDeactivateTeamUsersRequest
+	16, // 17: reviewer.v1.ReviewerService.CreatePR:input_type -> reviewer.v1.CreatePRRequest
+	17, // 18: reviewer.v1.ReviewerService.GetPR:input_type -> reviewer.v1.GetPRRequest
+	18, // 19: reviewer.v1.ReviewerService.MergePR:input_type -> reviewer.v1.MergePRRequest
+	19, // 20: reviewer.v1.ReviewerService.GetPRHistory:input_type -> reviewer.v1.GetPRHistoryRequest
+	21, // 21: reviewer.v1.ReviewerService.ListReviewerPRs:input_type -> reviewer.v1.ListReviewerPRsRequest
+	23, // 22: reviewer.v1.ReviewerService.RerollReviewer:input_type -> reviewer.v1.RerollReviewerRequest
+	24, // 23: reviewer.v1.ReviewerService.AddReviewer:input_type -> reviewer.v1.AddReviewerRequest
+	25, // 24: reviewer.v1.ReviewerService.RemoveReviewer:input_type -> reviewer.v1.RemoveReviewerRequest
+	26, // 25: reviewer.v1.ReviewerService.GetReviewerStats:input_type -> reviewer.v1.GetReviewerStatsRequest
+	1,  // 26: reviewer.v1.ReviewerService.CreateTeam:output_type -> reviewer.v1.Team
+	6,  // 27: reviewer.v1.ReviewerService.GetTeamPolicy:output_type -> reviewer.v1.TeamPolicy
+	6,  // 28: reviewer.v1.ReviewerService.UpdateTeamPolicy:output_type -> reviewer.v1.TeamPolicy
+	2,  // 29: reviewer.v1.ReviewerService.CreateUser:output_type -> reviewer.v1.User
+	13, // 30: reviewer.v1.ReviewerService.DeactivateUser:output_type -> reviewer.v1.DeactivateUserResponse
+	15, // 31: reviewer.v1.ReviewerService.MassDeactivateTeamUsers:output_type -> reviewer.v1.MassDeactivateTeamUsersResponse
+	3,  // 32: reviewer.v1.ReviewerService.CreatePR:output_type -> reviewer.v1.PullRequest
+	3,  // 33: reviewer.v1.ReviewerService.GetPR:output_type -> reviewer.v1.PullRequest
+	3,  // 34: reviewer.v1.ReviewerService.MergePR:output_type -> reviewer.v1.PullRequest
+	20, // 35: reviewer.v1.ReviewerService.GetPRHistory:output_type -> reviewer.v1.GetPRHistoryResponse
+	22, // 36: reviewer.v1.ReviewerService.ListReviewerPRs:output_type -> reviewer.v1.ListReviewerPRsResponse
+	3,  // 37: reviewer.v1.ReviewerService.RerollReviewer:output_type -> reviewer.v1.PullRequest
+	3,  // 38: reviewer.v1.ReviewerService.AddReviewer:output_type -> reviewer.v1.PullRequest
+	3,  // 39: reviewer.v1.ReviewerService.RemoveReviewer:output_type -> reviewer.v1.PullRequest
+	27, // 40: reviewer.v1.ReviewerService.GetReviewerStats:output_type -> reviewer.v1.GetReviewerStatsResponse
+	26, // [26:41] is the sub-list for method output_type
+	11, // [11:26] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_reviewer_v1_reviewer_proto_init() }
+func file_reviewer_v1_reviewer_proto_init() {
+	if File_reviewer_v1_reviewer_proto != nil {
+		return
+	}
+	file_reviewer_v1_reviewer_proto_msgTypes[1].OneofWrappers = []any{}
+	file_reviewer_v1_reviewer_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_reviewer_v1_reviewer_proto_rawDesc), len(file_reviewer_v1_reviewer_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   28,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_reviewer_v1_reviewer_proto_goTypes,
+		DependencyIndexes: file_reviewer_v1_reviewer_proto_depIdxs,
+		EnumInfos:         file_reviewer_v1_reviewer_proto_enumTypes,
+		MessageInfos:      file_reviewer_v1_reviewer_proto_msgTypes,
+	}.Build()
+	File_reviewer_v1_reviewer_proto = out.File
+	file_reviewer_v1_reviewer_proto_goTypes = nil
+	file_reviewer_v1_reviewer_proto_depIdxs = nil
+}
@@ -0,0 +1,653 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: reviewer/v1/reviewer.proto
+
+package reviewerpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ReviewerService_CreateTeam_FullMethodName              = "/reviewer.v1.ReviewerService/CreateTeam"
+	ReviewerService_GetTeamPolicy_FullMethodName           = "/reviewer.v1.ReviewerService/GetTeamPolicy"
+	ReviewerService_UpdateTeamPolicy_FullMethodName        = "/reviewer.v1.ReviewerService/UpdateTeamPolicy"
+	ReviewerService_CreateUser_FullMethodName              = "/reviewer.v1.ReviewerService/CreateUser"
+	ReviewerService_DeactivateUser_FullMethodName          = "/reviewer.v1.ReviewerService/DeactivateUser"
+	ReviewerService_MassDeactivateTeamUsers_FullMethodName = "/reviewer.v1.ReviewerService/MassDeactivateTeamUsers"
+	ReviewerService_CreatePR_FullMethodName                = "/reviewer.v1.ReviewerService/CreatePR"
+	ReviewerService_GetPR_FullMethodName                   = "/reviewer.v1.ReviewerService/GetPR"
+	ReviewerService_MergePR_FullMethodName                 = "/reviewer.v1.ReviewerService/MergePR"
+	ReviewerService_GetPRHistory_FullMethodName            = "/reviewer.v1.ReviewerService/GetPRHistory"
+	ReviewerService_ListReviewerPRs_FullMethodName         = "/reviewer.v1.ReviewerService/ListReviewerPRs"
+	ReviewerService_RerollReviewer_FullMethodName          = "/reviewer.v1.ReviewerService/RerollReviewer"
+	ReviewerService_AddReviewer_FullMethodName             = "/reviewer.v1.ReviewerService/AddReviewer"
+	ReviewerService_RemoveReviewer_FullMethodName          = "/reviewer.v1.ReviewerService/RemoveReviewer"
+	ReviewerService_GetReviewerStats_FullMethodName        = "/reviewer.v1.ReviewerService/GetReviewerStats"
+)
+
+// ReviewerServiceClient is the client API for ReviewerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ReviewerServiceClient interface {
+	CreateTeam(ctx context.Context, in *CreateTeamRequest, opts ...grpc.CallOption) (*Team, error)
+	GetTeamPolicy(ctx context.Context, in *GetTeamPolicyRequest, opts ...grpc.CallOption) (*TeamPolicy, error)
+	UpdateTeamPolicy(ctx context.Context, in *UpdateTeamPolicyRequest, opts ...grpc.CallOption) (*TeamPolicy, error)
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error)
+	DeactivateUser(ctx context.Context, in *DeactivateUserRequest, opts ...grpc.CallOption) (*DeactivateUserResponse, error)
+	MassDeactivateTeamUsers(ctx context.Context, in *MassDeactivateTeamUsersRequest, opts ...grpc.CallOption) (*MassDeactivateTeamUsersResponse, error)
+	CreatePR(ctx context.Context, in *CreatePRRequest, opts ...grpc.CallOption) (*PullRequest, error)
+	GetPR(ctx context.Context, in *GetPRRequest, opts ...grpc.CallOption) (*PullRequest, error)
+	MergePR(ctx context.Context, in *MergePRRequest, opts ...grpc.CallOption) (*PullRequest, error)
+	GetPRHistory(ctx context.Context, in *GetPRHistoryRequest, opts ...grpc.CallOption) (*GetPRHistoryResponse, error)
+	ListReviewerPRs(ctx context.Context, in *ListReviewerPRsRequest, opts ...grpc.CallOption) (*ListReviewerPRsResponse, error)
+	RerollReviewer(ctx context.Context, in *RerollReviewerRequest, opts ...grpc.CallOption) (*PullRequest, error)
+	AddReviewer(ctx context.Context, in *AddReviewerRequest, opts ...grpc.CallOption) (*PullRequest, error)
+	RemoveReviewer(ctx context.Context, in *RemoveReviewerRequest, opts ...grpc.CallOption) (*PullRequest, error)
+	GetReviewerStats(ctx context.Context, in *GetReviewerStatsRequest, opts ...grpc.CallOption) (*GetReviewerStatsResponse, error)
+}
+
+type reviewerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReviewerServiceClient(cc grpc.ClientConnInterface) ReviewerServiceClient {
+	return &reviewerServiceClient{cc}
+}
+
+func (c *reviewerServiceClient) CreateTeam(ctx context.Context, in *CreateTeamRequest, opts ...grpc.CallOption) (*Team, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Team)
+	err := c.cc.Invoke(ctx, ReviewerService_CreateTeam_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewerServiceClient) GetTeamPolicy(ctx context.Context, in *GetTeamPolicyRequest, opts ...grpc.CallOption) (*TeamPolicy, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TeamPolicy)
+	err := c.cc.Invoke(ctx, ReviewerService_GetTeamPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewerServiceClient) UpdateTeamPolicy(ctx context.Context, in *UpdateTeamPolicyRequest, opts ...grpc.CallOption) (*TeamPolicy, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TeamPolicy)
+	err := c.cc.Invoke(ctx, ReviewerService_UpdateTeamPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewerServiceClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, ReviewerService_CreateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewerServiceClient) DeactivateUser(ctx context.Context, in *DeactivateUserRequest, opts ...grpc.CallOption) (*DeactivateUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeactivateUserResponse)
+	err := c.cc.Invoke(ctx, ReviewerService_DeactivateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewerServiceClient) MassDeactivateTeamUsers(ctx context.Context, in *MassDeactivateTeamUsersRequest, opts ...grpc.CallOption) (*MassDeactivateTeamUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MassDeactivateTeamUsersResponse)
+	err := c.cc.Invoke(ctx, ReviewerService_MassDeactivateTeamUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewerServiceClient) CreatePR(ctx context.Context, in *CreatePRRequest, opts ...grpc.CallOption) (*PullRequest, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PullRequest)
+	err := c.cc.Invoke(ctx, ReviewerService_CreatePR_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewerServiceClient) GetPR(ctx context.Context, in *GetPRRequest, opts ...grpc.CallOption) (*PullRequest, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PullRequest)
+	err := c.cc.Invoke(ctx, ReviewerService_GetPR_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewerServiceClient) MergePR(ctx context.Context, in *MergePRRequest, opts ...grpc.CallOption) (*PullRequest, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PullRequest)
+	err := c.cc.Invoke(ctx, ReviewerService_MergePR_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewerServiceClient) GetPRHistory(ctx context.Context, in *GetPRHistoryRequest, opts ...grpc.CallOption) (*GetPRHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPRHistoryResponse)
+	err := c.cc.Invoke(ctx, ReviewerService_GetPRHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewerServiceClient) ListReviewerPRs(ctx context.Context, in *ListReviewerPRsRequest, opts ...grpc.CallOption) (*ListReviewerPRsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReviewerPRsResponse)
+	err := c.cc.Invoke(ctx, ReviewerService_ListReviewerPRs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewerServiceClient) RerollReviewer(ctx context.Context, in *RerollReviewerRequest, opts ...grpc.CallOption) (*PullRequest, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PullRequest)
+	err := c.cc.Invoke(ctx, ReviewerService_RerollReviewer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewerServiceClient) AddReviewer(ctx context.Context, in *AddReviewerRequest, opts ...grpc.CallOption) (*PullRequest, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PullRequest)
+	err := c.cc.Invoke(ctx, ReviewerService_AddReviewer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewerServiceClient) RemoveReviewer(ctx context.Context, in *RemoveReviewerRequest, opts ...grpc.CallOption) (*PullRequest, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PullRequest)
+	err := c.cc.Invoke(ctx, ReviewerService_RemoveReviewer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewerServiceClient) GetReviewerStats(ctx context.Context, in *GetReviewerStatsRequest, opts ...grpc.CallOption) (*GetReviewerStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetReviewerStatsResponse)
+	err := c.cc.Invoke(ctx, ReviewerService_GetReviewerStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReviewerServiceServer is the server API for ReviewerService service.
+// All implementations must embed UnimplementedReviewerServiceServer
+// for forward compatibility.
+type ReviewerServiceServer interface {
+	CreateTeam(context.Context, *CreateTeamRequest) (*Team, error)
+	GetTeamPolicy(context.Context, *GetTeamPolicyRequest) (*TeamPolicy, error)
+	UpdateTeamPolicy(context.Context, *UpdateTeamPolicyRequest) (*TeamPolicy, error)
+	CreateUser(context.Context, *CreateUserRequest) (*User, error)
+	DeactivateUser(context.Context, *DeactivateUserRequest) (*DeactivateUserResponse, error)
+	MassDeactivateTeamUsers(context.Context, *MassDeactivateTeamUsersRequest) (*MassDeactivateTeamUsersResponse, error)
+	CreatePR(context.Context, *CreatePRRequest) (*PullRequest, error)
+	GetPR(context.Context, *GetPRRequest) (*PullRequest, error)
+	MergePR(context.Context, *MergePRRequest) (*PullRequest, error)
+	GetPRHistory(context.Context, *GetPRHistoryRequest) (*GetPRHistoryResponse, error)
+	ListReviewerPRs(context.Context, *ListReviewerPRsRequest) (*ListReviewerPRsResponse, error)
+	RerollReviewer(context.Context, *RerollReviewerRequest) (*PullRequest, error)
+	AddReviewer(context.Context, *AddReviewerRequest) (*PullRequest, error)
+	RemoveReviewer(context.Context, *RemoveReviewerRequest) (*PullRequest, error)
+	GetReviewerStats(context.Context, *GetReviewerStatsRequest) (*GetReviewerStatsResponse, error)
+	mustEmbedUnimplementedReviewerServiceServer()
+}
+
+// UnimplementedReviewerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedReviewerServiceServer struct{}
+
+func (UnimplementedReviewerServiceServer) CreateTeam(context.Context, *CreateTeamRequest) (*Team, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTeam not implemented")
+}
+func (UnimplementedReviewerServiceServer) GetTeamPolicy(context.Context, *GetTeamPolicyRequest) (*TeamPolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTeamPolicy not implemented")
+}
+func (UnimplementedReviewerServiceServer) UpdateTeamPolicy(context.Context, *UpdateTeamPolicyRequest) (*TeamPolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTeamPolicy not implemented")
+}
+func (UnimplementedReviewerServiceServer) CreateUser(context.Context, *CreateUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedReviewerServiceServer) DeactivateUser(context.Context, *DeactivateUserRequest) (*DeactivateUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeactivateUser not implemented")
+}
+func (UnimplementedReviewerServiceServer) MassDeactivateTeamUsers(context.Context, *MassDeactivateTeamUsersRequest) (*MassDeactivateTeamUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MassDeactivateTeamUsers not implemented")
+}
+func (UnimplementedReviewerServiceServer) CreatePR(context.Context, *CreatePRRequest) (*PullRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePR not implemented")
+}
+func (UnimplementedReviewerServiceServer) GetPR(context.Context, *GetPRRequest) (*PullRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPR not implemented")
+}
+func (UnimplementedReviewerServiceServer) MergePR(context.Context, *MergePRRequest) (*PullRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergePR not implemented")
+}
+func (UnimplementedReviewerServiceServer) GetPRHistory(context.Context, *GetPRHistoryRequest) (*GetPRHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPRHistory not implemented")
+}
+func (UnimplementedReviewerServiceServer) ListReviewerPRs(context.Context, *ListReviewerPRsRequest) (*ListReviewerPRsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReviewerPRs not implemented")
+}
+func (UnimplementedReviewerServiceServer) RerollReviewer(context.Context, *RerollReviewerRequest) (*PullRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RerollReviewer not implemented")
+}
+func (UnimplementedReviewerServiceServer) AddReviewer(context.Context, *AddReviewerRequest) (*PullRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddReviewer not implemented")
+}
+func (UnimplementedReviewerServiceServer) RemoveReviewer(context.Context, *RemoveReviewerRequest) (*PullRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveReviewer not implemented")
+}
+func (UnimplementedReviewerServiceServer) GetReviewerStats(context.Context, *GetReviewerStatsRequest) (*GetReviewerStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReviewerStats not implemented")
+}
+func (UnimplementedReviewerServiceServer) mustEmbedUnimplementedReviewerServiceServer() {}
+func (UnimplementedReviewerServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeReviewerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ReviewerServiceServer will
+// result in compilation errors.
+type UnsafeReviewerServiceServer interface {
+	mustEmbedUnimplementedReviewerServiceServer()
+}
+
+func RegisterReviewerServiceServer(s grpc.ServiceRegistrar, srv ReviewerServiceServer) {
+	// If the following call pancis, it indicates UnimplementedReviewerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ReviewerService_ServiceDesc, srv)
+}
+
+func _ReviewerService_CreateTeam_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTeamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewerServiceServer).CreateTeam(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReviewerService_CreateTeam_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewerServiceServer).CreateTeam(ctx, req.(*CreateTeamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewerService_GetTeamPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTeamPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewerServiceServer).GetTeamPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReviewerService_GetTeamPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewerServiceServer).GetTeamPolicy(ctx, req.(*GetTeamPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewerService_UpdateTeamPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTeamPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewerServiceServer).UpdateTeamPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReviewerService_UpdateTeamPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewerServiceServer).UpdateTeamPolicy(ctx, req.(*UpdateTeamPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewerService_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewerServiceServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReviewerService_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewerServiceServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewerService_DeactivateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeactivateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewerServiceServer).DeactivateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReviewerService_DeactivateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewerServiceServer).DeactivateUser(ctx, req.(*DeactivateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewerService_MassDeactivateTeamUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MassDeactivateTeamUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewerServiceServer).MassDeactivateTeamUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReviewerService_MassDeactivateTeamUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewerServiceServer).MassDeactivateTeamUsers(ctx, req.(*MassDeactivateTeamUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewerService_CreatePR_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePRRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewerServiceServer).CreatePR(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReviewerService_CreatePR_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewerServiceServer).CreatePR(ctx, req.(*CreatePRRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewerService_GetPR_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPRRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewerServiceServer).GetPR(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReviewerService_GetPR_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewerServiceServer).GetPR(ctx, req.(*GetPRRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewerService_MergePR_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergePRRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewerServiceServer).MergePR(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReviewerService_MergePR_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewerServiceServer).MergePR(ctx, req.(*MergePRRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewerService_GetPRHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPRHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewerServiceServer).GetPRHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReviewerService_GetPRHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewerServiceServer).GetPRHistory(ctx, req.(*GetPRHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewerService_ListReviewerPRs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReviewerPRsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewerServiceServer).ListReviewerPRs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReviewerService_ListReviewerPRs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewerServiceServer).ListReviewerPRs(ctx, req.(*ListReviewerPRsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewerService_RerollReviewer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RerollReviewerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewerServiceServer).RerollReviewer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReviewerService_RerollReviewer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewerServiceServer).RerollReviewer(ctx, req.(*RerollReviewerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewerService_AddReviewer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddReviewerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewerServiceServer).AddReviewer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReviewerService_AddReviewer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewerServiceServer).AddReviewer(ctx, req.(*AddReviewerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewerService_RemoveReviewer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveReviewerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewerServiceServer).RemoveReviewer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReviewerService_RemoveReviewer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewerServiceServer).RemoveReviewer(ctx, req.(*RemoveReviewerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewerService_GetReviewerStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReviewerStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewerServiceServer).GetReviewerStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReviewerService_GetReviewerStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewerServiceServer).GetReviewerStats(ctx, req.(*GetReviewerStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ReviewerService_ServiceDesc is the grpc.ServiceDesc for ReviewerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ReviewerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "reviewer.v1.ReviewerService",
+	HandlerType: (*ReviewerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateTeam",
+			Handler:    _ReviewerService_CreateTeam_Handler,
+		},
+		{
+			MethodName: "GetTeamPolicy",
+			Handler:    _ReviewerService_GetTeamPolicy_Handler,
+		},
+		{
+			MethodName: "UpdateTeamPolicy",
+			Handler:    _ReviewerService_UpdateTeamPolicy_Handler,
+		},
+		{
+			MethodName: "CreateUser",
+			Handler:    _ReviewerService_CreateUser_Handler,
+		},
+		{
+			MethodName: "DeactivateUser",
+			Handler:    _ReviewerService_DeactivateUser_Handler,
+		},
+		{
+			MethodName: "MassDeactivateTeamUsers",
+			Handler:    _ReviewerService_MassDeactivateTeamUsers_Handler,
+		},
+		{
+			MethodName: "CreatePR",
+			Handler:    _ReviewerService_CreatePR_Handler,
+		},
+		{
+			MethodName: "GetPR",
+			Handler:    _ReviewerService_GetPR_Handler,
+		},
+		{
+			MethodName: "MergePR",
+			Handler:    _ReviewerService_MergePR_Handler,
+		},
+		{
+			MethodName: "GetPRHistory",
+			Handler:    _ReviewerService_GetPRHistory_Handler,
+		},
+		{
+			MethodName: "ListReviewerPRs",
+			Handler:    _ReviewerService_ListReviewerPRs_Handler,
+		},
+		{
+			MethodName: "RerollReviewer",
+			Handler:    _ReviewerService_RerollReviewer_Handler,
+		},
+		{
+			MethodName: "AddReviewer",
+			Handler:    _ReviewerService_AddReviewer_Handler,
+		},
+		{
+			MethodName: "RemoveReviewer",
+			Handler:    _ReviewerService_RemoveReviewer_Handler,
+		},
+		{
+			MethodName: "GetReviewerStats",
+			Handler:    _ReviewerService_GetReviewerStats_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "reviewer/v1/reviewer.proto",
+}